@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// abstractMembers holds the Go types of the implementations of an interface or the
+// members of a union. Their object types are generated on first use from the shared
+// type registry, so a member type reached elsewhere in the schema is not duplicated.
+type abstractMembers struct {
+	goTypes []reflect.Type
+
+	once     sync.Once
+	objects  []*graphql.Object
+	byGoType map[reflect.Type]*graphql.Object
+}
+
+// newAbstractMembers returns the members given as values of their Go struct types
+func newAbstractMembers(kind, name string, values []interface{}) *abstractMembers {
+	members := &abstractMembers{}
+	for _, value := range values {
+		t := reflect.TypeOf(value)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("%s %s: member %T is not a struct", kind, name, value))
+		}
+		members.goTypes = append(members.goTypes, t)
+	}
+	return members
+}
+
+// init generates the object types of the members
+func (m *abstractMembers) init() {
+	m.once.Do(func() {
+		gen := NewFieldGenerator[any]()
+		m.byGoType = make(map[reflect.Type]*graphql.Object, len(m.goTypes))
+		for _, t := range m.goTypes {
+			if object, ok := gen.getBaseGraphQLType(t, nil).(*graphql.Object); ok {
+				m.objects = append(m.objects, object)
+				m.byGoType[t] = object
+			}
+		}
+	})
+}
+
+// objectTypes returns the object types of the members
+func (m *abstractMembers) objectTypes() []*graphql.Object {
+	m.init()
+	return m.objects
+}
+
+// objectOf returns the object type of the member whose Go type is the type of value
+func (m *abstractMembers) objectOf(value interface{}) *graphql.Object {
+	m.init()
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return m.byGoType[t]
+}
+
+// sharedFields returns the fields declared by every member with the same type,
+// which become the fields of an interface
+func (m *abstractMembers) sharedFields() graphql.Fields {
+	objects := m.objectTypes()
+	if len(objects) == 0 {
+		return graphql.Fields{}
+	}
+
+	fields := graphql.Fields{}
+	for name, def := range objects[0].Fields() {
+		args := graphql.FieldConfigArgument{}
+		for _, arg := range def.Args {
+			args[arg.Name()] = &graphql.ArgumentConfig{
+				Type:         arg.Type,
+				DefaultValue: arg.DefaultValue,
+				Description:  arg.Description(),
+			}
+		}
+		fields[name] = &graphql.Field{
+			Type:              def.Type,
+			Args:              args,
+			Description:       def.Description,
+			DeprecationReason: def.DeprecationReason,
+		}
+	}
+	for _, object := range objects[1:] {
+		defs := object.Fields()
+		for name, field := range fields {
+			if def, ok := defs[name]; !ok || def.Type.String() != field.Type.String() {
+				delete(fields, name)
+			}
+		}
+	}
+	return fields
+}
+
+// resolveTypeFn dispatches on the Go type of the value, after resolveType if set
+func (m *abstractMembers) resolveTypeFn(resolveType func(value interface{}) *graphql.Object) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		if resolveType != nil {
+			if object := resolveType(p.Value); object != nil {
+				return object
+			}
+		}
+		return m.objectOf(p.Value)
+	}
+}
+
+// Global interface and union registry used by the type generators
+var (
+	interfacesByType        = make(map[reflect.Type]*graphql.Interface)
+	interfacesByImplementer = make(map[reflect.Type][]*graphql.Interface)
+	interfaceMembers        = make(map[*graphql.Interface]*abstractMembers)
+	unionsByName            = make(map[string]*graphql.Union)
+	abstractRegistry        sync.RWMutex
+)
+
+// RegisterInterface maps the Go interface type T to a GraphQL interface. Struct fields
+// of type T (or []T) use it, and the object types of the implementations, given as
+// values of their Go types, declare it. The interface has the fields that all
+// implementations share.
+//
+// resolveType returns the object type of a resolved value; when it is nil or returns
+// nil, the value's Go type selects the implementation. ObjectTypeOf returns the object
+// type generated for a Go type.
+//
+// Example:
+//
+//	type FeedEntry interface{ isFeedEntry() }
+//
+//	graph.RegisterInterface[FeedEntry]("FeedEntry", nil, Message{}, StatusEvent{})
+//
+//	type Feed struct {
+//	    Entries []FeedEntry `json:"entries"` // [FeedEntry]
+//	}
+func RegisterInterface[T any](name string, resolveType func(value interface{}) *graphql.Object, implementations ...interface{}) *graphql.Interface {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	abstractRegistry.Lock()
+	defer abstractRegistry.Unlock()
+	if existing, ok := interfacesByType[t]; ok {
+		return existing
+	}
+
+	members := newAbstractMembers("interface", name, implementations)
+	iface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: name,
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return members.sharedFields()
+		}),
+		ResolveType: members.resolveTypeFn(resolveType),
+	})
+
+	interfacesByType[t] = iface
+	interfaceMembers[iface] = members
+	for _, implementer := range members.goTypes {
+		interfacesByImplementer[implementer] = append(interfacesByImplementer[implementer], iface)
+	}
+	return iface
+}
+
+// RegisterUnion registers a GraphQL union of the given member types, given as values
+// of their Go types. Struct fields whose type is a Go interface named like the union
+// (or a slice of it) use the union, and the value's Go type selects the member.
+//
+// Example:
+//
+//	type FeedItem interface{}
+//
+//	graph.RegisterUnion("FeedItem", Message{}, StatusEvent{})
+//
+//	type Feed struct {
+//	    Items []FeedItem `json:"items"` // [FeedItem]
+//	}
+func RegisterUnion(name string, types ...interface{}) *graphql.Union {
+	abstractRegistry.Lock()
+	defer abstractRegistry.Unlock()
+	if existing, ok := unionsByName[name]; ok {
+		return existing
+	}
+
+	members := newAbstractMembers("union", name, types)
+	union := graphql.NewUnion(graphql.UnionConfig{
+		Name: name,
+		Types: (graphql.UnionTypesThunk)(func() []*graphql.Object {
+			return members.objectTypes()
+		}),
+		ResolveType: members.resolveTypeFn(nil),
+	})
+
+	unionsByName[name] = union
+	return union
+}
+
+// ObjectTypeOf returns the object type generated for the Go struct type T, shared
+// with every field of that type. Use it in the resolveType function of an interface.
+func ObjectTypeOf[T any]() *graphql.Object {
+	object, _ := NewFieldGenerator[T]().getBaseGraphQLType(reflect.TypeOf((*T)(nil)).Elem(), nil).(*graphql.Object)
+	return object
+}
+
+// lookupAbstractByType returns the interface or union used for fields of the Go
+// interface type t
+func lookupAbstractByType(t reflect.Type) graphql.Output {
+	if t.Kind() != reflect.Interface {
+		return nil
+	}
+
+	abstractRegistry.RLock()
+	defer abstractRegistry.RUnlock()
+	if iface, ok := interfacesByType[t]; ok {
+		return iface
+	}
+	if t.Name() != "" {
+		if union, ok := unionsByName[t.Name()]; ok {
+			return union
+		}
+	}
+	return nil
+}
+
+// interfacesOf returns the interfaces the object type of the Go type t implements
+func interfacesOf(t reflect.Type) graphql.InterfacesThunk {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return func() []*graphql.Interface {
+		abstractRegistry.RLock()
+		defer abstractRegistry.RUnlock()
+		return interfacesByImplementer[t]
+	}
+}
+
+// interfaceImplementations returns the implementations of the registered interfaces in
+// the schema that are missing from it. Implementations are only reachable through
+// their object types, so the schema builder adds them as extra types.
+func interfaceImplementations(schema graphql.Schema) []graphql.Type {
+	typeMap := schema.TypeMap()
+	var used []*abstractMembers
+	abstractRegistry.RLock()
+	for iface, members := range interfaceMembers {
+		if typeMap[iface.Name()] == iface {
+			used = append(used, members)
+		}
+	}
+	abstractRegistry.RUnlock()
+
+	var missing []graphql.Type
+	seen := make(map[string]bool)
+	for _, members := range used {
+		for _, object := range members.objectTypes() {
+			if _, ok := typeMap[object.Name()]; !ok && !seen[object.Name()] {
+				seen[object.Name()] = true
+				missing = append(missing, object)
+			}
+		}
+	}
+	return missing
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type AbstractTestEntry interface {
+	isAbstractTestEntry()
+}
+
+type AbstractTestItem interface{}
+
+type AbstractTestMessage struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+func (AbstractTestMessage) isAbstractTestEntry() {}
+
+type AbstractTestStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (*AbstractTestStatus) isAbstractTestEntry() {}
+
+func TestInterfaceAndUnionFields(t *testing.T) {
+	type AbstractTestFeed struct {
+		Pinned  AbstractTestMessage `json:"pinned"`
+		Entries []AbstractTestEntry `json:"entries"`
+		Items   []AbstractTestItem  `json:"items"`
+	}
+
+	RegisterInterface[AbstractTestEntry]("AbstractTestEntry", nil, AbstractTestMessage{}, AbstractTestStatus{})
+	RegisterUnion("AbstractTestItem", AbstractTestMessage{}, AbstractTestStatus{})
+
+	field := NewResolver[AbstractTestFeed]("abstractTestFeed").
+		WithResolver(func(p ResolveParams) (*AbstractTestFeed, error) {
+			return &AbstractTestFeed{
+				Pinned:  AbstractTestMessage{ID: "1", Text: "welcome"},
+				Entries: []AbstractTestEntry{AbstractTestMessage{ID: "2", Text: "hi"}, &AbstractTestStatus{ID: "3", Status: "online"}},
+				Items:   []AbstractTestItem{&AbstractTestStatus{ID: "4", Status: "away"}, AbstractTestMessage{ID: "5", Text: "bye"}},
+			}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	iface, ok := schema.Type("AbstractTestEntry").(*graphql.Interface)
+	if !ok {
+		t.Fatalf("Expected AbstractTestEntry interface, got %T", schema.Type("AbstractTestEntry"))
+	}
+	if _, ok := iface.Fields()["id"]; !ok || len(iface.Fields()) != 1 {
+		t.Errorf("Expected the interface to have the shared id field, got %v", iface.Fields())
+	}
+	if got := len(schema.PossibleTypes(iface)); got != 2 {
+		t.Errorf("Expected 2 implementations, got %d", got)
+	}
+	if _, ok := schema.Type("AbstractTestItem").(*graphql.Union); !ok {
+		t.Fatalf("Expected AbstractTestItem union, got %T", schema.Type("AbstractTestItem"))
+	}
+	if schema.Type("AbstractTestMessage") != ObjectTypeOf[AbstractTestMessage]() {
+		t.Error("Expected the member type to be shared with the pinned field")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			abstractTestFeed {
+				pinned { id }
+				entries {
+					__typename
+					id
+					... on AbstractTestMessage { text }
+					... on AbstractTestStatus { status }
+				}
+				items {
+					__typename
+					... on AbstractTestMessage { text }
+					... on AbstractTestStatus { status }
+				}
+			}
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	got, _ := json.Marshal(result.Data)
+	want := `{"abstractTestFeed":{` +
+		`"entries":[{"__typename":"AbstractTestMessage","id":"2","text":"hi"},{"__typename":"AbstractTestStatus","id":"3","status":"online"}],` +
+		`"items":[{"__typename":"AbstractTestStatus","status":"away"},{"__typename":"AbstractTestMessage","text":"bye"}],` +
+		`"pinned":{"id":"1"}}}`
+	if string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	// Implementations reachable only through the interface are added by the builder
+	type AbstractTestEntries struct {
+		Entries []AbstractTestEntry `json:"entries"`
+	}
+	entriesSchema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{
+			NewResolver[AbstractTestEntries]("abstractTestEntries").
+				WithResolver(func(p ResolveParams) (*AbstractTestEntries, error) {
+					return &AbstractTestEntries{Entries: []AbstractTestEntry{&AbstractTestStatus{ID: "6", Status: "busy"}}}, nil
+				}).
+				BuildQuery(),
+		},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	result = graphql.Do(graphql.Params{
+		Schema:        entriesSchema,
+		RequestString: `{ abstractTestEntries { entries { id ... on AbstractTestStatus { status } } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	got, _ = json.Marshal(result.Data)
+	if want := `{"abstractTestEntries":{"entries":[{"id":"6","status":"busy"}]}}`; string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
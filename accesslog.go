@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// accessLogWriter records the status code and size of a response
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *accessLogWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// accessLogEntry collects the fields of a single access log line
+type accessLogEntry struct {
+	method        string
+	path          string
+	operationName string
+	userID        string
+	start         time.Time
+}
+
+// logAccess writes the access log line for a completed request
+func logAccess(ctx context.Context, logger *slog.Logger, entry *accessLogEntry, w *accessLogWriter) {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "graphql request",
+		slog.String("method", entry.method),
+		slog.String("path", entry.path),
+		slog.String("operation", entry.operationName),
+		slog.String("user_id", entry.userID),
+		slog.Int("status", status),
+		slog.Int("bytes", w.bytes),
+		slog.Duration("duration", time.Since(entry.start)),
+	)
+}
+
+// operationName returns the operation name of a request: the explicit operationName
+// if given, otherwise the name of the first named operation in the query
+func operationName(payload requestPayload) string {
+	if payload.OperationName != "" || payload.Query == "" {
+		return payload.OperationName
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: payload.Query})
+	if err != nil {
+		return ""
+	}
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Name != nil {
+			return op.Name.Value
+		}
+	}
+	return ""
+}
+
+// userIDFromDetails extracts a user id from the details returned by UserDetailsFn.
+// Details implementing HasIDInterface or maps with an "id" key are supported.
+func userIDFromDetails(details interface{}) string {
+	switch d := details.(type) {
+	case HasIDInterface:
+		return d.GetID()
+	case map[string]interface{}:
+		if id, ok := d["id"]; ok && id != nil {
+			return fmt.Sprint(id)
+		}
+	}
+	return ""
+}
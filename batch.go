@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/handler"
+)
+
+// readBatchPayload returns the operations of a batched POST request, whose JSON body
+// is an array of operations. It returns false for any other request. The request
+// body is restored.
+func readBatchPayload(r *http.Request) ([]requestPayload, bool, error) {
+	if r.Method != http.MethodPost || r.Body == nil || isMultipartRequest(r) ||
+		strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql") ||
+		r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		return nil, false, nil
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil || !bytes.HasPrefix(bytes.TrimSpace(bodyBytes), []byte("[")) {
+		return nil, false, nil
+	}
+
+	var operations []struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal(bodyBytes, &operations); err != nil {
+		return nil, true, fmt.Errorf("invalid batch request: %w", err)
+	}
+	payloads := make([]requestPayload, len(operations))
+	for i, operation := range operations {
+		payloads[i] = requestPayload(operation)
+	}
+	return payloads, true, nil
+}
+
+// serveBatch executes the operations of a batched request and writes their results
+// as an array in the same order. Every operation is validated on its own: one that
+// fails validation gets its errors in place of a result, and the others still run.
+// Batches larger than MaxBatchSize are rejected with 400 Bad Request.
+func serveBatch(w http.ResponseWriter, r *http.Request, graphCtx *GraphContext, schema *graphql.Schema, rootObjectFn handler.RootObjectFn, userDetails interface{}, operations []requestPayload) {
+	if len(operations) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "batch request must contain at least one operation")
+		return
+	}
+	if graphCtx.MaxBatchSize > 0 && len(operations) > graphCtx.MaxBatchSize {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("batch of %d operations exceeds the maximum of %d", len(operations), graphCtx.MaxBatchSize))
+		return
+	}
+
+	if graphCtx.EchoCostHeader {
+		setBatchCostHeader(w, graphCtx, schema, operations)
+	}
+
+	results := make([]interface{}, len(operations))
+	for i, operation := range operations {
+		results[i] = executeBatchOperation(r, graphCtx, schema, rootObjectFn, userDetails, operation)
+	}
+
+	var body []byte
+	if graphCtx.Pretty {
+		body, _ = json.MarshalIndent(results, "", "\t")
+	} else {
+		body, _ = json.Marshal(results)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// setBatchCostHeader sets the X-GraphQL-Cost header to the total estimated cost of
+// the batched operations. Operations that cannot be estimated count as zero.
+func setBatchCostHeader(w http.ResponseWriter, graphCtx *GraphContext, schema *graphql.Schema, operations []requestPayload) {
+	total := 0
+	for _, operation := range operations {
+		if cost, ok := queryCost(graphCtx, schema, operation.Query, operation.Variables); ok {
+			total += cost
+		}
+	}
+	w.Header().Set(costHeader, strconv.Itoa(total))
+}
+
+// executeBatchOperation validates and executes one operation of a batched request.
+// Each operation gets its own raw variables and field contexts.
+func executeBatchOperation(r *http.Request, graphCtx *GraphContext, schema *graphql.Schema, rootObjectFn handler.RootObjectFn, userDetails interface{}, operation requestPayload) interface{} {
+	ctx := WithFieldContexts(r.Context())
+	if operation.Variables != nil {
+		ctx = WithRawVariables(ctx, operation.Variables)
+	}
+	r = r.WithContext(ctx)
+
+	var warnings []*ValidationError
+	if !graphCtx.DEBUG {
+		var err error
+		if warnings, err = validateQuery(r, graphCtx, schema, operation.Query, userDetails); err != nil {
+			return validationErrorResponse(err, graphCtx.ErrorFormatter)
+		}
+	}
+
+	params := &graphql.Params{
+		Schema:         *schema,
+		RequestString:  operation.Query,
+		VariableValues: operation.Variables,
+		OperationName:  operation.OperationName,
+	}
+	r = beforeExecute(graphCtx, r, params)
+	params.RootObject = rootObjectFn(r.Context(), r)
+	params.Context = r.Context()
+
+	result := graphql.Do(*params)
+	formatResultErrors(result.Errors)
+	if len(warnings) > 0 {
+		if result.Extensions == nil {
+			result.Extensions = make(map[string]interface{})
+		}
+		result.Extensions["warnings"] = formatWarnings(warnings)
+	}
+	if graphCtx.AfterExecute != nil {
+		graphCtx.AfterExecute(r.Context(), result)
+	}
+	if graphCtx.OmitNullFields {
+		result.Data = omitNulls(result.Data)
+	}
+
+	sanitize := !graphCtx.DEBUG && graphCtx.EnableSanitization
+	if graphCtx.ErrorFormatter != nil && len(result.Errors) > 0 {
+		formatted := formatErrorsWith(graphCtx.ErrorFormatter, result.Errors)
+		if sanitize {
+			for _, entry := range formatted {
+				if message, ok := entry["message"].(string); ok {
+					entry["message"] = sanitizeMessage(message)
+				}
+			}
+		}
+		response := map[string]interface{}{"data": result.Data}
+		if len(formatted) > 0 {
+			response["errors"] = formatted
+		}
+		if len(result.Extensions) > 0 {
+			response["extensions"] = result.Extensions
+		}
+		return response
+	}
+
+	if sanitize {
+		for i := range result.Errors {
+			result.Errors[i].Message = sanitizeMessage(result.Errors[i].Message)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTP_BatchedRequests(t *testing.T) {
+	newHandler := func(maxBatchSize int) http.HandlerFunc {
+		return NewHTTP(&GraphContext{
+			SchemaParams: &SchemaBuilderParams{
+				QueryFields:    []QueryField{getDefaultHelloQuery()},
+				MutationFields: []MutationField{getDefaultEchoMutation()},
+			},
+			ValidationRules:    []ValidationRule{NewNoIntrospectionRule()},
+			EnableSanitization: true,
+			MaxBatchSize:       maxBatchSize,
+		})
+	}
+	post := func(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	t.Run("results are returned in order", func(t *testing.T) {
+		w := post(newHandler(0), `[
+			{"query":"{ hello }"},
+			{"query":"{ __schema { queryType { name } } }"},
+			{"query":"mutation Echo($message: String!) { echo(message: $message) }","variables":{"message":"hi"}},
+			{"query":"{ helo }"}
+		]`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var results []struct {
+			Data   map[string]interface{}   `json:"data"`
+			Errors []map[string]interface{} `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+			t.Fatalf("Expected a JSON array, got %s", w.Body.String())
+		}
+		if len(results) != 4 {
+			t.Fatalf("Expected 4 results, got %d", len(results))
+		}
+		if results[0].Data["hello"] != "Hello world" || len(results[0].Errors) > 0 {
+			t.Errorf("Unexpected first result %+v", results[0])
+		}
+		if len(results[1].Errors) == 0 || results[1].Errors[0]["rule"] != "NoIntrospectionRule" || results[1].Data != nil {
+			t.Errorf("Expected the introspection query to fail validation, got %+v", results[1])
+		}
+		if results[2].Data["echo"] != "hi" {
+			t.Errorf("Expected the mutation to echo its variable, got %+v", results[2])
+		}
+		if len(results[3].Errors) == 0 {
+			t.Fatalf("Expected an error for the unknown field, got %+v", results[3])
+		}
+		if message := results[3].Errors[0]["message"].(string); strings.Contains(message, "Did you mean") {
+			t.Errorf("Expected the error message to be sanitized, got %q", message)
+		}
+	})
+
+	t.Run("oversized batch is rejected", func(t *testing.T) {
+		w := post(newHandler(2), `[{"query":"{ hello }"},{"query":"{ hello }"},{"query":"{ hello }"}]`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "exceeds the maximum of 2") {
+			t.Errorf("Unexpected response %s", w.Body.String())
+		}
+	})
+
+	t.Run("single operation is unchanged", func(t *testing.T) {
+		w := post(newHandler(2), `{"query":"{ hello }"}`)
+		if w.Code != http.StatusOK || !strings.HasPrefix(strings.TrimSpace(w.Body.String()), "{") {
+			t.Fatalf("Expected a single result, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestNewHTTP_BatchedCostHeader(t *testing.T) {
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{getDefaultHelloQuery()},
+		},
+		EchoCostHeader: true,
+	})
+	cost := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		n, err := strconv.Atoi(w.Header().Get("X-GraphQL-Cost"))
+		if err != nil {
+			t.Fatalf("Expected a numeric cost header, got %q", w.Header().Get("X-GraphQL-Cost"))
+		}
+		return n
+	}
+
+	single := cost(`{"query":"{ hello }"}`)
+	if batch := cost(`[{"query":"{ hello }"},{"query":"{ hello }"}]`); batch != 2*single {
+		t.Errorf("Expected the batch cost to be %d, got %d", 2*single, batch)
+	}
+}
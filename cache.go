@@ -0,0 +1,210 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// FieldCache stores resolved field values by key. It backs WithCachedField,
+// CachedFieldResolver and CacheMiddleware and can be invalidated, so mutations
+// can bust cached reads. It is safe for concurrent use.
+//
+// Example:
+//
+//	userQuery := graph.NewResolver[User]("user").
+//	    WithCachedField("stats", statsKey, statsResolver)
+//	statsCache := userQuery.FieldCache("stats")
+//
+//	updateStats := graph.NewResolver[Stats]("updateStats").
+//	    WithResolver(func(p graph.ResolveParams) (*Stats, error) {
+//	        stats, err := statsService.Update(p.Context, p.Args)
+//	        statsCache.InvalidatePrefix("user:")
+//	        return stats, err
+//	    }).
+//	    BuildMutation()
+type FieldCache struct {
+	mu      sync.RWMutex
+	entries map[string]interface{}
+}
+
+// NewFieldCache creates an empty field cache.
+func NewFieldCache() *FieldCache {
+	return &FieldCache{entries: make(map[string]interface{})}
+}
+
+// Get returns the cached value for key.
+func (c *FieldCache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+// Set stores a value for key.
+func (c *FieldCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+}
+
+// Invalidate removes the value cached for key; the next read recomputes it.
+func (c *FieldCache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// InvalidatePrefix removes every value whose key starts with prefix.
+func (c *FieldCache) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Clear removes all cached values.
+func (c *FieldCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]interface{})
+}
+
+// Resolver wraps resolver so successful results are cached under cacheKey(p).
+func (c *FieldCache) Resolver(cacheKey func(graphql.ResolveParams) string, resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		key := cacheKey(p)
+		if cached, exists := c.Get(key); exists {
+			return cached, nil
+		}
+
+		result, err := resolver(p)
+		if err == nil {
+			c.Set(key, result)
+		}
+		return result, err
+	}
+}
+
+// Middleware caches successful resolver results under cacheKey(p).
+func (c *FieldCache) Middleware(cacheKey func(ResolveParams) string) FieldMiddleware {
+	return func(next FieldResolveFn) FieldResolveFn {
+		return func(p ResolveParams) (interface{}, error) {
+			key := cacheKey(p)
+			if cached, exists := c.Get(key); exists {
+				return cached, nil
+			}
+			result, err := next(p)
+			if err == nil {
+				c.Set(key, result)
+			}
+			return result, err
+		}
+	}
+}
+
+// RequestCache holds the field values cached by WithRequestCachedField for a single
+// request, so cached values never leak to other requests or users. NewHTTP installs
+// one into every request context. It is safe for concurrent use.
+type RequestCache struct {
+	mu      sync.Mutex
+	entries map[string]requestCacheEntry
+}
+
+// requestCacheEntry is a cached value with its expiry; a zero expiry never expires
+type requestCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewRequestCache creates an empty request cache.
+func NewRequestCache() *RequestCache {
+	return &RequestCache{entries: make(map[string]requestCacheEntry)}
+}
+
+// get returns the value cached for key, unless it has expired
+func (c *RequestCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set caches value for key; a positive ttl makes it expire
+func (c *RequestCache) set(key string, value interface{}, ttl time.Duration) {
+	entry := requestCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// requestCacheKey is the context key for the request's RequestCache
+type requestCacheKey struct{}
+
+// WithRequestCache returns a copy of ctx that carries the request cache.
+// NewHTTP does this for every request; use it directly when executing queries
+// with graphql.Do.
+func WithRequestCache(ctx context.Context, cache *RequestCache) context.Context {
+	return context.WithValue(ctx, requestCacheKey{}, cache)
+}
+
+// requestCacheFromContext returns the request cache of ctx, or nil
+func requestCacheFromContext(ctx context.Context) *RequestCache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(requestCacheKey{}).(*RequestCache)
+	return cache
+}
+
+// WithRequestCachedField caches the results of resolver for fieldName in the request
+// cache under keyFn(p), so rows of a request sharing a key resolve it once. Entries
+// expire after ttl, or live as long as the request when ttl is 0. Unlike
+// WithCachedField nothing is shared between requests. Without a request cache in the
+// context (see WithRequestCache) the field resolves uncached.
+//
+// Example:
+//
+//	NewResolver[[]Order]("orders").
+//	    AsList().
+//	    WithRequestCachedField("customer", func(p graphql.ResolveParams) string {
+//	        return fmt.Sprintf("customer:%d", p.Source.(Order).CustomerID)
+//	    }, time.Minute, resolveCustomer).
+//	    WithResolver(listOrders).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) WithRequestCachedField(fieldName string, keyFn func(graphql.ResolveParams) string, ttl time.Duration, resolver graphql.FieldResolveFn) *UnifiedResolver[T] {
+	prefix := r.objectName + "." + fieldName + ":"
+	r.fieldOverrides[fieldName] = func(p graphql.ResolveParams) (interface{}, error) {
+		cache := requestCacheFromContext(p.Context)
+		if cache == nil {
+			return resolver(p)
+		}
+
+		key := prefix + keyFn(p)
+		if cached, exists := cache.get(key); exists {
+			return cached, nil
+		}
+		result, err := resolver(p)
+		if err == nil {
+			cache.set(key, result, ttl)
+		}
+		return result, err
+	}
+	return r
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultCompressionThreshold is the smallest response body compressed by default
+const defaultCompressionThreshold = 1024
+
+// compressResponseWriter buffers a response and compresses it when it is written out,
+// if the body is at least threshold bytes. It wraps the writers used for sanitization
+// and warnings, so it compresses the bytes they produce.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding   string
+	threshold  int
+	body       bytes.Buffer
+	statusCode int
+}
+
+// newCompressResponseWriter returns a writer compressing the response with the
+// encoding preferred by the client, or nil when the client accepts neither gzip nor
+// deflate. The response varies with Accept-Encoding in both cases.
+func newCompressResponseWriter(w http.ResponseWriter, r *http.Request, threshold int) *compressResponseWriter {
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		return nil
+	}
+	if threshold <= 0 {
+		threshold = defaultCompressionThreshold
+	}
+	return &compressResponseWriter{
+		ResponseWriter: w,
+		encoding:       encoding,
+		threshold:      threshold,
+		statusCode:     http.StatusOK,
+	}
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *compressResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+// Close writes the buffered response to the original writer, compressed when it is
+// large enough and not already encoded
+func (w *compressResponseWriter) Close() {
+	body := w.body.Bytes()
+	header := w.ResponseWriter.Header()
+	if len(body) < w.threshold || header.Get("Content-Encoding") != "" {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+		_, _ = w.ResponseWriter.Write(body)
+		return
+	}
+
+	var compressed bytes.Buffer
+	var encoder io.WriteCloser
+	if w.encoding == "gzip" {
+		encoder = gzip.NewWriter(&compressed)
+	} else {
+		encoder = zlib.NewWriter(&compressed)
+	}
+	_, _ = encoder.Write(body)
+	_ = encoder.Close()
+
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, _ = w.ResponseWriter.Write(compressed.Bytes())
+}
+
+// negotiateEncoding returns the response encoding for an Accept-Encoding header:
+// gzip when accepted, otherwise deflate, otherwise an empty string. Encodings with
+// q=0 are refused. "*" accepts the encodings the header doesn't list, so
+// "*, gzip;q=0" selects deflate.
+func negotiateEncoding(acceptEncoding string) string {
+	// accepted holds the listed encodings, false for those refused with q=0
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		accepted[name] = true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				accepted[name] = false
+			}
+		}
+	}
+
+	acceptable := func(encoding string) bool {
+		if explicit, listed := accepted[encoding]; listed {
+			return explicit
+		}
+		return accepted["*"]
+	}
+	switch {
+	case acceptable("gzip"):
+		return "gzip"
+	case acceptable("deflate"):
+		return "deflate"
+	}
+	return ""
+}
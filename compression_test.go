@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type CompressionTestItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNewHTTP_Compression(t *testing.T) {
+	items := NewResolver[[]CompressionTestItem]("compressionTestItems").
+		AsList().
+		WithResolver(func(p ResolveParams) (*[]CompressionTestItem, error) {
+			items := make([]CompressionTestItem, 200)
+			for i := range items {
+				items[i] = CompressionTestItem{ID: i, Name: "item"}
+			}
+			return &items, nil
+		}).
+		BuildQuery()
+
+	newHandler := func(enable bool) http.HandlerFunc {
+		return NewHTTP(&GraphContext{
+			SchemaParams:       &SchemaBuilderParams{QueryFields: []QueryField{getDefaultHelloQuery(), items}},
+			EnableCompression:  enable,
+			EnableSanitization: true,
+		})
+	}
+	request := func(handler http.HandlerFunc, query, acceptEncoding string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+		t.Helper()
+		var reader io.Reader = w.Body
+		switch w.Header().Get("Content-Encoding") {
+		case "gzip":
+			gz, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatalf("Invalid gzip body: %v", err)
+			}
+			reader = gz
+		case "deflate":
+			zr, err := zlib.NewReader(w.Body)
+			if err != nil {
+				t.Fatalf("Invalid deflate body: %v", err)
+			}
+			reader = zr
+		}
+		var result map[string]interface{}
+		if err := json.NewDecoder(reader).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name           string
+		enable         bool
+		query          string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{name: "gzip", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "gzip, deflate", wantEncoding: "gzip"},
+		{name: "deflate", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "deflate, gzip;q=0", wantEncoding: "deflate"},
+		{name: "wildcard", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "*", wantEncoding: "gzip"},
+		{name: "wildcard with gzip refused", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "gzip;q=0, *", wantEncoding: "deflate"},
+		{name: "wildcard refused", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "*;q=0"},
+		{name: "below threshold", enable: true, query: "{ hello }", acceptEncoding: "gzip"},
+		{name: "not accepted", enable: true, query: "{ compressionTestItems { id name } }", acceptEncoding: "br"},
+		{name: "disabled", enable: false, query: "{ compressionTestItems { id name } }", acceptEncoding: "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(newHandler(tt.enable), tt.query, tt.acceptEncoding)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			if vary := strings.Join(w.Header().Values("Vary"), ","); tt.enable != strings.Contains(vary, "Accept-Encoding") {
+				t.Errorf("Unexpected Vary header %q", vary)
+			}
+			if result := decode(t, w); result["data"] == nil {
+				t.Errorf("Expected data in the response, got %v", result)
+			}
+		})
+	}
+
+	t.Run("compresses the sanitized body", func(t *testing.T) {
+		handler := NewHTTP(&GraphContext{
+			SchemaParams:         &SchemaBuilderParams{QueryFields: []QueryField{getDefaultHelloQuery()}},
+			EnableCompression:    true,
+			CompressionThreshold: 1,
+			EnableSanitization:   true,
+		})
+		w := request(handler, "{ helo }", "gzip")
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Expected a gzip response, got %q", w.Header().Get("Content-Encoding"))
+		}
+		result := decode(t, w)
+		errs, _ := result["errors"].([]interface{})
+		if len(errs) == 0 {
+			t.Fatalf("Expected an error, got %v", result)
+		}
+		if message := errs[0].(map[string]interface{})["message"].(string); strings.Contains(message, "Did you mean") {
+			t.Errorf("Expected the error message to be sanitized, got %q", message)
+		}
+	})
+}
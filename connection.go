@@ -0,0 +1,381 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+)
+
+// CursorEdge is an item of a Relay connection with its opaque cursor
+type CursorEdge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// CursorConnection is a Relay-style cursor connection returned by resolvers configured
+// with AsConnection. Its GraphQL type also exposes hasContent and isEmpty, computed
+// from the edges of the page, like the flags of pageable responses.
+type CursorConnection[T any] struct {
+	Edges      []CursorEdge[T] `json:"edges"`
+	PageInfo   PageInfo        `json:"pageInfo"`
+	TotalCount int             `json:"totalCount"`
+}
+
+// HasContent reports whether the page has at least one edge
+func (c *CursorConnection[T]) HasContent() bool {
+	return len(c.Edges) > 0
+}
+
+// IsEmpty reports whether the page has no edges
+func (c *CursorConnection[T]) IsEmpty() bool {
+	return len(c.Edges) == 0
+}
+
+// EncodeConnectionCursor returns the opaque cursor of a stable item key
+func EncodeConnectionCursor(key string) string {
+	return base64.StdEncoding.EncodeToString([]byte(key))
+}
+
+// DecodeConnectionCursor returns the item key encoded in a cursor
+func DecodeConnectionCursor(cursor string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return string(key), nil
+}
+
+// NewConnectionFromSlice builds the page of a connection selected by args from the
+// complete, ordered result set. key returns a stable key for an item (such as its
+// primary key) that is encoded into its cursor, so cursors keep pointing at the same
+// item when rows are inserted or removed. A cursor whose item is no longer in items
+// is ignored, as allowed by the Relay specification.
+//
+// HasPreviousPage and HasNextPage report whether items exist before and after the page.
+//
+// Example:
+//
+//	return graph.NewConnectionFromSlice(users, args, func(u User) string {
+//	    return strconv.Itoa(u.ID)
+//	})
+func NewConnectionFromSlice[T any](items []T, args PaginationArgs, key func(item T) string) (*CursorConnection[T], error) {
+	if args.First != nil && *args.First < 0 {
+		return nil, fmt.Errorf("first must be a non-negative integer, got %d", *args.First)
+	}
+	if args.Last != nil && *args.Last < 0 {
+		return nil, fmt.Errorf("last must be a non-negative integer, got %d", *args.Last)
+	}
+
+	cursors := make([]string, len(items))
+	for i, item := range items {
+		cursors[i] = EncodeConnectionCursor(key(item))
+	}
+	indexOf := func(cursor string) int {
+		for i := range cursors {
+			if cursors[i] == cursor {
+				return i
+			}
+		}
+		return -1
+	}
+
+	start, end := 0, len(items)
+	if args.After != nil {
+		if _, err := DecodeConnectionCursor(*args.After); err != nil {
+			return nil, err
+		}
+		if i := indexOf(*args.After); i >= 0 {
+			start = i + 1
+		}
+	}
+	if args.Before != nil {
+		if _, err := DecodeConnectionCursor(*args.Before); err != nil {
+			return nil, err
+		}
+		if i := indexOf(*args.Before); i >= 0 && i < end {
+			end = i
+		}
+	}
+	if end < start {
+		end = start
+	}
+	if args.First != nil && end-start > *args.First {
+		end = start + *args.First
+	}
+	if args.Last != nil && end-start > *args.Last {
+		start = end - *args.Last
+	}
+
+	connection := &CursorConnection[T]{
+		Edges:      make([]CursorEdge[T], 0, end-start),
+		TotalCount: len(items),
+		PageInfo: PageInfo{
+			HasPreviousPage: start > 0,
+			HasNextPage:     end < len(items),
+		},
+	}
+	for i := start; i < end; i++ {
+		connection.Edges = append(connection.Edges, CursorEdge[T]{Node: items[i], Cursor: cursors[i]})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	return connection, nil
+}
+
+// WithConnectionResolver sets the resolver of a connection field and configures the
+// field with AsConnection. The first/after/last/before arguments are passed as args.
+func (r *UnifiedResolver[T]) WithConnectionResolver(resolver func(p ResolveParams, args PaginationArgs) (*CursorConnection[T], error)) *UnifiedResolver[T] {
+	r.AsConnection()
+	r.resolver = func(p graphql.ResolveParams) (interface{}, error) {
+		var args PaginationArgs
+		if err := mapArgsToStruct(p.Args, &args); err != nil {
+			return nil, err
+		}
+		return resolver(ResolveParams(p), args)
+	}
+	return r
+}
+
+// withConnectionArgs returns args with the connection arguments added
+func withConnectionArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	connectionArgs := graphql.FieldConfigArgument{
+		"first":  &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of items to fetch"},
+		"after":  &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor to start after"},
+		"last":   &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of items to fetch from end"},
+		"before": &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor to start before"},
+	}
+	for name, arg := range args {
+		connectionArgs[name] = arg
+	}
+	return connectionArgs
+}
+
+// connectionSource returns the connection resolved by a connection field
+func connectionSource[T any](source interface{}) (*CursorConnection[T], bool) {
+	switch connection := source.(type) {
+	case *CursorConnection[T]:
+		return connection, connection != nil
+	case CursorConnection[T]:
+		return &connection, true
+	}
+	return nil, false
+}
+
+// generateConnectionType creates the TConnection and TEdge types of AsConnection
+func (r *UnifiedResolver[T]) generateConnectionType(scope *typeScope) *graphql.Object {
+	nodeType := r.generateObjectTypeWithOverrides(scope)
+	pageInfoType := createPageInfoType()
+
+	edgeType := scopedObjectType(scope, r.objectName+"Edge", func() *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: r.objectName + "Edge",
+			Fields: graphql.Fields{
+				"node": &graphql.Field{
+					Type: nodeType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if edge, ok := p.Source.(CursorEdge[T]); ok {
+							return edge.Node, nil
+						}
+						return nil, nil
+					},
+				},
+				"cursor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if edge, ok := p.Source.(CursorEdge[T]); ok {
+							return edge.Cursor, nil
+						}
+						return "", nil
+					},
+				},
+			},
+		})
+	})
+
+	return scopedObjectType(scope, r.objectName+"Connection", func() *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: r.objectName + "Connection",
+			Fields: graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewList(edgeType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if connection, ok := connectionSource[T](p.Source); ok {
+							return connection.Edges, nil
+						}
+						return nil, nil
+					},
+				},
+				"pageInfo": &graphql.Field{
+					Type: graphql.NewNonNull(pageInfoType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if connection, ok := connectionSource[T](p.Source); ok {
+							return connection.PageInfo, nil
+						}
+						return PageInfo{}, nil
+					},
+				},
+				"totalCount": &graphql.Field{
+					Type: graphql.Int,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if connection, ok := connectionSource[T](p.Source); ok {
+							return connection.TotalCount, nil
+						}
+						return 0, nil
+					},
+				},
+				"hasContent": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the page has at least one edge",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if connection, ok := connectionSource[T](p.Source); ok {
+							return connection.HasContent(), nil
+						}
+						return false, nil
+					},
+				},
+				"isEmpty": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the page has no edges",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if connection, ok := connectionSource[T](p.Source); ok {
+							return connection.IsEmpty(), nil
+						}
+						return true, nil
+					},
+				},
+			},
+		})
+	})
+}
+
+// ResultList is a page of items already sliced and counted by an upstream service,
+// such as a pageable REST response. WithResolverResultList maps it to the output of
+// the field as is, without counting or slicing the items again.
+type ResultList[T any] struct {
+	Items           []T
+	TotalCount      int
+	HasNextPage     bool
+	HasPreviousPage bool
+
+	// Offset is the position of the first item in the complete result set. Connection
+	// edges of items without an ID get cursors encoding their position.
+	Offset int
+
+	// Cursor returns the cursor of an item (optional). By default the cursor encodes
+	// the ID of the item, like the stable keys of NewConnectionFromSlice: the result
+	// of GetID (HasIDInterface), or its "id" field or map key.
+	Cursor func(item T) string
+}
+
+// connection returns the list as a connection page
+func (l *ResultList[T]) connection() *CursorConnection[T] {
+	connection := &CursorConnection[T]{
+		Edges:      make([]CursorEdge[T], len(l.Items)),
+		TotalCount: l.TotalCount,
+		PageInfo: PageInfo{
+			HasPreviousPage: l.HasPreviousPage,
+			HasNextPage:     l.HasNextPage,
+		},
+	}
+	for i, item := range l.Items {
+		var cursor string
+		if l.Cursor != nil {
+			cursor = l.Cursor(item)
+		} else if key, ok := itemKey(item); ok {
+			cursor = EncodeConnectionCursor(key)
+		} else {
+			cursor = EncodeConnectionCursor(strconv.Itoa(l.Offset + i))
+		}
+		connection.Edges[i] = CursorEdge[T]{Node: item, Cursor: cursor}
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	return connection
+}
+
+// itemKey returns the ID of an item: the result of GetID, or the value of its "id"
+// struct field or map key
+func itemKey(item interface{}) (string, bool) {
+	switch value := item.(type) {
+	case HasIDInterface:
+		return value.GetID(), true
+	case map[string]interface{}:
+		if id, ok := value["id"]; ok && id != nil {
+			return fmt.Sprint(id), true
+		}
+		return "", false
+	}
+
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	id, ok := sortColumnValue(v, "id")
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(id), true
+}
+
+// paginated returns the list as a paginated response
+func (l *ResultList[T]) paginated() PaginatedResponse[T] {
+	page := l.connection()
+	return PaginatedResponse[T]{Items: l.Items, TotalCount: l.TotalCount, PageInfo: page.PageInfo}
+}
+
+// WithResolverResultList sets a resolver returning a page computed upstream. The page
+// is exposed as the connection of AsConnection, or as the items/totalCount/pageInfo of
+// AsPaginated when the field is configured with it; fields configured with neither
+// become connections. The first/after/last/before arguments are passed as args for
+// the resolver to forward upstream.
+//
+// Example:
+//
+//	NewResolver[Interview]("interviews").
+//	    WithResolverResultList(func(p graph.ResolveParams, args graph.PaginationArgs) (*graph.ResultList[Interview], error) {
+//	        page, err := interviewService.Page(p.Context, args)
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return &graph.ResultList[Interview]{
+//	            Items:           page.Content,
+//	            TotalCount:      int(page.TotalElements),
+//	            HasNextPage:     page.HasNext,
+//	            HasPreviousPage: page.Number > 0,
+//	            Offset:          page.Number * page.Size,
+//	        }, nil
+//	    }).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) WithResolverResultList(resolver func(p ResolveParams, args PaginationArgs) (*ResultList[T], error)) *UnifiedResolver[T] {
+	if !r.isPaginated {
+		r.AsConnection()
+	}
+	r.resolver = func(p graphql.ResolveParams) (interface{}, error) {
+		var args PaginationArgs
+		if err := mapArgsToStruct(p.Args, &args); err != nil {
+			return nil, err
+		}
+		list, err := resolver(ResolveParams(p), args)
+		if err != nil || list == nil {
+			return nil, err
+		}
+		if r.isPaginated {
+			return list.paginated(), nil
+		}
+		return list.connection(), nil
+	}
+	return r
+}
@@ -0,0 +1,317 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestNewConnectionFromSlice(t *testing.T) {
+	items := []int{10, 20, 30, 40, 50}
+	key := func(item int) string { return strconv.Itoa(item) }
+	intPtr := func(n int) *int { return &n }
+	cursor := func(item int) *string {
+		c := EncodeConnectionCursor(strconv.Itoa(item))
+		return &c
+	}
+
+	tests := []struct {
+		name         string
+		args         PaginationArgs
+		want         []int
+		wantPrevious bool
+		wantNext     bool
+	}{
+		{name: "all", args: PaginationArgs{}, want: []int{10, 20, 30, 40, 50}},
+		{name: "first", args: PaginationArgs{First: intPtr(2)}, want: []int{10, 20}, wantNext: true},
+		{name: "first after", args: PaginationArgs{First: intPtr(2), After: cursor(20)}, want: []int{30, 40}, wantPrevious: true, wantNext: true},
+		{name: "first after to end", args: PaginationArgs{First: intPtr(5), After: cursor(30)}, want: []int{40, 50}, wantPrevious: true},
+		{name: "last", args: PaginationArgs{Last: intPtr(2)}, want: []int{40, 50}, wantPrevious: true},
+		{name: "last before", args: PaginationArgs{Last: intPtr(2), Before: cursor(40)}, want: []int{20, 30}, wantPrevious: true, wantNext: true},
+		{name: "after and before", args: PaginationArgs{After: cursor(10), Before: cursor(50)}, want: []int{20, 30, 40}, wantPrevious: true, wantNext: true},
+		{name: "unknown cursor is ignored", args: PaginationArgs{First: intPtr(1), After: cursor(99)}, want: []int{10}, wantNext: true},
+		{name: "first zero", args: PaginationArgs{First: intPtr(0)}, want: []int{}, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connection, err := NewConnectionFromSlice(items, tt.args, key)
+			if err != nil {
+				t.Fatalf("NewConnectionFromSlice() error = %v", err)
+			}
+
+			got := make([]int, len(connection.Edges))
+			for i, edge := range connection.Edges {
+				got[i] = edge.Node
+				if edge.Cursor != *cursor(edge.Node) {
+					t.Errorf("edge %d cursor = %s, want cursor of %d", i, edge.Cursor, edge.Node)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("nodes = %v, want %v", got, tt.want)
+			}
+			if connection.PageInfo.HasPreviousPage != tt.wantPrevious {
+				t.Errorf("hasPreviousPage = %v, want %v", connection.PageInfo.HasPreviousPage, tt.wantPrevious)
+			}
+			if connection.PageInfo.HasNextPage != tt.wantNext {
+				t.Errorf("hasNextPage = %v, want %v", connection.PageInfo.HasNextPage, tt.wantNext)
+			}
+			if connection.TotalCount != len(items) {
+				t.Errorf("totalCount = %d, want %d", connection.TotalCount, len(items))
+			}
+		})
+	}
+
+	if _, err := NewConnectionFromSlice(items, PaginationArgs{First: intPtr(-1)}, key); err == nil {
+		t.Error("Expected error for negative first")
+	}
+
+	// Cursors encode the key, so they survive inserts before the cursor
+	page, _ := NewConnectionFromSlice(items, PaginationArgs{First: intPtr(2)}, key)
+	inserted := append([]int{5}, items...)
+	next, _ := NewConnectionFromSlice(inserted, PaginationArgs{First: intPtr(2), After: &page.PageInfo.EndCursor}, key)
+	if next.Edges[0].Node != 30 {
+		t.Errorf("Expected next page to start at 30 after an insert, got %d", next.Edges[0].Node)
+	}
+}
+
+func TestUnifiedResolver_AsConnection(t *testing.T) {
+	type ConnectionTestUser struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	users := []ConnectionTestUser{{ID: 1, Name: "ada"}, {ID: 2, Name: "grace"}, {ID: 3, Name: "linus"}}
+
+	field := NewResolver[ConnectionTestUser]("connectionTestUsers").
+		AsConnection().
+		WithConnectionResolver(func(p ResolveParams, args PaginationArgs) (*CursorConnection[ConnectionTestUser], error) {
+			return NewConnectionFromSlice(users, args, func(u ConnectionTestUser) string {
+				return strconv.Itoa(u.ID)
+			})
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field, NewResolver[PaginatedResponse[ConnectionTestUser]]("connectionTestPaginated").AsPaginated().BuildQuery()},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	for _, name := range []string{"ConnectionTestUserConnection", "ConnectionTestUserEdge"} {
+		if schema.Type(name) == nil {
+			t.Errorf("Expected schema to contain type %s", name)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			connectionTestUsers(first: 2, after: "MQ==") {
+				totalCount
+				edges { cursor node { id name } }
+				pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+			}
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	connection := result.Data.(map[string]interface{})["connectionTestUsers"].(map[string]interface{})
+	edges := connection["edges"].([]interface{})
+	if len(edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d", len(edges))
+	}
+	first := edges[0].(map[string]interface{})
+	if first["cursor"] != EncodeConnectionCursor("2") {
+		t.Errorf("Expected cursor of user 2, got %v", first["cursor"])
+	}
+	if node := first["node"].(map[string]interface{}); node["name"] != "grace" {
+		t.Errorf("Expected first node grace, got %v", node["name"])
+	}
+	pageInfo := connection["pageInfo"].(map[string]interface{})
+	if pageInfo["hasNextPage"] != false || pageInfo["hasPreviousPage"] != true {
+		t.Errorf("Unexpected pageInfo %v", pageInfo)
+	}
+	if pageInfo["endCursor"] != EncodeConnectionCursor("3") {
+		t.Errorf("Expected endCursor of user 3, got %v", pageInfo["endCursor"])
+	}
+	if connection["totalCount"] != 3 {
+		t.Errorf("Expected totalCount 3, got %v", connection["totalCount"])
+	}
+}
+
+func TestUnifiedResolver_AsConnectionContentFlags(t *testing.T) {
+	type ConnectionFlagsItem struct {
+		ID int `json:"id"`
+	}
+	items := []ConnectionFlagsItem{{ID: 1}, {ID: 2}}
+
+	field := NewResolver[ConnectionFlagsItem]("connectionFlagsItems").
+		WithConnectionResolver(func(p ResolveParams, args PaginationArgs) (*CursorConnection[ConnectionFlagsItem], error) {
+			return NewConnectionFromSlice(items, args, func(item ConnectionFlagsItem) string {
+				return strconv.Itoa(item.ID)
+			})
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantHasContent bool
+		wantIsEmpty    bool
+	}{
+		{name: "non-empty page", query: `{ connectionFlagsItems(first: 1) { hasContent isEmpty } }`, wantHasContent: true},
+		{name: "empty page", query: `{ connectionFlagsItems(first: 0) { hasContent isEmpty } }`, wantIsEmpty: true},
+		{name: "empty page after last item", query: `{ connectionFlagsItems(after: "Mg==") { hasContent isEmpty } }`, wantIsEmpty: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tt.query})
+			if len(result.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			connection := result.Data.(map[string]interface{})["connectionFlagsItems"].(map[string]interface{})
+			if connection["hasContent"] != tt.wantHasContent {
+				t.Errorf("hasContent = %v, want %v", connection["hasContent"], tt.wantHasContent)
+			}
+			if connection["isEmpty"] != tt.wantIsEmpty {
+				t.Errorf("isEmpty = %v, want %v", connection["isEmpty"], tt.wantIsEmpty)
+			}
+		})
+	}
+}
+
+func TestUnifiedResolver_WithResolverResultList(t *testing.T) {
+	type ResultListTestInterview struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+
+	// The upstream page is the second page of 2 out of 5 interviews
+	upstream := TestPageableResponse[ResultListTestInterview]{
+		Size:             2,
+		Number:           1,
+		HasNext:          true,
+		NumberOfElements: 2,
+		TotalElements:    5,
+		Content:          []ResultListTestInterview{{ID: 3, Title: "backend"}, {ID: 4, Title: "frontend"}},
+	}
+	var received PaginationArgs
+	toResultList := func(p ResolveParams, args PaginationArgs) (*ResultList[ResultListTestInterview], error) {
+		received = args
+		return &ResultList[ResultListTestInterview]{
+			Items:           upstream.Content,
+			TotalCount:      int(upstream.TotalElements),
+			HasNextPage:     upstream.HasNext,
+			HasPreviousPage: upstream.Number > 0,
+			Offset:          upstream.Number * upstream.Size,
+		}, nil
+	}
+
+	field := NewResolver[ResultListTestInterview]("resultListTestInterviews").
+		WithResolverResultList(toResultList).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	if schema.Type("ResultListTestInterviewConnection") == nil {
+		t.Fatal("Expected the field to use the connection type")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			resultListTestInterviews(first: 2, after: "MQ==") {
+				totalCount
+				edges { cursor node { id title } }
+				pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+			}
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if received.First == nil || *received.First != 2 || received.After == nil || *received.After != "MQ==" {
+		t.Errorf("Expected the pagination args to be passed to the resolver, got %+v", received)
+	}
+
+	connection := result.Data.(map[string]interface{})["resultListTestInterviews"].(map[string]interface{})
+	if connection["totalCount"] != 5 {
+		t.Errorf("Expected the upstream total of 5, got %v", connection["totalCount"])
+	}
+	edges := connection["edges"].([]interface{})
+	if len(edges) != 2 {
+		t.Fatalf("Expected the 2 upstream items unsliced, got %d", len(edges))
+	}
+	first := edges[0].(map[string]interface{})
+	if first["cursor"] != EncodeConnectionCursor("3") {
+		t.Errorf("Expected the cursor of ID 3, got %v", first["cursor"])
+	}
+	if node := first["node"].(map[string]interface{}); node["id"] != 3 {
+		t.Errorf("Expected first node 3, got %v", node["id"])
+	}
+	pageInfo := connection["pageInfo"].(map[string]interface{})
+	if pageInfo["hasNextPage"] != true || pageInfo["hasPreviousPage"] != true {
+		t.Errorf("Unexpected pageInfo %v", pageInfo)
+	}
+	if pageInfo["endCursor"] != EncodeConnectionCursor("4") {
+		t.Errorf("Expected the endCursor of ID 4, got %v", pageInfo["endCursor"])
+	}
+
+	paginated := NewResolver[ResultListTestInterview]("resultListTestInterviewsPage").
+		AsPaginated().
+		WithResolverResultList(toResultList).
+		Serve()
+	value, err := paginated.Resolve(graphql.ResolveParams{Args: map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	page, ok := value.(PaginatedResponse[ResultListTestInterview])
+	if !ok {
+		t.Fatalf("Expected a paginated response, got %T", value)
+	}
+	if page.TotalCount != 5 || len(page.Items) != 2 || !page.PageInfo.HasNextPage {
+		t.Errorf("Unexpected paginated response %+v", page)
+	}
+}
+
+func TestResultList_ConnectionCursors(t *testing.T) {
+	type keyedItem struct {
+		ID string `json:"id"`
+	}
+	type unkeyedItem struct {
+		Title string `json:"title"`
+	}
+
+	keyed := (&ResultList[keyedItem]{Items: []keyedItem{{ID: "a"}, {ID: "b"}}, Offset: 10}).connection()
+	if keyed.Edges[0].Cursor != EncodeConnectionCursor("a") || keyed.Edges[1].Cursor != EncodeConnectionCursor("b") {
+		t.Errorf("Expected cursors of the item IDs, got %+v", keyed.Edges)
+	}
+
+	rows := (&ResultList[map[string]interface{}]{Items: []map[string]interface{}{{"id": 7}}}).connection()
+	if rows.Edges[0].Cursor != EncodeConnectionCursor("7") {
+		t.Errorf("Expected the cursor of the id key, got %s", rows.Edges[0].Cursor)
+	}
+
+	// Items without an ID fall back to their position
+	unkeyed := (&ResultList[unkeyedItem]{Items: []unkeyedItem{{Title: "x"}, {Title: "y"}}, Offset: 10}).connection()
+	if unkeyed.Edges[0].Cursor != EncodeConnectionCursor("10") || unkeyed.Edges[1].Cursor != EncodeConnectionCursor("11") {
+		t.Errorf("Expected cursors of the item positions, got %+v", unkeyed.Edges)
+	}
+}
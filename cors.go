@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig configures the Cross-Origin Resource Sharing headers of NewHTTP.
+//
+// Example:
+//
+//	graph.NewHTTP(&graph.GraphContext{
+//	    SchemaParams: params,
+//	    CORS: &graph.CORSConfig{
+//	        AllowedOrigins:   []string{"https://app.example.com", "https://*.example.dev"},
+//	        AllowCredentials: true,
+//	    },
+//	})
+type CORSConfig struct {
+	// AllowedOrigins: Origins allowed to call the endpoint. An entry is an exact origin,
+	// "*" for any origin, or a pattern with one wildcard such as "https://*.example.com"
+	// Origins allowed only by "*" never get credentials, see AllowCredentials
+	AllowedOrigins []string
+
+	// AllowedMethods: Methods allowed in preflight responses (default: GET, POST, OPTIONS)
+	AllowedMethods []string
+
+	// AllowedHeaders: Request headers allowed in preflight responses
+	// Default: Content-Type, Authorization
+	AllowedHeaders []string
+
+	// AllowCredentials: Allow cookies and authorization headers on cross-origin requests
+	// from the exact origins and patterns of AllowedOrigins, whose origin is echoed as
+	// required by browsers. It doesn't apply to "*": echoing any origin with credentials
+	// would let every site act as the logged-in user, so such requests are answered
+	// with "*" and no credentials, and NewHTTP logs a warning for the configuration
+	AllowCredentials bool
+
+	// MaxAge: How long browsers may cache preflight responses (default: not sent)
+	MaxAge time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for origin, or an
+// empty string when the origin is not allowed
+func (c *CORSConfig) allowedOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, allowed := range c.AllowedOrigins {
+		if allowed != "*" && matchOrigin(allowed, origin) {
+			return origin
+		}
+	}
+	if c.allowsAnyOrigin() {
+		return "*"
+	}
+	return ""
+}
+
+// allowsAnyOrigin reports whether AllowedOrigins contains "*"
+func (c *CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin reports whether origin matches an exact origin or a pattern with one wildcard
+func matchOrigin(pattern, origin string) bool {
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return strings.EqualFold(pattern, origin)
+	}
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+		strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix))
+}
+
+// handleCORS adds the CORS headers for the request origin and answers preflight
+// requests with 204 No Content. It returns true when the request was answered.
+func handleCORS(w http.ResponseWriter, r *http.Request, cors *CORSConfig) bool {
+	if cors == nil {
+		return false
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	origin := cors.allowedOrigin(r.Header.Get("Origin"))
+	if origin != "" {
+		header.Set("Access-Control-Allow-Origin", origin)
+		if cors.AllowCredentials && origin != "*" {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+
+	if r.Method != http.MethodOptions {
+		return false
+	}
+
+	if origin != "" {
+		methods := cors.AllowedMethods
+		if len(methods) == 0 {
+			methods = defaultCORSMethods
+		}
+		headers := cors.AllowedHeaders
+		if len(headers) == 0 {
+			headers = defaultCORSHeaders
+		}
+		header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		header.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+		if cors.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(cors.MaxAge.Seconds())))
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
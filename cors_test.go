@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTTP_CORS(t *testing.T) {
+	newHandler := func(cors *CORSConfig) http.HandlerFunc {
+		return NewHTTP(&GraphContext{
+			SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{getDefaultHelloQuery()}},
+			CORS:         cors,
+		})
+	}
+	request := func(handler http.HandlerFunc, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/graphql", bytes.NewBufferString(`{"query":"{ hello }"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	t.Run("preflight is answered with the configured headers", func(t *testing.T) {
+		handler := newHandler(&CORSConfig{
+			AllowedOrigins:   []string{"https://app.example.com"},
+			AllowedHeaders:   []string{"Content-Type", "X-Tenant"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		})
+		w := request(handler, http.MethodOptions, "https://app.example.com")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", w.Code)
+		}
+		want := map[string]string{
+			"Access-Control-Allow-Origin":      "https://app.example.com",
+			"Access-Control-Allow-Methods":     "GET, POST, OPTIONS",
+			"Access-Control-Allow-Headers":     "Content-Type, X-Tenant",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Max-Age":           "600",
+		}
+		for header, value := range want {
+			if got := w.Header().Get(header); got != value {
+				t.Errorf("Expected %s %q, got %q", header, value, got)
+			}
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected an empty preflight body, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("actual responses carry the allow origin header", func(t *testing.T) {
+		w := request(newHandler(&CORSConfig{AllowedOrigins: []string{"https://*.example.com"}}), http.MethodPost, "https://admin.example.com")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://admin.example.com" {
+			t.Errorf("Expected the origin to be allowed, got %q", got)
+		}
+	})
+
+	t.Run("wildcard origin", func(t *testing.T) {
+		w := request(newHandler(&CORSConfig{AllowedOrigins: []string{"*"}}), http.MethodPost, "https://any.test")
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("Expected *, got %q", got)
+		}
+	})
+
+	t.Run("wildcard origin never gets credentials", func(t *testing.T) {
+		handler := newHandler(&CORSConfig{
+			AllowedOrigins:   []string{"https://app.example.com", "*"},
+			AllowCredentials: true,
+		})
+		w := request(handler, http.MethodPost, "https://evil.test")
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("Expected * for an origin allowed by the wildcard, got %q", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("Expected no credentials for an origin allowed by the wildcard, got %q", got)
+		}
+
+		w = request(handler, http.MethodPost, "https://app.example.com")
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Expected the listed origin to be echoed, got %q", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("Expected credentials for the listed origin, got %q", got)
+		}
+	})
+
+	t.Run("disallowed origin gets no headers", func(t *testing.T) {
+		handler := newHandler(&CORSConfig{AllowedOrigins: []string{"https://app.example.com", "https://*.example.com"}})
+		for _, origin := range []string{"https://evil.test", "https://example.com.evil.test"} {
+			w := request(handler, http.MethodOptions, origin)
+			if w.Code != http.StatusNoContent {
+				t.Errorf("Expected status 204, got %d", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("Expected no allow origin header for %s, got %q", origin, got)
+			}
+		}
+	})
+
+	t.Run("nil config is a no-op", func(t *testing.T) {
+		w := request(newHandler(nil), http.MethodPost, "https://app.example.com")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		for header := range w.Header() {
+			if strings.HasPrefix(header, "Access-Control-") {
+				t.Errorf("Unexpected header %s", header)
+			}
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SortField is a column of a keyset pagination sort order
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// SortRequest is the sort order used for keyset (cursor) pagination.
+// The last field must be unique (typically the primary key) so that every row
+// has a distinct position and pages have no duplicates or gaps.
+//
+// Example:
+//
+//	sort := graph.SortRequest{
+//	    {Column: "score", Desc: true},
+//	    {Column: "id"},
+//	}
+type SortRequest []SortField
+
+// keysetCursor is the encoded content of a cursor
+type keysetCursor struct {
+	Sort   []string      `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// key identifies the sort order a cursor was created for
+func (s SortRequest) key() []string {
+	key := make([]string, len(s))
+	for i, field := range s {
+		key[i] = field.Column
+		if field.Desc {
+			key[i] += " desc"
+		}
+	}
+	return key
+}
+
+// EncodeCursor encodes the sort column values of a row into an opaque cursor.
+// values must be given in the order of the sort fields.
+func (s SortRequest) EncodeCursor(values ...interface{}) (string, error) {
+	if len(values) != len(s) {
+		return "", fmt.Errorf("cursor needs %d sort values, got %d", len(s), len(values))
+	}
+	data, err := json.Marshal(keysetCursor{Sort: s.key(), Values: values})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// CursorFor encodes the cursor of a struct row, reading each sort column from the
+// field whose json name or Go name matches it.
+//
+// Example:
+//
+//	pageInfo.EndCursor, err = sort.CursorFor(users[len(users)-1])
+func (s SortRequest) CursorFor(row interface{}) (string, error) {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", fmt.Errorf("cannot build cursor for nil row")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("cannot build cursor for %s, expected struct", v.Kind())
+	}
+
+	values := make([]interface{}, len(s))
+	for i, field := range s {
+		value, ok := sortColumnValue(v, field.Column)
+		if !ok {
+			return "", fmt.Errorf("sort column %q not found in %s", field.Column, v.Type())
+		}
+		values[i] = value
+	}
+	return s.EncodeCursor(values...)
+}
+
+// sortColumnValue returns the value of the struct field matching a sort column
+func sortColumnValue(v reflect.Value, column string) (interface{}, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if getFieldName(field) == column || strings.EqualFold(field.Name, column) {
+			return v.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// DecodeCursor returns the sort column values encoded in a cursor.
+// Cursors created for a different sort order are rejected.
+// JSON numbers are returned as int64 when integral, float64 otherwise.
+func (s SortRequest) DecodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var decoded keysetCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Sort, s.key()) || len(decoded.Values) != len(s) {
+		return nil, fmt.Errorf("cursor does not match sort order %s", strings.Join(s.key(), ", "))
+	}
+
+	for i, value := range decoded.Values {
+		if number, ok := value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				decoded.Values[i] = n
+			} else if f, err := number.Float64(); err == nil {
+				decoded.Values[i] = f
+			}
+		}
+	}
+	return decoded.Values, nil
+}
+
+// KeysetWhere builds the WHERE clause selecting the rows after a cursor in the sort
+// order, with "?" placeholders and their arguments.
+// For sort (score DESC, id ASC) it returns
+//
+//	(score < ?) OR (score = ? AND id > ?)
+//
+// Column names are written as given and must not come from user input.
+//
+// Example:
+//
+//	where, args, err := sort.KeysetWhere(*pagination.After)
+//	if err != nil {
+//	    return nil, err
+//	}
+//	query := "SELECT * FROM users WHERE " + where + " ORDER BY " + sort.OrderBy() + " LIMIT ?"
+//	rows, err := db.Query(query, append(args, *pagination.First)...)
+func (s SortRequest) KeysetWhere(cursor string) (string, []interface{}, error) {
+	values, err := s.DecodeCursor(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var clauses []string
+	var args []interface{}
+	for i, field := range s {
+		var conditions []string
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, s[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if field.Desc {
+			op = "<"
+		}
+		conditions = append(conditions, field.Column+" "+op+" ?")
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(conditions, " AND ")+")")
+	}
+	return strings.Join(clauses, " OR "), args, nil
+}
+
+// OrderBy returns the ORDER BY expression of the sort order
+func (s SortRequest) OrderBy() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		parts[i] = field.Column + " ASC"
+		if field.Desc {
+			parts[i] = field.Column + " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+type cursorRow struct {
+	ID    int `json:"id"`
+	Score int `json:"score"`
+}
+
+// matchesKeyset evaluates a KeysetWhere clause against a row
+func matchesKeyset(t *testing.T, where string, args []interface{}, row cursorRow) bool {
+	t.Helper()
+	columns := map[string]int64{"id": int64(row.ID), "score": int64(row.Score)}
+	arg := 0
+	for _, clause := range strings.Split(where, " OR ") {
+		matched := true
+		for _, condition := range strings.Split(strings.Trim(clause, "()"), " AND ") {
+			parts := strings.Fields(condition)
+			value := columns[parts[0]]
+			bound := args[arg].(int64)
+			arg++
+			switch parts[1] {
+			case "=":
+				matched = matched && value == bound
+			case ">":
+				matched = matched && value > bound
+			case "<":
+				matched = matched && value < bound
+			default:
+				t.Fatalf("unexpected operator in %q", condition)
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSortRequest_KeysetPagination(t *testing.T) {
+	rows := []cursorRow{
+		{ID: 1, Score: 50}, {ID: 2, Score: 70}, {ID: 3, Score: 50}, {ID: 4, Score: 90},
+		{ID: 5, Score: 70}, {ID: 6, Score: 50}, {ID: 7, Score: 10}, {ID: 8, Score: 70},
+	}
+	order := SortRequest{{Column: "score", Desc: true}, {Column: "id"}}
+
+	if got := order.OrderBy(); got != "score DESC, id ASC" {
+		t.Errorf("OrderBy() = %q", got)
+	}
+
+	sorted := append([]cursorRow(nil), rows...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Score != sorted[j].Score {
+			return sorted[i].Score > sorted[j].Score
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var seen []int
+	cursor := ""
+	for page := 0; page < 10; page++ {
+		candidates := sorted
+		if cursor != "" {
+			where, args, err := order.KeysetWhere(cursor)
+			if err != nil {
+				t.Fatalf("KeysetWhere() error = %v", err)
+			}
+			if page == 1 && where != "(score < ?) OR (score = ? AND id > ?)" {
+				t.Errorf("KeysetWhere() = %q", where)
+			}
+			candidates = nil
+			for _, row := range sorted {
+				if matchesKeyset(t, where, args, row) {
+					candidates = append(candidates, row)
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		if len(candidates) > 3 {
+			candidates = candidates[:3]
+		}
+		for _, row := range candidates {
+			seen = append(seen, row.ID)
+		}
+
+		var err error
+		cursor, err = order.CursorFor(&candidates[len(candidates)-1])
+		if err != nil {
+			t.Fatalf("CursorFor() error = %v", err)
+		}
+	}
+
+	if len(seen) != len(sorted) {
+		t.Fatalf("Expected %d rows across pages, got %v", len(sorted), seen)
+	}
+	for i, row := range sorted {
+		if seen[i] != row.ID {
+			t.Fatalf("Expected rows %v in sort order, got %v", sorted, seen)
+		}
+	}
+}
+
+func TestSortRequest_DecodeCursorRejectsOtherSort(t *testing.T) {
+	byScore := SortRequest{{Column: "score", Desc: true}, {Column: "id"}}
+	byID := SortRequest{{Column: "id"}}
+
+	cursor, err := byScore.EncodeCursor(70, 5)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	values, err := byScore.DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if values[0] != int64(70) || values[1] != int64(5) {
+		t.Errorf("DecodeCursor() = %v, want [70 5]", values)
+	}
+
+	if _, err := byID.DecodeCursor(cursor); err == nil {
+		t.Error("Expected a cursor for another sort order to be rejected")
+	}
+	if _, err := byScore.DecodeCursor("not a cursor"); err == nil {
+		t.Error("Expected an invalid cursor to be rejected")
+	}
+}
@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// FieldDirective is a directive applied to a generated field through the `directive` struct tag.
+type FieldDirective struct {
+	// Name is the directive name without the leading @
+	Name string
+
+	// Source is the directive as written in the tag, e.g. `@cacheControl(maxAge: 60)`
+	Source string
+
+	// Arguments holds the parsed directive arguments
+	Arguments []*ast.Argument
+}
+
+// generatedGoType returns the Go struct type a generated object or input type was
+// generated from. Types are matched by identity, so same-named types of different
+// schemas or Go types each resolve to their own struct.
+func generatedGoType(t graphql.Type) (reflect.Type, bool) {
+	var goType reflect.Type
+	switch typ := t.(type) {
+	case *graphql.Object:
+		typeRegistryMu.RLock()
+		goType = objectGoTypes[typ]
+		typeRegistryMu.RUnlock()
+	case *graphql.InputObject:
+		inputTypeRegistryMu.RLock()
+		goType = inputGoTypes[typ]
+		inputTypeRegistryMu.RUnlock()
+	}
+	for goType != nil && (goType.Kind() == reflect.Ptr || goType.Kind() == reflect.Slice || goType.Kind() == reflect.Array) {
+		goType = goType.Elem()
+	}
+	if goType == nil || goType.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return goType, true
+}
+
+// parseDirectiveTag parses the value of a `directive` struct tag.
+// The tag holds one or more directives in GraphQL syntax:
+//
+//	type Product struct {
+//	    Price float64 `json:"price" directive:"@auth(requires: ADMIN) @cacheControl(maxAge: 60)"`
+//	    SKU   string  `json:"sku" directive:"@deprecated(reason: \"Use code\")"`
+//	}
+//
+// Tags that are not valid GraphQL directive syntax are ignored.
+func parseDirectiveTag(tag string) []FieldDirective {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil
+	}
+
+	// Parse the directives as if they were applied to a field in a query
+	const prefix = "{ f "
+	body := prefix + tag + " }"
+	doc, err := parser.Parse(parser.ParseParams{Source: body})
+	if err != nil || len(doc.Definitions) != 1 {
+		return nil
+	}
+
+	op, ok := doc.Definitions[0].(*ast.OperationDefinition)
+	if !ok || op.SelectionSet == nil || len(op.SelectionSet.Selections) != 1 {
+		return nil
+	}
+	field, ok := op.SelectionSet.Selections[0].(*ast.Field)
+	if !ok || field.SelectionSet != nil {
+		return nil
+	}
+
+	directives := make([]FieldDirective, 0, len(field.Directives))
+	for _, d := range field.Directives {
+		directives = append(directives, FieldDirective{
+			Name:      d.Name.Value,
+			Source:    body[d.Loc.Start:d.Loc.End],
+			Arguments: d.Arguments,
+		})
+	}
+	return directives
+}
+
+// deprecationFromDirectives returns the deprecation reason declared by a @deprecated
+// directive, or an empty string if there is none
+func deprecationFromDirectives(directives []FieldDirective) string {
+	for _, d := range directives {
+		if d.Name != "deprecated" {
+			continue
+		}
+		for _, arg := range d.Arguments {
+			if arg.Name.Value == "reason" {
+				if value, ok := arg.Value.(*ast.StringValue); ok {
+					return value.Value
+				}
+			}
+		}
+		return graphql.DefaultDeprecationReason
+	}
+	return ""
+}
+
+// deprecationReason returns the deprecation reason of a struct field, given by its
+// `deprecated` tag or a @deprecated directive in its `directive` tag. An empty
+// `deprecated` tag leaves the field non-deprecated.
+//
+//	type User struct {
+//	    Name     string `json:"name" deprecated:"Use fullName instead"`
+//	    FullName string `json:"fullName"`
+//	}
+func deprecationReason(field reflect.StructField) string {
+	if reason := strings.TrimSpace(field.Tag.Get("deprecated")); reason != "" {
+		return reason
+	}
+	return deprecationFromDirectives(parseDirectiveTag(field.Tag.Get("directive")))
+}
+
+// inputDescription returns the description of an input field or argument generated
+// from a struct field. graphql-go cannot deprecate arguments and input fields, so
+// the deprecation reason is added to the description instead.
+func inputDescription(field reflect.StructField) string {
+	description := field.Tag.Get("description")
+	reason := deprecationReason(field)
+	if reason == "" {
+		return description
+	}
+	if description == "" {
+		return "Deprecated: " + reason
+	}
+	return description + "\n\nDeprecated: " + reason
+}
+
+// collectFieldDirectives returns the directives declared on the fields of a struct,
+// keyed by GraphQL field name. Fields of embedded structs are included; as with field
+// generation, fields declared on the outer struct take precedence.
+func collectFieldDirectives(t reflect.Type) map[string][]FieldDirective {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	gen := NewFieldGenerator[any]()
+	result := make(map[string][]FieldDirective)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			for name, directives := range collectFieldDirectives(field.Type) {
+				if _, exists := result[name]; !exists {
+					result[name] = directives
+				}
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldName := gen.getFieldName(field)
+		if fieldName == "-" {
+			continue
+		}
+
+		// Outer fields replace embedded fields of the same name, directives included
+		if directives := parseDirectiveTag(field.Tag.Get("directive")); len(directives) > 0 {
+			result[fieldName] = directives
+		} else {
+			delete(result, fieldName)
+		}
+	}
+	return result
+}
+
+// printFieldDirectives prints the tag directives of a field, skipping @deprecated
+// when the deprecation is already part of the field definition
+func printFieldDirectives(directives []FieldDirective, skipDeprecated bool) string {
+	var b strings.Builder
+	for _, d := range directives {
+		if skipDeprecated && d.Name == "deprecated" {
+			continue
+		}
+		b.WriteString(" ")
+		b.WriteString(d.Source)
+	}
+	return b.String()
+}
+
+// typeFieldDirectives returns the tag directives for the fields of a generated type
+func typeFieldDirectives(t graphql.Type) map[string][]FieldDirective {
+	goType, exists := generatedGoType(t)
+	if !exists {
+		return nil
+	}
+	return collectFieldDirectives(goType)
+}
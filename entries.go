@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// MapEntry is a single key/value pair of a map returned by a resolver.
+// GraphQL has no map type, so resolvers returning map[string]V expose the map as a
+// list of entries sorted by key:
+//
+//	NewResolver[map[string]int]("stock")        // stock: [IntEntry]
+//	NewResolver[[]map[string]interface{}]("rows") // rows: [[JSONEntry]]
+//
+//	type IntEntry {
+//	    key: String!
+//	    value: Int
+//	}
+type MapEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// isStringKeyedMap reports whether t is a map with string keys
+func isStringKeyedMap(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// mapEntryType returns the GraphQL entry type for maps with values of type valueType,
+// generated in scope. Values are typed like struct fields, so map and interface{}
+// values use the JSON scalar.
+func mapEntryType(scope *typeScope, valueType reflect.Type) graphql.Output {
+	gen := newScopedFieldGenerator[any](scope)
+	valueOutput := gen.getBaseGraphQLType(valueType, nil)
+	if valueOutput == nil {
+		valueOutput = JSON
+	}
+
+	var valueName string
+	if named, ok := valueOutput.(graphql.Type); ok {
+		valueName = sanitizeTypeName(named.Name())
+	}
+	if list, ok := valueOutput.(*graphql.List); ok {
+		valueName = sanitizeTypeName(list.OfType.Name()) + "List"
+	}
+	entryName := valueName + "Entry"
+
+	return scopedObjectType(scope, entryName, func() *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name:        entryName,
+			Description: "A key/value entry of a map",
+			Fields: graphql.Fields{
+				"key": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if entry, ok := p.Source.(MapEntry); ok {
+							return entry.Key, nil
+						}
+						return nil, nil
+					},
+				},
+				"value": &graphql.Field{
+					Type: valueOutput,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if entry, ok := p.Source.(MapEntry); ok {
+							return entry.Value, nil
+						}
+						return nil, nil
+					},
+				},
+			},
+		})
+	})
+}
+
+// mapToEntries converts map results into sorted lists of MapEntry values.
+// Maps are converted to []MapEntry and slices of maps to [][]MapEntry; pointers are
+// dereferenced and any other value is returned unchanged.
+func mapToEntries(value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys must be strings, got %s", rv.Type().Key())
+		}
+
+		entries := make([]MapEntry, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			entries = append(entries, MapEntry{Key: iter.Key().String(), Value: iter.Value().Interface()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+		return entries, nil
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		items := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := mapToEntries(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,298 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// caseInsensitiveEnum is a type created by NewCaseInsensitiveEnum with its values
+type caseInsensitiveEnum struct {
+	scalar *graphql.Scalar
+	values []string
+}
+
+// caseInsensitiveEnums caches enum types by name so the same type
+// is reused across resolvers (GraphQL type names must be unique in a schema)
+var (
+	caseInsensitiveEnums   = make(map[string]caseInsensitiveEnum)
+	caseInsensitiveEnumsMu sync.Mutex
+)
+
+// NewCaseInsensitiveEnum creates a GraphQL type that accepts a fixed set of values
+// regardless of letter case and always resolves them to their canonical spelling.
+// Clients can send `asc`, `Asc` or `ASC`, as an enum literal, a string literal or a
+// variable, and resolvers always receive "ASC". Any other value is rejected during validation.
+//
+// The type is a scalar, since graphql-go enums only accept their exact member names:
+// introspection and the SDL show a scalar whose description lists the values, so
+// clients and code generators don't see the members. Use RegisterEnum when the
+// members must be part of the schema.
+//
+// Calling NewCaseInsensitiveEnum again with the same name and values returns the
+// existing type; it panics when the values differ.
+//
+// Example:
+//
+//	status := graph.NewCaseInsensitiveEnum("Status", "ACTIVE", "ARCHIVED")
+func NewCaseInsensitiveEnum(name string, values ...string) *graphql.Scalar {
+	caseInsensitiveEnumsMu.Lock()
+	defer caseInsensitiveEnumsMu.Unlock()
+
+	if existing, exists := caseInsensitiveEnums[name]; exists {
+		if !slices.Equal(existing.values, values) {
+			panic(fmt.Sprintf("case-insensitive enum %s already exists with values %v, got %v", name, existing.values, values))
+		}
+		return existing.scalar
+	}
+
+	lookup := make(map[string]string, len(values))
+	for _, value := range values {
+		lookup[strings.ToLower(value)] = value
+	}
+
+	coerce := func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			if canonical, exists := lookup[strings.ToLower(s)]; exists {
+				return canonical
+			}
+		}
+		return nil
+	}
+
+	enumType := graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: fmt.Sprintf("One of %s (case-insensitive)", strings.Join(values, ", ")),
+		Serialize:   coerce,
+		ParseValue:  coerce,
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch v := valueAST.(type) {
+			case *ast.EnumValue:
+				return coerce(v.Value)
+			case *ast.StringValue:
+				return coerce(v.Value)
+			}
+			return nil
+		},
+	})
+
+	caseInsensitiveEnums[name] = caseInsensitiveEnum{scalar: enumType, values: append([]string(nil), values...)}
+	registerAllowedValues(name, values)
+	return enumType
+}
+
+// SortDirection is a case-insensitive enum for sort direction arguments.
+// Resolvers always receive "ASC" or "DESC".
+var SortDirection = NewCaseInsensitiveEnum("SortDirection", "ASC", "DESC")
+
+// WithEnumArg adds an argument of a case-insensitive enum type created with
+// NewCaseInsensitiveEnum (such as SortDirection). When defaultValue is not empty it is
+// used if the client omits the argument; it is normalized to its canonical spelling.
+//
+// Example:
+//
+//	NewResolver[[]User]("users").
+//	    AsList().
+//	    WithEnumArg("direction", graph.SortDirection, "asc").
+//	    WithResolver(func(p graph.ResolveParams) (*[]User, error) {
+//	        direction, _ := graph.GetArgString(p, "direction") // "ASC" or "DESC"
+//	        return userService.List(direction)
+//	    }).BuildQuery()
+func (r *UnifiedResolver[T]) WithEnumArg(name string, enumType *graphql.Scalar, defaultValue string) *UnifiedResolver[T] {
+	if r.args == nil {
+		r.args = graphql.FieldConfigArgument{}
+	}
+
+	argConfig := &graphql.ArgumentConfig{
+		Type: enumType,
+	}
+	if defaultValue != "" {
+		if canonical := enumType.ParseValue(defaultValue); canonical != nil {
+			argConfig.DefaultValue = canonical
+		} else {
+			argConfig.DefaultValue = defaultValue
+		}
+	}
+
+	r.args[name] = argConfig
+	return r
+}
+
+// Global enum registry used by the type generators
+var (
+	enumsByType  = make(map[reflect.Type]*graphql.Enum)
+	enumRegistry sync.RWMutex
+)
+
+// EnumValue is the underlying type of Go constants that RegisterEnum maps to enum members
+type EnumValue interface {
+	~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// RegisterEnum creates a GraphQL enum from a group of Go string or integer constants
+// and maps the Go type T to it. Struct fields, input fields and arguments of type T
+// (or *T, []T) use the enum instead of String or Int, so introspection lists the
+// allowed values and unknown members are rejected during validation. Resolvers receive
+// and return values of type T: an int-backed value is serialized as the name of its
+// member, and a member name is parsed back to its value.
+//
+// Members of string enums are listed by name, members of integer enums by value.
+// Calling RegisterEnum again for the same Go type returns the existing enum.
+//
+// Example:
+//
+//	type InterviewState string
+//
+//	const (
+//	    InterviewStarted InterviewState = "started"
+//	    InterviewEnded   InterviewState = "ended"
+//	)
+//
+//	graph.RegisterEnum("InterviewState", map[string]InterviewState{
+//	    "STARTED": InterviewStarted,
+//	    "ENDED":   InterviewEnded,
+//	})
+//
+//	type Interview struct {
+//	    State InterviewState `json:"state"` // InterviewState enum
+//	}
+//
+// Integer constants declared with iota work the same way:
+//
+//	type Priority int
+//
+//	const (
+//	    PriorityLow Priority = iota
+//	    PriorityHigh
+//	)
+//
+//	graph.RegisterEnum("Priority", map[string]Priority{
+//	    "LOW":  PriorityLow,
+//	    "HIGH": PriorityHigh,
+//	})
+func RegisterEnum[T EnumValue](name string, values map[string]T) *graphql.Enum {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	enumRegistry.Lock()
+	defer enumRegistry.Unlock()
+
+	if existing, exists := enumsByType[t]; exists {
+		return existing
+	}
+
+	names := make([]string, 0, len(values))
+	for valueName := range values {
+		names = append(names, valueName)
+	}
+	sort.Strings(names)
+	if t.Kind() != reflect.String {
+		sort.SliceStable(names, func(i, j int) bool {
+			return values[names[i]] < values[names[j]]
+		})
+	}
+
+	enumValues := make(graphql.EnumValueConfigMap, len(values))
+	for _, valueName := range names {
+		enumValues[valueName] = &graphql.EnumValueConfig{Value: values[valueName]}
+	}
+
+	enumType := graphql.NewEnum(graphql.EnumConfig{
+		Name:        name,
+		Description: fmt.Sprintf("One of %s", strings.Join(names, ", ")),
+		Values:      enumValues,
+	})
+
+	enumsByType[t] = enumType
+	registerAllowedValues(name, names)
+	return enumType
+}
+
+// lookupEnumByType returns the enum registered for a Go type
+func lookupEnumByType(t reflect.Type) *graphql.Enum {
+	enumRegistry.RLock()
+	defer enumRegistry.RUnlock()
+	return enumsByType[t]
+}
+
+// Allowed values of the enums created by NewCaseInsensitiveEnum and RegisterEnum,
+// by type name, listed in invalid value errors
+var (
+	allowedValuesByEnum = make(map[string][]string)
+	allowedValuesMu     sync.RWMutex
+)
+
+func registerAllowedValues(name string, values []string) {
+	allowedValuesMu.Lock()
+	defer allowedValuesMu.Unlock()
+	allowedValuesByEnum[name] = append([]string(nil), values...)
+}
+
+func lookupAllowedValues(name string) ([]string, bool) {
+	allowedValuesMu.RLock()
+	defer allowedValuesMu.RUnlock()
+	values, exists := allowedValuesByEnum[name]
+	return values, exists
+}
+
+// NewEnumValueError returns the error for a value of name that is not one of the
+// allowed values. Rejected values of enum arguments get the same message, so
+// resolvers validating a plain string argument can report it the same way:
+//
+//	invalid value "x" for direction; allowed: [ASC, DESC]
+//
+// Example:
+//
+//	format, _ := graph.GetArgString(p, "format")
+//	if format != "csv" && format != "json" {
+//	    return nil, graph.NewEnumValueError("format", format, []string{"csv", "json"})
+//	}
+func NewEnumValueError(name string, value string, allowed []string) error {
+	return fmt.Errorf("invalid value %q for %s; allowed: [%s]", value, name, strings.Join(allowed, ", "))
+}
+
+// invalidEnumValuePattern matches the errors of graphql-go for an argument literal or
+// a variable whose value (or the value of one of its input fields) is not a member
+// of its type
+var invalidEnumValuePattern = regexp.MustCompile(`^(?:Argument "([^"]+)" has|Variable "(\$[^"]+)" got) invalid value .*\n` +
+	`(?:In (?:field "([^"]+)"|element #\d+): )*Expected type "([^"]+)", found (.*)\.$`)
+
+// describeEnumValueError rewrites a graphql-go error for a rejected value of an enum
+// created by NewCaseInsensitiveEnum or RegisterEnum to list the allowed values.
+// Other messages are returned unchanged.
+func describeEnumValueError(message string) string {
+	match := invalidEnumValuePattern.FindStringSubmatch(message)
+	if match == nil {
+		return message
+	}
+	allowed, exists := lookupAllowedValues(match[4])
+	if !exists {
+		return message
+	}
+
+	name := match[1]
+	if match[2] != "" {
+		name = match[2]
+	}
+	if match[3] != "" {
+		name = match[3]
+	}
+	value := match[5]
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	for _, member := range allowed {
+		// A member sent as a string literal is rejected by enums for its type, not its value
+		if member == value {
+			return message
+		}
+	}
+	return NewEnumValueError(name, value, allowed).Error()
+}
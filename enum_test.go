@@ -0,0 +1,363 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestWithEnumArg_CaseInsensitiveSortDirection(t *testing.T) {
+	users := NewResolver[string]("enumSortedUsers").
+		WithEnumArg("direction", SortDirection, "asc").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			direction, err := GetArgString(p, "direction")
+			if err != nil {
+				return nil, err
+			}
+			orderBy := "ORDER BY name " + direction
+			return &orderBy, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{users},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+		wantError bool
+	}{
+		{name: "upper case enum literal", query: `{ enumSortedUsers(direction: DESC) }`, want: "ORDER BY name DESC"},
+		{name: "lower case enum literal", query: `{ enumSortedUsers(direction: desc) }`, want: "ORDER BY name DESC"},
+		{name: "mixed case string literal", query: `{ enumSortedUsers(direction: "Desc") }`, want: "ORDER BY name DESC"},
+		{
+			name:      "lower case variable",
+			query:     `query($dir: SortDirection) { enumSortedUsers(direction: $dir) }`,
+			variables: map[string]interface{}{"dir": "asc"},
+			want:      "ORDER BY name ASC",
+		},
+		{name: "default is normalized", query: `{ enumSortedUsers }`, want: "ORDER BY name ASC"},
+		{name: "invalid value is rejected", query: `{ enumSortedUsers(direction: sideways) }`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  tt.query,
+				VariableValues: tt.variables,
+			})
+
+			if tt.wantError {
+				if len(result.Errors) == 0 {
+					t.Errorf("Expected error, got %v", result.Data)
+				}
+				return
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+
+			got := result.Data.(map[string]interface{})["enumSortedUsers"]
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewCaseInsensitiveEnum_ReusesType(t *testing.T) {
+	first := NewCaseInsensitiveEnum("EnumTestStatus", "ACTIVE", "ARCHIVED")
+	second := NewCaseInsensitiveEnum("EnumTestStatus", "ACTIVE", "ARCHIVED")
+	if first != second {
+		t.Error("Expected the same type instance for the same enum name")
+	}
+
+	if got := first.Serialize("archived"); got != "ARCHIVED" {
+		t.Errorf("Serialize() = %v, want ARCHIVED", got)
+	}
+	if got := first.ParseValue("unknown"); got != nil {
+		t.Errorf("ParseValue() = %v, want nil for unknown value", got)
+	}
+}
+
+func TestNewCaseInsensitiveEnum_ConflictingValuesPanic(t *testing.T) {
+	NewCaseInsensitiveEnum("EnumTestConflict", "ACTIVE", "ARCHIVED")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an existing enum name with different values")
+		}
+	}()
+	NewCaseInsensitiveEnum("EnumTestConflict", "ACTIVE", "DELETED")
+}
+
+type enumTestInterviewState string
+
+const (
+	enumTestInterviewStarted enumTestInterviewState = "started"
+	enumTestInterviewEnded   enumTestInterviewState = "ended"
+)
+
+func TestRegisterEnum(t *testing.T) {
+	stateEnum := RegisterEnum("EnumTestInterviewState", map[string]enumTestInterviewState{
+		"STARTED": enumTestInterviewStarted,
+		"ENDED":   enumTestInterviewEnded,
+	})
+
+	type EnumTestInterview struct {
+		ID    int                    `json:"id"`
+		State enumTestInterviewState `json:"state"`
+	}
+	type EnumTestInterviewArgs struct {
+		State enumTestInterviewState `graphql:"state,required"`
+	}
+
+	interview := NewResolver[EnumTestInterview]("enumTestInterview").
+		WithArgsFromStruct(EnumTestInterviewArgs{}).
+		WithResolver(func(p ResolveParams) (*EnumTestInterview, error) {
+			var args EnumTestInterviewArgs
+			if err := GetArg(p, "state", &args.State); err != nil {
+				return nil, err
+			}
+			return &EnumTestInterview{ID: 1, State: args.State}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{interview},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	if RegisterEnum("Other", map[string]enumTestInterviewState{}) != stateEnum {
+		t.Error("Expected RegisterEnum to return the existing enum for the same type")
+	}
+
+	stateField := schema.Type("EnumTestInterview").(*graphql.Object).Fields()["state"]
+	if stateField.Type != stateEnum {
+		t.Errorf("Expected state field of type %s, got %s", stateEnum, stateField.Type)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      string
+		wantError bool
+	}{
+		{name: "enum member", query: `{ enumTestInterview(state: ENDED) { state } }`, want: "ENDED"},
+		{name: "unknown member", query: `{ enumTestInterview(state: PAUSED) { state } }`, wantError: true},
+		{name: "string literal", query: `{ enumTestInterview(state: "ENDED") { state } }`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tt.query})
+
+			if tt.wantError {
+				if len(result.Errors) == 0 {
+					t.Errorf("Expected error, got %v", result.Data)
+				}
+				return
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			got := result.Data.(map[string]interface{})["enumTestInterview"].(map[string]interface{})["state"]
+			if got != tt.want {
+				t.Errorf("Expected state %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+type enumTestPriority int
+
+const (
+	enumTestPriorityLow enumTestPriority = iota
+	enumTestPriorityMedium
+	enumTestPriorityHigh
+)
+
+func TestRegisterEnum_IntBacked(t *testing.T) {
+	priorityEnum := RegisterEnum("EnumTestPriority", map[string]enumTestPriority{
+		"HIGH":   enumTestPriorityHigh,
+		"LOW":    enumTestPriorityLow,
+		"MEDIUM": enumTestPriorityMedium,
+	})
+	if want := "One of LOW, MEDIUM, HIGH"; priorityEnum.Description() != want {
+		t.Errorf("Expected members ordered by value %q, got %q", want, priorityEnum.Description())
+	}
+
+	type EnumTestTask struct {
+		Title    string           `json:"title"`
+		Priority enumTestPriority `json:"priority"`
+	}
+	type EnumTestTaskInput struct {
+		Title    string           `json:"title"`
+		Priority enumTestPriority `json:"priority"`
+	}
+
+	var received enumTestPriority
+	createTask := NewResolver[EnumTestTask]("enumTestCreateTask").
+		WithInputObject(EnumTestTaskInput{}).
+		WithResolver(func(p ResolveParams) (*EnumTestTask, error) {
+			var input EnumTestTaskInput
+			if err := GetArg(p, "input", &input); err != nil {
+				return nil, err
+			}
+			received = input.Priority
+			// Escalate the task to show the returned int is serialized by name
+			return &EnumTestTask{Title: input.Title, Priority: input.Priority + 1}, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{getDefaultHelloQuery()},
+		MutationFields: []MutationField{createTask},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	if field := schema.Type("EnumTestTask").(*graphql.Object).Fields()["priority"]; field.Type != priorityEnum {
+		t.Errorf("Expected priority field of type %s, got %s", priorityEnum, field.Type)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+	}{
+		{name: "enum literal", query: `mutation { enumTestCreateTask(input: {title: "deploy", priority: MEDIUM}) { title priority } }`},
+		{
+			name:      "variable",
+			query:     `mutation Create($input: EnumTestTaskInputInput!) { enumTestCreateTask(input: $input) { title priority } }`,
+			variables: map[string]interface{}{"input": map[string]interface{}{"title": "deploy", "priority": "MEDIUM"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = -1
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tt.query, VariableValues: tt.variables})
+			if len(result.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			if received != enumTestPriorityMedium {
+				t.Errorf("Expected the resolver to receive %d, got %d", enumTestPriorityMedium, received)
+			}
+			task := result.Data.(map[string]interface{})["enumTestCreateTask"].(map[string]interface{})
+			if task["priority"] != "HIGH" {
+				t.Errorf("Expected priority HIGH, got %v", task["priority"])
+			}
+		})
+	}
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `mutation { enumTestCreateTask(input: {title: "x", priority: 2}) { title } }`})
+	if len(result.Errors) == 0 {
+		t.Error("Expected an int literal to be rejected")
+	}
+}
+
+func TestNewHTTP_EnumErrorsListAllowedValues(t *testing.T) {
+	type EnumTestReviewArgs struct {
+		State enumTestInterviewState `graphql:"state,required"`
+	}
+	RegisterEnum("EnumTestInterviewState", map[string]enumTestInterviewState{
+		"STARTED": enumTestInterviewStarted,
+		"ENDED":   enumTestInterviewEnded,
+	})
+
+	users := NewResolver[string]("enumErrorUsers").
+		WithEnumArg("direction", SortDirection, "").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			direction, _ := GetArgString(p, "direction")
+			return &direction, nil
+		}).
+		BuildQuery()
+	reviews := NewResolver[string]("enumErrorReviews").
+		WithArgsFromStruct(EnumTestReviewArgs{}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			state := "reviewed"
+			return &state, nil
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{users, reviews}},
+	})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "enum literal",
+			query: `{ enumErrorUsers(direction: sideways) }`,
+			want:  `invalid value "sideways" for direction; allowed: [ASC, DESC]`,
+		},
+		{
+			name:  "string literal",
+			query: `{ enumErrorUsers(direction: "up") }`,
+			want:  `invalid value "up" for direction; allowed: [ASC, DESC]`,
+		},
+		{
+			name:      "variable",
+			query:     `query($dir: SortDirection) { enumErrorUsers(direction: $dir) }`,
+			variables: map[string]interface{}{"dir": "left"},
+			want:      `invalid value "left" for $dir; allowed: [ASC, DESC]`,
+		},
+		{
+			name:  "registered enum",
+			query: `{ enumErrorReviews(state: PAUSED) }`,
+			want:  `invalid value "PAUSED" for state; allowed: [ENDED, STARTED]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, _ := json.Marshal(map[string]interface{}{"query": tt.query, "variables": tt.variables})
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			var response struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response %s: %v", w.Body.String(), err)
+			}
+			if len(response.Errors) != 1 {
+				t.Fatalf("Expected one error, got %s", w.Body.String())
+			}
+			if response.Errors[0].Message != tt.want {
+				t.Errorf("Expected message %q, got %q", tt.want, response.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestNewEnumValueError(t *testing.T) {
+	err := NewEnumValueError("format", "xml", []string{"csv", "json"})
+	if want := `invalid value "xml" for format; allowed: [csv, json]`; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
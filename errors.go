@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Standard resolver errors. Returning one of them, directly or wrapped with
+// fmt.Errorf("...: %w", err), gives the GraphQL error a stable extensions.code and
+// an HTTP-style extensions.status:
+//
+//	ErrNotFound        NOT_FOUND        404
+//	ErrForbidden       FORBIDDEN        403
+//	ErrUnauthenticated UNAUTHENTICATED  401
+//	ErrConflict        CONFLICT         409
+//
+// The HTTP response status is not changed (GraphQL responses are always 200).
+// They are *GraphError values, so errors.As reads their code like any GraphError.
+//
+// Example:
+//
+//	user, err := repo.FindUser(ctx, id)
+//	if errors.Is(err, sql.ErrNoRows) {
+//	    return nil, fmt.Errorf("user %d: %w", id, graph.ErrNotFound)
+//	}
+var (
+	ErrNotFound        error = NewGraphError("NOT_FOUND", "not found", map[string]interface{}{"status": http.StatusNotFound})
+	ErrForbidden       error = NewGraphError("FORBIDDEN", "forbidden", map[string]interface{}{"status": http.StatusForbidden})
+	ErrUnauthenticated error = NewGraphError("UNAUTHENTICATED", "unauthenticated", map[string]interface{}{"status": http.StatusUnauthorized})
+	ErrConflict        error = NewGraphError("CONFLICT", "conflict", map[string]interface{}{"status": http.StatusConflict})
+)
+
+// GraphError is a resolver error with a stable code for clients. The code and the
+// extensions are written to the GraphQL error's extensions, also when the error is
+// returned through middleware or wrapped with fmt.Errorf("...: %w", err).
+//
+// Example:
+//
+//	return nil, graph.NewGraphError("NOT_FOUND", "user not found", map[string]interface{}{
+//	    "id": id,
+//	})
+//
+// The response error is:
+//
+//	{"message": "user not found", "extensions": {"code": "NOT_FOUND", "id": 42}, ...}
+type GraphError struct {
+	Code    string
+	Message string
+
+	extensions map[string]interface{}
+}
+
+// NewGraphError creates a GraphError with the code, the message and the extensions
+// of the optional maps
+func NewGraphError(code string, message string, extensions ...map[string]interface{}) *GraphError {
+	merged := make(map[string]interface{})
+	for _, ext := range extensions {
+		for key, value := range ext {
+			merged[key] = value
+		}
+	}
+	return &GraphError{Code: code, Message: message, extensions: merged}
+}
+
+func (e *GraphError) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError. The code takes precedence over a
+// "code" extension.
+func (e *GraphError) Extensions() map[string]interface{} {
+	extensions := make(map[string]interface{}, len(e.extensions)+1)
+	for key, value := range e.extensions {
+		extensions[key] = value
+	}
+	if e.Code != "" {
+		extensions["code"] = e.Code
+	}
+	return extensions
+}
+
+// formatError formats an execution error for the response. The extensions of errors
+// implementing gqlerrors.ExtendedError are included even when they are wrapped, and
+// rejected values of registered enums are reported with the allowed values.
+func formatError(err error) gqlerrors.FormattedError {
+	if err == nil {
+		return gqlerrors.NewFormattedError("unknown error")
+	}
+	formatted := gqlerrors.FormatError(err)
+
+	original := err
+	if located, ok := err.(*gqlerrors.Error); ok {
+		if located.OriginalError != nil {
+			original = located.OriginalError
+		} else {
+			// Validation errors of enum values list the allowed values
+			formatted.Message = describeEnumValueError(formatted.Message)
+		}
+	}
+
+	var extended gqlerrors.ExtendedError
+	if errors.As(original, &extended) {
+		extensions := make(map[string]interface{}, len(formatted.Extensions))
+		for key, value := range formatted.Extensions {
+			extensions[key] = value
+		}
+		for key, value := range extended.Extensions() {
+			extensions[key] = value
+		}
+		formatted.Extensions = extensions
+	}
+
+	// Errors returned in DEBUG mode with IncludeStackTraceInDebug carry their stack
+	var stackErr *stackTraceError
+	if errors.As(original, &stackErr) {
+		extensions := make(map[string]interface{}, len(formatted.Extensions)+2)
+		for key, value := range formatted.Extensions {
+			extensions[key] = value
+		}
+		extensions["stacktrace"] = strings.Split(strings.TrimSpace(string(stackErr.stack)), "\n")
+		extensions["errorChain"] = errorChain(stackErr.err)
+		formatted.Extensions = extensions
+	}
+	return formatted
+}
+
+// errorStacksKey marks a request whose resolver errors carry a stack trace
+type errorStacksKey struct{}
+
+// stackTraceError is a resolver error with the stack of the resolver call that failed
+type stackTraceError struct {
+	err   error
+	stack []byte
+}
+
+func (e *stackTraceError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackTraceError) Unwrap() error {
+	return e.err
+}
+
+// withErrorStacks wraps the errors of resolver with a stack trace when the request
+// context asks for them
+func withErrorStacks(resolver func(p graphql.ResolveParams) (interface{}, error)) func(p graphql.ResolveParams) (interface{}, error) {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := resolver(p)
+		if err == nil || p.Context == nil || p.Context.Value(errorStacksKey{}) == nil {
+			return result, err
+		}
+		var stackErr *stackTraceError
+		if !errors.As(err, &stackErr) {
+			err = &stackTraceError{err: err, stack: debug.Stack()}
+		}
+		return result, err
+	}
+}
+
+// errorChain returns the messages of err and of the errors it wraps
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	return chain
+}
+
+// formatResultErrors applies formatError to the errors of a result
+func formatResultErrors(errs []gqlerrors.FormattedError) {
+	for i, err := range errs {
+		if original := err.OriginalError(); original != nil {
+			errs[i] = formatError(original)
+		}
+	}
+}
+
+// formatErrorsWith formats errors with an ErrorFormatter, dropping the errors it
+// returns nil for. The formatter gets the error returned by the resolver when there
+// is one, and the locations and path are kept unless it sets its own.
+func formatErrorsWith(formatter func(err error) map[string]interface{}, errs []gqlerrors.FormattedError) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(errs))
+	for _, err := range errs {
+		var original error = err
+		if located, ok := err.OriginalError().(*gqlerrors.Error); ok {
+			original = located
+			if located.OriginalError != nil {
+				original = located.OriginalError
+			}
+		} else if err.OriginalError() != nil {
+			original = err.OriginalError()
+		}
+
+		custom := formatter(original)
+		if custom == nil {
+			continue
+		}
+		entry := make(map[string]interface{}, len(custom)+2)
+		for key, value := range custom {
+			entry[key] = value
+		}
+		if _, ok := entry["locations"]; !ok && len(err.Locations) > 0 {
+			entry["locations"] = err.Locations
+		}
+		if _, ok := entry["path"]; !ok && len(err.Path) > 0 {
+			entry["path"] = err.Path
+		}
+		formatted = append(formatted, entry)
+	}
+	return formatted
+}
@@ -0,0 +1,318 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantStatus int
+	}{
+		{name: "not found", err: ErrNotFound, wantCode: "NOT_FOUND", wantStatus: http.StatusNotFound},
+		{name: "forbidden", err: ErrForbidden, wantCode: "FORBIDDEN", wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", err: ErrUnauthenticated, wantCode: "UNAUTHENTICATED", wantStatus: http.StatusUnauthorized},
+		{name: "conflict", err: ErrConflict, wantCode: "CONFLICT", wantStatus: http.StatusConflict},
+		{name: "wrapped", err: fmt.Errorf("user 42: %w", ErrNotFound), wantCode: "NOT_FOUND", wantStatus: http.StatusNotFound},
+	}
+
+	fields := graphql.Fields{}
+	for i, tt := range tests {
+		err := tt.err
+		fields[fmt.Sprintf("sentinel%d", i)] = &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, err
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: fields}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	handler := NewHTTP(&GraphContext{Schema: &schema})
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := bytes.NewBufferString(fmt.Sprintf(`{"query":"{ sentinel%d }"}`, i))
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			var response struct {
+				Errors []struct {
+					Message    string                 `json:"message"`
+					Extensions map[string]interface{} `json:"extensions"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Errors) != 1 {
+				t.Fatalf("Expected 1 error, got %s", w.Body.String())
+			}
+
+			got := response.Errors[0]
+			if got.Message != tt.err.Error() {
+				t.Errorf("Expected message %q, got %q", tt.err.Error(), got.Message)
+			}
+			if got.Extensions["code"] != tt.wantCode {
+				t.Errorf("Expected code %s, got %v", tt.wantCode, got.Extensions["code"])
+			}
+			if got.Extensions["status"] != float64(tt.wantStatus) {
+				t.Errorf("Expected status %d, got %v", tt.wantStatus, got.Extensions["status"])
+			}
+
+			var graphErr *GraphError
+			if !errors.As(tt.err, &graphErr) || graphErr.Code != tt.wantCode {
+				t.Errorf("Expected a GraphError with code %s, got %#v", tt.wantCode, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewGraphError(t *testing.T) {
+	graphErr := NewGraphError("NOT_FOUND", "user not found", map[string]interface{}{"id": 42})
+	resolvers := map[string]func(p ResolveParams) (*string, error){
+		"direct": func(p ResolveParams) (*string, error) {
+			return nil, graphErr
+		},
+		"wrapped": func(p ResolveParams) (*string, error) {
+			return nil, fmt.Errorf("load user: %w", graphErr)
+		},
+	}
+
+	var fields []QueryField
+	for name, resolve := range resolvers {
+		fields = append(fields, NewResolver[string]("graphError"+name).
+			WithMiddleware(func(next FieldResolveFn) FieldResolveFn {
+				return func(p ResolveParams) (interface{}, error) {
+					return next(p)
+				}
+			}).
+			WithResolver(resolve).
+			BuildQuery())
+	}
+	handler := NewHTTP(&GraphContext{
+		SchemaParams:       &SchemaBuilderParams{QueryFields: fields},
+		EnableSanitization: true,
+	})
+
+	for name := range resolvers {
+		t.Run(name, func(t *testing.T) {
+			body := bytes.NewBufferString(fmt.Sprintf(`{"query":"{ graphError%s }"}`, name))
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			var response struct {
+				Errors []struct {
+					Message    string                 `json:"message"`
+					Extensions map[string]interface{} `json:"extensions"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Errors) != 1 {
+				t.Fatalf("Expected 1 error, got %s", w.Body.String())
+			}
+			if code := response.Errors[0].Extensions["code"]; code != "NOT_FOUND" {
+				t.Errorf("Expected code NOT_FOUND, got %v", code)
+			}
+			if id := response.Errors[0].Extensions["id"]; id != float64(42) {
+				t.Errorf("Expected id extension 42, got %v", id)
+			}
+		})
+	}
+}
+
+func TestNewHTTP_ErrorFormatter(t *testing.T) {
+	errDatabase := errors.New("pq: connection refused")
+	errIgnored := errors.New("ignored")
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"account": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, fmt.Errorf("load account: %w", errDatabase)
+				},
+			},
+			"ignored": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errIgnored
+				},
+			},
+		}}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	var validationErrs []error
+	handler := NewHTTP(&GraphContext{
+		Schema:           &schema,
+		EnableValidation: true,
+		MaxDepth:         2,
+		ErrorFormatter: func(err error) map[string]interface{} {
+			switch {
+			case errors.Is(err, errDatabase):
+				return map[string]interface{}{
+					"message":    "internal error",
+					"extensions": map[string]interface{}{"code": "INTERNAL"},
+				}
+			case errors.Is(err, errIgnored):
+				return nil
+			}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				validationErrs = append(validationErrs, err)
+				return map[string]interface{}{
+					"message":    "invalid query",
+					"extensions": map[string]interface{}{"code": validationErr.Rule},
+				}
+			}
+			return map[string]interface{}{"message": err.Error()}
+		},
+	})
+
+	type responseError struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	}
+	serve := func(query string) (int, []responseError) {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		var response struct {
+			Errors []responseError `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return w.Code, response.Errors
+	}
+
+	t.Run("resolver errors", func(t *testing.T) {
+		_, errs := serve("{ account ignored }")
+		if len(errs) != 1 {
+			t.Fatalf("Expected the ignored error to be dropped, got %+v", errs)
+		}
+		if errs[0].Message != "internal error" || errs[0].Extensions["code"] != "INTERNAL" {
+			t.Errorf("Expected the formatted error, got %+v", errs[0])
+		}
+		if len(errs[0].Path) != 1 || errs[0].Path[0] != "account" {
+			t.Errorf("Expected the path to be kept, got %v", errs[0].Path)
+		}
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		code, errs := serve("{ __schema { types { fields { name } } } }")
+		if code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", code)
+		}
+		if len(errs) == 0 || errs[0].Message != "invalid query" {
+			t.Fatalf("Expected the formatted validation error, got %+v", errs)
+		}
+		if len(validationErrs) == 0 {
+			t.Errorf("Expected the formatter to get the *ValidationError")
+		}
+	})
+}
+
+type StackTraceTestAccount struct {
+	ID int `json:"id"`
+}
+
+func TestNewHTTP_IncludeStackTraceInDebug(t *testing.T) {
+	errDatabase := errors.New("pq: connection refused")
+	account := NewResolver[StackTraceTestAccount]("stackTraceAccount").
+		WithResolver(func(p ResolveParams) (*StackTraceTestAccount, error) {
+			return nil, fmt.Errorf("load account: %w", errDatabase)
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{account}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		debug     bool
+		wantStack bool
+	}{
+		{name: "debug", debug: true, wantStack: true},
+		{name: "production", debug: false, wantStack: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				Schema:                   &schema,
+				DEBUG:                    tt.debug,
+				IncludeStackTraceInDebug: true,
+			})
+			body, _ := json.Marshal(map[string]string{"query": "{ stackTraceAccount { id } }"})
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			var resp struct {
+				Errors []struct {
+					Message    string                 `json:"message"`
+					Extensions map[string]interface{} `json:"extensions"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Errors) != 1 {
+				t.Fatalf("Expected 1 error, got %s", w.Body.String())
+			}
+			if resp.Errors[0].Message != "load account: pq: connection refused" {
+				t.Errorf("Unexpected message %q", resp.Errors[0].Message)
+			}
+
+			stack, hasStack := resp.Errors[0].Extensions["stacktrace"].([]interface{})
+			if hasStack != tt.wantStack {
+				t.Fatalf("Expected stacktrace present = %v, got %v", tt.wantStack, resp.Errors[0].Extensions)
+			}
+			if !tt.wantStack {
+				if _, ok := resp.Errors[0].Extensions["errorChain"]; ok {
+					t.Errorf("Expected no errorChain in production, got %v", resp.Errors[0].Extensions)
+				}
+				return
+			}
+			if len(stack) == 0 || !strings.HasPrefix(stack[0].(string), "goroutine") {
+				t.Errorf("Expected a goroutine stack, got %v", stack)
+			}
+			chain := fmt.Sprint(resp.Errors[0].Extensions["errorChain"])
+			if chain != "[load account: pq: connection refused pq: connection refused]" {
+				t.Errorf("Unexpected errorChain %s", chain)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ContextMiddleware derives the context of a field from its resolve params.
+// The returned context replaces p.Context for the middleware that follow, the resolver,
+// and the field resolvers nested under the field's result (WithFieldResolver overrides
+// with the WithFieldMiddleware of the overridden field, and custom fields, including
+// nested resolvers built with NewResolver). Fields resolved from the struct itself
+// don't receive a context.
+// Returning a nil context keeps the current one; returning an error fails the field
+// without calling the resolver.
+//
+// graphql-go passes the same context to every field of an operation, so nested fields
+// find the derived context through a request-scoped table. NewHTTP installs the table
+// for every request; use WithFieldContexts when executing queries with graphql.Do.
+//
+// Example:
+//
+//	NewResolver[Tenant]("tenant").
+//	    WithContextMiddleware(func(p graph.ResolveParams) (context.Context, error) {
+//	        tenant, err := tenants.Lookup(p.Context, p.Args["slug"].(string))
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return context.WithValue(p.Context, tenantKey{}, tenant), nil
+//	    }).
+//	    WithResolver(func(p graph.ResolveParams) (*Tenant, error) {
+//	        return p.Context.Value(tenantKey{}).(*Tenant), nil
+//	    }).
+//	    BuildQuery()
+type ContextMiddleware func(p ResolveParams) (context.Context, error)
+
+// WithContextMiddleware adds a middleware that derives the context seen by the resolver
+// and the fields nested under it. It runs in order with the middleware added by WithMiddleware.
+func (r *UnifiedResolver[T]) WithContextMiddleware(middleware ContextMiddleware) *UnifiedResolver[T] {
+	r.resolverMiddlewares = append(r.resolverMiddlewares, contextMiddleware(middleware))
+	return r
+}
+
+// contextMiddleware adapts a ContextMiddleware to a FieldMiddleware
+func contextMiddleware(middleware ContextMiddleware) FieldMiddleware {
+	return func(next FieldResolveFn) FieldResolveFn {
+		return func(p ResolveParams) (interface{}, error) {
+			ctx, err := middleware(p)
+			if err != nil {
+				return nil, err
+			}
+			if ctx != nil {
+				if p.Context != nil && p.Info.Path != nil {
+					if contexts, ok := p.Context.Value(fieldContextsKey{}).(*fieldContexts); ok {
+						contexts.set(responsePathKey(p.Info.Path), ctx)
+					}
+				}
+				p.Context = ctx
+			}
+			return next(p)
+		}
+	}
+}
+
+// fieldContexts holds the contexts derived by ContextMiddleware during a request,
+// keyed by the response path of the field that derived them
+type fieldContexts struct {
+	mu     sync.RWMutex
+	byPath map[string]context.Context
+}
+
+func (c *fieldContexts) set(path string, ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byPath[path] = ctx
+}
+
+// nearest returns the context derived by the closest enclosing field of path
+func (c *fieldContexts) nearest(path *graphql.ResponsePath) (context.Context, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.byPath) == 0 {
+		return nil, false
+	}
+	for parent := path.Prev; parent != nil; parent = parent.Prev {
+		if ctx, exists := c.byPath[responsePathKey(parent)]; exists {
+			return ctx, true
+		}
+	}
+	return nil, false
+}
+
+// fieldContextsKey is the context key for the request's fieldContexts
+type fieldContextsKey struct{}
+
+// WithFieldContexts returns a copy of ctx that lets fields observe contexts derived by
+// ContextMiddleware on their enclosing fields. NewHTTP does this for every request;
+// use it directly when executing queries with graphql.Do.
+func WithFieldContexts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fieldContextsKey{}, &fieldContexts{byPath: make(map[string]context.Context)})
+}
+
+// withFieldContext runs resolver with the context derived for the nearest enclosing field
+func withFieldContext(resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if p.Context != nil && p.Info.Path != nil {
+			if contexts, ok := p.Context.Value(fieldContextsKey{}).(*fieldContexts); ok {
+				if ctx, found := contexts.nearest(p.Info.Path); found {
+					p.Context = ctx
+				}
+			}
+		}
+		return resolver(p)
+	}
+}
+
+// responsePathKey formats a response path such as users.0.posts
+func responsePathKey(path *graphql.ResponsePath) string {
+	parts := path.AsArray()
+	keys := make([]string, len(parts))
+	for i, part := range parts {
+		keys[i] = fmt.Sprint(part)
+	}
+	return strings.Join(keys, ".")
+}
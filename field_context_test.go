@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type fieldContextTestTenantKey struct{}
+
+type FieldContextTestTenant struct {
+	Slug string `json:"slug"`
+}
+
+func TestWithContextMiddleware(t *testing.T) {
+	tenantFromContext := func(ctx context.Context) string {
+		tenant, _ := ctx.Value(fieldContextTestTenantKey{}).(string)
+		return tenant
+	}
+
+	tenant := NewResolver[FieldContextTestTenant]("fieldContextTenant").
+		WithArgs(graphql.FieldConfigArgument{
+			"slug": &graphql.ArgumentConfig{Type: graphql.String},
+		}).
+		WithContextMiddleware(func(p ResolveParams) (context.Context, error) {
+			slug, _ := p.Args["slug"].(string)
+			if slug == "" {
+				return nil, errors.New("tenant not found")
+			}
+			return context.WithValue(p.Context, fieldContextTestTenantKey{}, "tenant-"+slug), nil
+		}).
+		WithComputedField("contextTenant", graphql.String, func(p graphql.ResolveParams) (interface{}, error) {
+			return tenantFromContext(p.Context), nil
+		}).
+		WithResolver(func(p ResolveParams) (*FieldContextTestTenant, error) {
+			return &FieldContextTestTenant{Slug: tenantFromContext(p.Context)}, nil
+		}).
+		BuildQuery()
+
+	plain := NewResolver[string]("fieldContextPlain").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			value := "none"
+			if tenant := tenantFromContext(p.Context); tenant != "" {
+				value = tenant
+			}
+			return &value, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{tenant, plain},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	t.Run("resolver and nested fields observe the derived context", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ a: fieldContextTenant(slug: "a") { slug contextTenant } b: fieldContextTenant(slug: "b") { contextTenant } fieldContextPlain }`,
+			Context:       WithFieldContexts(context.Background()),
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		data := result.Data.(map[string]interface{})
+		a := data["a"].(map[string]interface{})
+		if a["slug"] != "tenant-a" {
+			t.Errorf("Expected resolver to see tenant-a, got %v", a["slug"])
+		}
+		if a["contextTenant"] != "tenant-a" {
+			t.Errorf("Expected nested field to see tenant-a, got %v", a["contextTenant"])
+		}
+		if b := data["b"].(map[string]interface{}); b["contextTenant"] != "tenant-b" {
+			t.Errorf("Expected nested field to see tenant-b, got %v", b["contextTenant"])
+		}
+		if data["fieldContextPlain"] != "none" {
+			t.Errorf("Expected sibling field not to see the derived context, got %v", data["fieldContextPlain"])
+		}
+	})
+
+	t.Run("resolver observes the derived context without field contexts", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ fieldContextTenant(slug: "a") { slug } }`,
+			Context:       context.Background(),
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		got := result.Data.(map[string]interface{})["fieldContextTenant"].(map[string]interface{})
+		if got["slug"] != "tenant-a" {
+			t.Errorf("Expected resolver to see tenant-a, got %v", got["slug"])
+		}
+	})
+
+	t.Run("error fails the field", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ fieldContextTenant { slug } }`,
+			Context:       WithFieldContexts(context.Background()),
+		})
+		if len(result.Errors) == 0 || result.Errors[0].Message != "tenant not found" {
+			t.Errorf("Expected error 'tenant not found', got %v", result.Errors)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultFieldNameTags are the struct tags field names are read from by default
+var defaultFieldNameTags = []string{"json", "graphql"}
+
+// fieldNameTags holds the []string of tags consulted for field names, in priority order
+var fieldNameTags atomic.Value
+
+// SetFieldNameTags sets the struct tags that GraphQL field, argument and input field
+// names are read from, in priority order. The first tag that names the field wins;
+// fields without any of the tags use their Go name in camelCase. The default is
+// "json", then "graphql". Types already generated keep their field names, so call it
+// before building resolvers and schemas. Calling it without tags restores the default.
+//
+// Example:
+//
+//	graph.SetFieldNameTags("graphql", "json", "db")
+//
+//	type User struct {
+//	    ID       int    `db:"id"`
+//	    FullName string `db:"full_name"` // full_name: String
+//	}
+func SetFieldNameTags(tags ...string) {
+	if len(tags) == 0 {
+		tags = defaultFieldNameTags
+	}
+	fieldNameTags.Store(append([]string(nil), tags...))
+}
+
+// taggedFieldName returns the field name given by the first configured tag that
+// names the field. The graphql tag names the field with its first part that is not
+// an option (required, nullable or key=value).
+func taggedFieldName(field reflect.StructField) (string, bool) {
+	tags, _ := fieldNameTags.Load().([]string)
+	if tags == nil {
+		tags = defaultFieldNameTags
+	}
+	for _, tag := range tags {
+		value := field.Tag.Get(tag)
+		if value == "" {
+			continue
+		}
+		if tag != "graphql" {
+			if name, _, _ := strings.Cut(value, ","); name != "" {
+				return name, true
+			}
+			continue
+		}
+		for _, part := range strings.Split(value, ",") {
+			if !isGraphQLTagOption(part) {
+				return part, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isGraphQLTagOption reports whether a part of a graphql tag is one of the options
+// that can precede the field name (required, nullable or key=value)
+func isGraphQLTagOption(part string) bool {
+	return strings.Contains(part, "=") || part == "required" || part == "nullable"
+}
+
+// graphqlTagOptions returns the parts of a graphql tag other than the one naming the
+// field, which is its first part that is not an option
+func graphqlTagOptions(tag string) []string {
+	parts := strings.Split(tag, ",")
+	for i, part := range parts {
+		if !isGraphQLTagOption(part) {
+			return append(parts[:i:i], parts[i+1:]...)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type FieldNameTagsAccount struct {
+	ID          int    `db:"account_id"`
+	DisplayName string `db:"display_name" json:"displayName"`
+	Email       string `graphql:"contactEmail,required" db:"email"`
+	CreatedBy   string
+}
+
+type FieldNameTagsFilter struct {
+	MinBalance int `db:"min_balance"`
+}
+
+func TestSetFieldNameTags(t *testing.T) {
+	SetFieldNameTags("graphql", "json", "db")
+	defer SetFieldNameTags()
+
+	var gotFilter FieldNameTagsFilter
+	account := NewResolver[FieldNameTagsAccount]("fieldNameTagsAccount").
+		WithArgsFromStruct(FieldNameTagsFilter{}).
+		WithResolver(func(p ResolveParams) (*FieldNameTagsAccount, error) {
+			if err := mapArgsToStruct(p.Args, &gotFilter); err != nil {
+				return nil, err
+			}
+			return &FieldNameTagsAccount{ID: 7, DisplayName: "Ada", Email: "ada@example.com", CreatedBy: "admin"}, nil
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{account}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	fields := schema.Type("FieldNameTagsAccount").(*graphql.Object).Fields()
+	for _, name := range []string{"account_id", "displayName", "contactEmail", "createdBy"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("Expected field %s, got %v", name, fields)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ fieldNameTagsAccount(min_balance: 100) { account_id displayName contactEmail createdBy } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	got := fmt.Sprint(result.Data.(map[string]interface{})["fieldNameTagsAccount"])
+	if want := "map[account_id:7 contactEmail:ada@example.com createdBy:admin displayName:Ada]"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if gotFilter.MinBalance != 100 {
+		t.Errorf("Expected the db-named argument to decode, got %+v", gotFilter)
+	}
+}
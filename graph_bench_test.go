@@ -1200,3 +1200,31 @@ func BenchmarkGenerateGraphQLFields_ComplexEmbedding(b *testing.B) {
 		_ = GenerateGraphQLFields[Organization]()
 	}
 }
+
+// Benchmark repeated Serve calls, which reuse the output type assembled on the first call
+func BenchmarkUnifiedResolver_Serve(b *testing.B) {
+	type ServeBenchUser struct {
+		ID        int      `json:"id"`
+		Name      string   `json:"name"`
+		Email     string   `json:"email"`
+		Age       int      `json:"age"`
+		IsActive  bool     `json:"isActive"`
+		Tags      []string `json:"tags"`
+		CreatedAt string   `json:"createdAt"`
+	}
+
+	resolver := NewResolver[ServeBenchUser]("serveBenchUsers").
+		AsPaginated().
+		WithFieldResolver("name", func(p graphql.ResolveParams) (interface{}, error) {
+			return "override", nil
+		}).
+		WithResolver(func(p ResolveParams) (*ServeBenchUser, error) {
+			return &ServeBenchUser{}, nil
+		})
+	resolver.Serve()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = resolver.Serve()
+	}
+}
@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -163,6 +169,168 @@ func TestGetArgBool(t *testing.T) {
 	}
 }
 
+func TestGetArgFloat(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		key       string
+		want      float64
+		wantError bool
+	}{
+		{
+			name:      "valid float argument",
+			args:      map[string]interface{}{"price": 9.99},
+			key:       "price",
+			want:      9.99,
+			wantError: false,
+		},
+		{
+			name:      "int argument",
+			args:      map[string]interface{}{"price": 10},
+			key:       "price",
+			want:      10,
+			wantError: false,
+		},
+		{
+			name:      "missing argument",
+			args:      map[string]interface{}{},
+			key:       "price",
+			want:      0,
+			wantError: true,
+		},
+		{
+			name:      "wrong type argument",
+			args:      map[string]interface{}{"price": "cheap"},
+			key:       "price",
+			want:      0,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := graphql.ResolveParams{Args: tt.args}
+			got, err := GetArgFloat(ResolveParams(params), tt.key)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("GetArgFloat() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetArgFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArgStringSlice(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		key       string
+		want      []string
+		wantError bool
+	}{
+		{
+			name:      "valid list argument",
+			args:      map[string]interface{}{"tags": []interface{}{"go", "graphql"}},
+			key:       "tags",
+			want:      []string{"go", "graphql"},
+			wantError: false,
+		},
+		{
+			name:      "empty list argument",
+			args:      map[string]interface{}{"tags": []interface{}{}},
+			key:       "tags",
+			want:      []string{},
+			wantError: false,
+		},
+		{
+			name:      "missing argument",
+			args:      map[string]interface{}{},
+			key:       "tags",
+			want:      nil,
+			wantError: true,
+		},
+		{
+			name:      "not a list",
+			args:      map[string]interface{}{"tags": "go"},
+			key:       "tags",
+			want:      nil,
+			wantError: true,
+		},
+		{
+			name:      "wrong element type",
+			args:      map[string]interface{}{"tags": []interface{}{"go", 1}},
+			key:       "tags",
+			want:      nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := graphql.ResolveParams{Args: tt.args}
+			got, err := GetArgStringSlice(ResolveParams(params), tt.key)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("GetArgStringSlice() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetArgStringSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArgIntSlice(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		key       string
+		want      []int
+		wantError bool
+	}{
+		{
+			name:      "valid list argument",
+			args:      map[string]interface{}{"ids": []interface{}{1, float64(2)}},
+			key:       "ids",
+			want:      []int{1, 2},
+			wantError: false,
+		},
+		{
+			name:      "missing argument",
+			args:      map[string]interface{}{},
+			key:       "ids",
+			want:      nil,
+			wantError: true,
+		},
+		{
+			name:      "wrong element type",
+			args:      map[string]interface{}{"ids": []interface{}{1, "two"}},
+			key:       "ids",
+			want:      nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := graphql.ResolveParams{Args: tt.args}
+			got, err := GetArgIntSlice(ResolveParams(params), tt.key)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("GetArgIntSlice() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetArgIntSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetArg(t *testing.T) {
 	type Input struct {
 		Name  string `json:"name"`
@@ -377,6 +545,53 @@ func TestExtractBearerToken(t *testing.T) {
 	}
 }
 
+func TestTokenExtractors(t *testing.T) {
+	tests := []struct {
+		name      string
+		extractor func(*http.Request) string
+		header    string
+		cookie    string
+		query     string
+		want      string
+	}{
+		{name: "cookie", extractor: ExtractCookieToken("session"), cookie: "cookie-token", want: "cookie-token"},
+		{name: "missing cookie", extractor: ExtractCookieToken("session"), header: "Bearer header-token", want: ""},
+		{name: "query", extractor: ExtractQueryToken("token"), query: "?token=query-token", want: "query-token"},
+		{name: "missing query", extractor: ExtractQueryToken("token"), query: "?other=value", want: ""},
+		{
+			name:      "chain uses the first token",
+			extractor: ChainExtractors(ExtractBearerToken, ExtractCookieToken("session"), ExtractQueryToken("token")),
+			header:    "Bearer header-token",
+			cookie:    "cookie-token",
+			query:     "?token=query-token",
+			want:      "header-token",
+		},
+		{
+			name:      "chain falls through to later extractors",
+			extractor: ChainExtractors(ExtractBearerToken, nil, ExtractCookieToken("session"), ExtractQueryToken("token")),
+			query:     "?token=query-token",
+			want:      "query-token",
+		},
+		{name: "empty chain", extractor: ChainExtractors(), header: "Bearer header-token", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/graphql"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+
+			if got := tt.extractor(req); got != tt.want {
+				t.Errorf("extractor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // Test Schema Builder
 
 func TestSchemaBuilder_Simple(t *testing.T) {
@@ -779,6 +994,68 @@ func TestValidateGraphQLQuery_Introspection(t *testing.T) {
 	}
 }
 
+func TestValidateGraphQLQuery_Limits(t *testing.T) {
+	schema, _ := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{getDefaultHelloQuery()},
+	}).Build()
+
+	deepQuery := "{ " + strings.Repeat("a { ", 11) + "b" + strings.Repeat(" }", 11) + " }"
+	aliasQuery := "{ a1: hello a2: hello a3: hello a4: hello a5: hello }"
+
+	tests := []struct {
+		name      string
+		query     string
+		limits    []QueryLimits
+		wantError bool
+	}{
+		{name: "default depth", query: deepQuery, wantError: true},
+		{name: "raised depth", query: deepQuery, limits: []QueryLimits{{MaxDepth: 15, MaxComplexity: 5000}}},
+		{name: "default aliases", query: aliasQuery, wantError: true},
+		{name: "raised aliases", query: aliasQuery, limits: []QueryLimits{{MaxAliases: 5}}},
+		{name: "unset limits keep defaults", query: aliasQuery, limits: []QueryLimits{{MaxDepth: 15}}, wantError: true},
+		{name: "lowered complexity", query: aliasQuery, limits: []QueryLimits{{MaxAliases: 5, MaxComplexity: 4}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGraphQLQuery(tt.query, &schema, tt.limits...)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateGraphQLQuery() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestNewHTTP_ValidationLimits(t *testing.T) {
+	aliasQuery := `{"query":"{ a1: hello a2: hello a3: hello a4: hello a5: hello }"}`
+
+	tests := []struct {
+		name       string
+		maxAliases int
+		wantError  bool
+	}{
+		{name: "default limit", wantError: true},
+		{name: "raised limit", maxAliases: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				EnableValidation: true,
+				MaxAliases:       tt.maxAliases,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(aliasQuery))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if gotError := strings.Contains(w.Body.String(), "MaxAliasesRule"); gotError != tt.wantError {
+				t.Errorf("Expected alias error %v, got %d: %s", tt.wantError, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 // Test HTTP Handler
 
 func TestNewHTTP_DefaultSchema(t *testing.T) {
@@ -930,6 +1207,164 @@ func TestLoggingMiddleware(t *testing.T) {
 	}
 }
 
+func TestRetryMiddleware(t *testing.T) {
+	transient := fmt.Errorf("service unavailable")
+
+	calls := 0
+	resolver := func(p ResolveParams) (interface{}, error) {
+		calls++
+		if calls <= 2 {
+			return nil, transient
+		}
+		return "success", nil
+	}
+
+	var backoffs []int
+	wrapped := RetryMiddleware(3,
+		func(attempt int) time.Duration {
+			backoffs = append(backoffs, attempt)
+			return time.Millisecond
+		},
+		func(err error) bool { return err == transient },
+	)(resolver)
+
+	result, err := wrapped(ResolveParams(graphql.ResolveParams{Context: context.Background()}))
+	if err != nil {
+		t.Fatalf("RetryMiddleware() error = %v", err)
+	}
+	if result != "success" {
+		t.Errorf("RetryMiddleware() result = %v, want 'success'", result)
+	}
+	if calls != 3 {
+		t.Errorf("Resolver should be called 3 times, called %d times", calls)
+	}
+	if len(backoffs) != 2 || backoffs[0] != 1 || backoffs[1] != 2 {
+		t.Errorf("Expected backoff for attempts [1 2], got %v", backoffs)
+	}
+}
+
+func TestRetryMiddleware_NonRetryableError(t *testing.T) {
+	permanent := fmt.Errorf("not found")
+
+	calls := 0
+	resolver := func(p ResolveParams) (interface{}, error) {
+		calls++
+		return nil, permanent
+	}
+
+	wrapped := RetryMiddleware(5, nil, func(err error) bool { return false })(resolver)
+
+	_, err := wrapped(ResolveParams(graphql.ResolveParams{Context: context.Background()}))
+	if err != permanent {
+		t.Errorf("RetryMiddleware() error = %v, want %v", err, permanent)
+	}
+	if calls != 1 {
+		t.Errorf("Non-retryable error should short-circuit, resolver called %d times", calls)
+	}
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	downstream := fmt.Errorf("downstream unavailable")
+
+	calls := 0
+	healthy := false
+	resolver := func(p ResolveParams) (interface{}, error) {
+		calls++
+		if !healthy {
+			return nil, downstream
+		}
+		return "ok", nil
+	}
+
+	wrapped := CircuitBreakerMiddleware(CircuitBreakerOptions{
+		FailureThreshold: 3,
+		Cooldown:         20 * time.Millisecond,
+	})(resolver)
+	params := ResolveParams(graphql.ResolveParams{Context: context.Background()})
+
+	// Drive failures until the breaker opens
+	for i := 0; i < 3; i++ {
+		if _, err := wrapped(params); err != downstream {
+			t.Fatalf("call %d: error = %v, want %v", i+1, err, downstream)
+		}
+	}
+
+	// While open, calls fail fast without reaching the resolver
+	if _, err := wrapped(params); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen while open, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected resolver to be called 3 times, called %d times", calls)
+	}
+
+	// After the cooldown a failing trial call reopens the breaker
+	time.Sleep(30 * time.Millisecond)
+	if _, err := wrapped(params); err != downstream {
+		t.Fatalf("Expected the trial call to reach the resolver, got %v", err)
+	}
+	if _, err := wrapped(params); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after a failed trial, got %v", err)
+	}
+
+	// A successful trial call closes the breaker
+	healthy = true
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		result, err := wrapped(params)
+		if err != nil || result != "ok" {
+			t.Fatalf("Expected recovery after cooldown, got %v, %v", result, err)
+		}
+	}
+	if calls != 6 {
+		t.Errorf("Expected resolver to be called 6 times, called %d times", calls)
+	}
+}
+
+func TestCircuitBreakerMiddleware_PanickingTrial(t *testing.T) {
+	panicking := true
+	resolver := func(p ResolveParams) (interface{}, error) {
+		if panicking {
+			panic("downstream client crashed")
+		}
+		return "ok", nil
+	}
+
+	wrapped := CircuitBreakerMiddleware(CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         20 * time.Millisecond,
+	})(resolver)
+	params := ResolveParams(graphql.ResolveParams{Context: context.Background()})
+
+	call := func() (result interface{}, panicked bool, err error) {
+		defer func() {
+			if recover() != nil {
+				panicked = true
+			}
+		}()
+		result, err = wrapped(params)
+		return result, false, err
+	}
+
+	// A panicking call opens the breaker like a failure
+	if _, panicked, _ := call(); !panicked {
+		t.Fatal("Expected the panic to propagate")
+	}
+	if _, _, err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after a panic, got %v", err)
+	}
+
+	// A panicking trial call reopens the breaker instead of blocking later trials
+	time.Sleep(30 * time.Millisecond)
+	if _, panicked, _ := call(); !panicked {
+		t.Fatal("Expected the trial call to reach the resolver")
+	}
+	panicking = false
+	time.Sleep(30 * time.Millisecond)
+	if result, _, err := call(); err != nil || result != "ok" {
+		t.Fatalf("Expected a trial call after the cooldown to recover, got %v, %v", result, err)
+	}
+}
+
 func TestCachedFieldResolver(t *testing.T) {
 	callCount := 0
 	resolver := func(p graphql.ResolveParams) (interface{}, error) {
@@ -969,42 +1404,259 @@ func TestCachedFieldResolver(t *testing.T) {
 	}
 }
 
-// Test Type Registration
-
-func TestRegisterObjectType(t *testing.T) {
-	typeName := "TestUser"
-
-	RegisterObjectType(typeName, func() *graphql.Object {
-		return graphql.NewObject(graphql.ObjectConfig{
-			Name: typeName,
-			Fields: graphql.Fields{
-				"id":   &graphql.Field{Type: graphql.Int},
-				"name": &graphql.Field{Type: graphql.String},
-			},
-		})
-	})
+func TestFieldCache_Invalidate(t *testing.T) {
+	type CacheUser struct {
+		ID    int    `json:"id"`
+		Score int    `json:"score"`
+		Name  string `json:"name"`
+	}
 
-	// Try to get the type (this tests internal registration)
-	// Second registration should reuse cached type
-	RegisterObjectType(typeName, func() *graphql.Object {
-		return graphql.NewObject(graphql.ObjectConfig{
-			Name: typeName,
-			Fields: graphql.Fields{
-				"id": &graphql.Field{Type: graphql.Int},
-			},
+	score := 10
+	calls := 0
+	userQuery := NewResolver[CacheUser]("cacheUser").
+		WithResolver(func(p ResolveParams) (*CacheUser, error) {
+			return &CacheUser{ID: 1, Name: "Ada"}, nil
+		}).
+		WithCachedField("score", func(p graphql.ResolveParams) string {
+			return fmt.Sprintf("user:%d:score", p.Source.(*CacheUser).ID)
+		}, func(p graphql.ResolveParams) (interface{}, error) {
+			calls++
+			return score, nil
 		})
-	})
-}
 
-// Test Handler Creation
-
-func TestNew(t *testing.T) {
-	graphCtx := GraphContext{
-		Playground: true,
-		DEBUG:      true,
+	cache := userQuery.FieldCache("score")
+	if cache == nil {
+		t.Fatal("Expected FieldCache for cached field 'score'")
+	}
+	if userQuery.FieldCache("name") != nil {
+		t.Error("Expected no FieldCache for uncached field 'name'")
 	}
 
-	handler, err := New(graphCtx)
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{userQuery.BuildQuery()},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	readScore := func() interface{} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ cacheUser { score } }`})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		return result.Data.(map[string]interface{})["cacheUser"].(map[string]interface{})["score"]
+	}
+
+	if got := readScore(); got != 10 {
+		t.Errorf("score = %v, want 10", got)
+	}
+
+	// A mutation updates the value; the cached read is stale until invalidated
+	score = 20
+	if got := readScore(); got != 10 || calls != 1 {
+		t.Errorf("Expected cached score 10 after 1 call, got %v after %d calls", got, calls)
+	}
+
+	cache.Invalidate("user:1:score")
+	if got := readScore(); got != 20 || calls != 2 {
+		t.Errorf("Expected recomputed score 20 after 2 calls, got %v after %d calls", got, calls)
+	}
+
+	score = 30
+	cache.InvalidatePrefix("user:1:")
+	if got := readScore(); got != 30 || calls != 3 {
+		t.Errorf("Expected recomputed score 30 after 3 calls, got %v after %d calls", got, calls)
+	}
+}
+
+// TestFieldCache_ConcurrentResolution resolves cached fields from many goroutines
+// while the cache is invalidated; run it with -race
+func TestFieldCache_ConcurrentResolution(t *testing.T) {
+	type ConcurrentCacheItem struct {
+		ID    int    `json:"id"`
+		Score int    `json:"score"`
+		Label string `json:"label"`
+		Rank  int    `json:"rank"`
+	}
+
+	items := make([]ConcurrentCacheItem, 20)
+	for i := range items {
+		items[i] = ConcurrentCacheItem{ID: i % 5}
+	}
+
+	itemKey := func(p graphql.ResolveParams) string {
+		return fmt.Sprintf("item:%d", p.Source.(ConcurrentCacheItem).ID)
+	}
+	labelResolver := CachedFieldResolver(itemKey, func(p graphql.ResolveParams) (interface{}, error) {
+		return fmt.Sprintf("item %d", p.Source.(ConcurrentCacheItem).ID), nil
+	})
+	rankResolver := CacheMiddleware(func(p ResolveParams) string {
+		return fmt.Sprintf("rank:%d", p.Source.(ConcurrentCacheItem).ID)
+	})(func(p ResolveParams) (interface{}, error) {
+		return p.Source.(ConcurrentCacheItem).ID * 10, nil
+	})
+
+	listQuery := NewResolver[[]ConcurrentCacheItem]("concurrentCacheItems").
+		AsList().
+		WithCachedField("score", itemKey, func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(ConcurrentCacheItem).ID + 1, nil
+		}).
+		WithFieldResolver("label", labelResolver).
+		WithFieldResolver("rank", func(p graphql.ResolveParams) (interface{}, error) {
+			return rankResolver(ResolveParams(p))
+		}).
+		WithResolver(func(p ResolveParams) (*[]ConcurrentCacheItem, error) {
+			return &items, nil
+		})
+	scoreCache := listQuery.FieldCache("score")
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{listQuery.BuildQuery()}},
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%10 == 0 {
+				scoreCache.InvalidatePrefix("item:")
+			}
+
+			body := bytes.NewBufferString(`{"query":"{ concurrentCacheItems { id score label rank } }"}`)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			var response struct {
+				Data struct {
+					Items []ConcurrentCacheItem `json:"concurrentCacheItems"`
+				} `json:"data"`
+				Errors []interface{} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Errors) > 0 {
+				errs <- fmt.Sprintf("unexpected response %s", w.Body.String())
+				return
+			}
+			for _, item := range response.Data.Items {
+				if item.Score != item.ID+1 || item.Label != fmt.Sprintf("item %d", item.ID) || item.Rank != item.ID*10 {
+					errs <- fmt.Sprintf("unexpected item %+v", item)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestWithRequestCachedField(t *testing.T) {
+	type RequestCacheOrder struct {
+		ID         int    `json:"id"`
+		CustomerID int    `json:"customerId"`
+		Customer   string `json:"customer"`
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	orders := NewResolver[[]RequestCacheOrder]("requestCacheOrders").
+		AsList().
+		WithRequestCachedField("customer", func(p graphql.ResolveParams) string {
+			return fmt.Sprintf("customer:%d", p.Source.(RequestCacheOrder).CustomerID)
+		}, 0, func(p graphql.ResolveParams) (interface{}, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			return fmt.Sprintf("customer-%d", p.Source.(RequestCacheOrder).CustomerID), nil
+		}).
+		WithResolver(func(p ResolveParams) (*[]RequestCacheOrder, error) {
+			orders := []RequestCacheOrder{{ID: 1, CustomerID: 7}, {ID: 2, CustomerID: 7}, {ID: 3, CustomerID: 8}}
+			return &orders, nil
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{orders}},
+	})
+	execute := func() string {
+		body := bytes.NewBufferString(`{"query":"{ requestCacheOrders { id customer } }"}`)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Body.String()
+	}
+
+	want := `{"data":{"requestCacheOrders":[{"customer":"customer-7","id":1},{"customer":"customer-7","id":2},{"customer":"customer-8","id":3}]}}`
+	if got := execute(); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+	if calls != 2 {
+		t.Errorf("Expected one call per customer, got %d calls", calls)
+	}
+
+	// Nothing is shared with the next request
+	execute()
+	if calls != 4 {
+		t.Errorf("Expected the second request to resolve again, got %d calls", calls)
+	}
+
+	t.Run("ttl", func(t *testing.T) {
+		cache := NewRequestCache()
+		cache.set("short", 1, time.Millisecond)
+		cache.set("request", 2, 0)
+		time.Sleep(5 * time.Millisecond)
+		if _, ok := cache.get("short"); ok {
+			t.Error("Expected the entry to expire after its ttl")
+		}
+		if value, ok := cache.get("request"); !ok || value != 2 {
+			t.Errorf("Expected the entry without ttl to remain, got %v", value)
+		}
+	})
+}
+
+// Test Type Registration
+
+func TestRegisterObjectType(t *testing.T) {
+	typeName := "TestUser"
+
+	RegisterObjectType(typeName, func() *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: typeName,
+			Fields: graphql.Fields{
+				"id":   &graphql.Field{Type: graphql.Int},
+				"name": &graphql.Field{Type: graphql.String},
+			},
+		})
+	})
+
+	// Try to get the type (this tests internal registration)
+	// Second registration should reuse cached type
+	RegisterObjectType(typeName, func() *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: typeName,
+			Fields: graphql.Fields{
+				"id": &graphql.Field{Type: graphql.Int},
+			},
+		})
+	})
+}
+
+// Test Handler Creation
+
+func TestNew(t *testing.T) {
+	graphCtx := GraphContext{
+		Playground: true,
+		DEBUG:      true,
+	}
+
+	handler, err := New(graphCtx)
 	if err != nil {
 		t.Fatalf("New() error = %v", err)
 	}
@@ -1205,6 +1857,65 @@ func TestNewArgsResolver_StructArgs(t *testing.T) {
 	}
 }
 
+func TestNewArgsResolver_WithArgPreprocessor(t *testing.T) {
+	type RenameArgs struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+		// Legacy argument still accepted from older clients
+		UserID int `json:"userId"`
+	}
+
+	type User struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	}
+
+	resolver := NewArgsResolver[User, RenameArgs]("user").
+		WithArgPreprocessor(func(args map[string]interface{}) map[string]interface{} {
+			if legacy, ok := args["userId"]; ok {
+				args["id"] = legacy
+				delete(args, "userId")
+			}
+			return args
+		}).
+		WithArgPreprocessor(func(args map[string]interface{}) map[string]interface{} {
+			if username, ok := args["username"].(string); ok {
+				args["username"] = strings.ToLower(strings.TrimSpace(username))
+			}
+			return args
+		}).
+		WithResolver(func(ctx context.Context, p ResolveParams, args RenameArgs) (*User, error) {
+			if args.UserID != 0 {
+				return nil, fmt.Errorf("legacy userId should have been renamed")
+			}
+			return &User{ID: args.ID, Username: args.Username}, nil
+		})
+
+	field := resolver.BuildQuery().Serve()
+
+	rawArgs := map[string]interface{}{
+		"userId":   7,
+		"username": "  Ada ",
+	}
+	result, err := field.Resolve(graphql.ResolveParams{
+		Args:    rawArgs,
+		Context: context.Background(),
+	})
+	if err != nil {
+		t.Fatalf("Resolver error = %v", err)
+	}
+
+	user := result.(*User)
+	if user.ID != 7 || user.Username != "ada" {
+		t.Errorf("Expected User{ID: 7, Username: ada}, got %+v", user)
+	}
+
+	// The original arguments are left untouched
+	if _, ok := rawArgs["userId"]; !ok {
+		t.Error("Preprocessor should operate on a copy of the arguments")
+	}
+}
+
 func TestNewArgsResolver_PrimitiveArgs_String(t *testing.T) {
 	// Create resolver with primitive string argument
 	resolver := NewArgsResolver[string, string]("echo", "message").
@@ -1631,6 +2342,55 @@ func TestGenerateGraphQLFields_FieldOverride(t *testing.T) {
 	}
 }
 
+func TestGenerateGraphQLFields_EmbeddedCollision(t *testing.T) {
+	type Audited struct {
+		ID     string `json:"id"`
+		Author string `json:"author"`
+	}
+	type Versioned struct {
+		ID      string `graphql:"id"`
+		Version int    `json:"version"`
+	}
+	type AuditedFirst struct {
+		Audited
+		Versioned
+	}
+	type VersionedFirst struct {
+		Versioned
+		Audited
+	}
+
+	resolveID := func(fields graphql.Fields, source interface{}) interface{} {
+		value, err := fields["id"].Resolve(graphql.ResolveParams{Source: source})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return value
+	}
+
+	audited := Audited{ID: "audit-1", Author: "ada"}
+	versioned := Versioned{ID: "version-1", Version: 3}
+	for i := 0; i < 20; i++ {
+		fields := GenerateGraphQLFields[AuditedFirst]()
+		if fields["id"].Type != graphql.String {
+			t.Fatalf("Expected id to be a String, got %v", fields["id"].Type)
+		}
+		if got := resolveID(fields, AuditedFirst{Audited: audited, Versioned: versioned}); got != "audit-1" {
+			t.Fatalf("Expected the first embedded struct to provide id, got %v", got)
+		}
+		if got := resolveID(GenerateGraphQLFields[VersionedFirst](), &VersionedFirst{Versioned: versioned, Audited: audited}); got != "version-1" {
+			t.Fatalf("Expected the first embedded struct to provide id, got %v", got)
+		}
+	}
+
+	fields := GenerateGraphQLFields[AuditedFirst]()
+	for _, name := range []string{"id", "author", "version"} {
+		if _, exists := fields[name]; !exists {
+			t.Errorf("Expected field %s to exist", name)
+		}
+	}
+}
+
 func TestFieldResolver_EmbeddedFields(t *testing.T) {
 	type BaseEntity struct {
 		ID        string     `json:"id"`
@@ -1725,6 +2485,181 @@ func TestGenerateInputObject_EmbeddedStruct(t *testing.T) {
 	}
 }
 
+func TestInputObjectFromValue(t *testing.T) {
+	type Paging struct {
+		Limit int `json:"limit" default:"10"`
+	}
+	type Filter struct {
+		Status string `json:"status"`
+	}
+	type SearchInput struct {
+		Paging
+		Query  string   `json:"query"`
+		Sort   string   `json:"sort" default:"name"`
+		Tags   []string `json:"tags"`
+		Filter *Filter  `json:"filter"`
+		Exact  bool     `json:"exact"`
+	}
+
+	input := InputObjectFromValue("SearchInputWithDefaults", SearchInput{
+		Paging: Paging{Limit: 25},
+		Sort:   "created_at",
+		Tags:   []string{"go"},
+		Filter: &Filter{Status: "active"},
+	})
+
+	fields := input.Fields()
+	tests := []struct {
+		field string
+		want  interface{}
+	}{
+		{field: "limit", want: 25},
+		{field: "sort", want: "created_at"},
+		{field: "tags", want: []string{"go"}},
+		{field: "filter", want: map[string]interface{}{"status": "active"}},
+		{field: "query", want: nil},
+		{field: "exact", want: nil},
+	}
+	for _, tt := range tests {
+		field, exists := fields[tt.field]
+		if !exists {
+			t.Fatalf("Expected input field %s to exist", tt.field)
+		}
+		if !reflect.DeepEqual(field.DefaultValue, tt.want) {
+			t.Errorf("Expected default of %s to be %v, got %v", tt.field, tt.want, field.DefaultValue)
+		}
+	}
+
+	// Zero fields keep their tag default
+	input = InputObjectFromValue("SearchInputTagDefaults", SearchInput{})
+	if got := input.Fields()["sort"].DefaultValue; got != "name" {
+		t.Errorf("Expected tag default name, got %v", got)
+	}
+}
+
+func TestTagDefaultValues(t *testing.T) {
+	type TagDefaultsArgs struct {
+		Limit  int     `json:"limit" default:"10"`
+		Exact  bool    `json:"exact" default:"true"`
+		Ratio  float64 `json:"ratio" default:"0.5"`
+		Sort   string  `json:"sort" default:"name"`
+		Broken int     `json:"broken" default:"ten"`
+	}
+	want := map[string]interface{}{"limit": 10, "exact": true, "ratio": 0.5, "sort": "name", "broken": "ten"}
+
+	args := GenerateArgsFromStruct[TagDefaultsArgs]()
+	inputFields := GenerateInputObject[TagDefaultsArgs]("TagDefaultsInput").Fields()
+	for name, value := range want {
+		if got := args[name].DefaultValue; got != value {
+			t.Errorf("Expected argument %s to default to %#v, got %#v", name, value, got)
+		}
+		if got := inputFields[name].DefaultValue; got != value {
+			t.Errorf("Expected input field %s to default to %#v, got %#v", name, value, got)
+		}
+	}
+
+	var seen TagDefaultsArgs
+	var limit int
+	var exact bool
+	var ratio float64
+	field := NewResolver[string]("tagDefaults").
+		WithArgsFromStruct(TagDefaultsArgs{}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			var err error
+			if limit, err = GetArgInt(p, "limit"); err != nil {
+				return nil, err
+			}
+			if exact, err = GetArgBool(p, "exact"); err != nil {
+				return nil, err
+			}
+			ratio, _ = p.Args["ratio"].(float64)
+			delete(p.Args, "broken")
+			result := "ok"
+			return &result, mapArgsToStruct(p.Args, &seen)
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{field}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ tagDefaults }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if limit != 10 || !exact || ratio != 0.5 {
+		t.Errorf("Expected typed defaults 10, true and 0.5, got %v, %v and %v", limit, exact, ratio)
+	}
+	if seen.Limit != 10 || !seen.Exact || seen.Ratio != 0.5 || seen.Sort != "name" {
+		t.Errorf("Expected the defaults to decode into the args struct, got %+v", seen)
+	}
+}
+
+func TestTagDefaultValues_Int64(t *testing.T) {
+	type Int64DefaultArgs struct {
+		Limit int64 `json:"limit" default:"10"`
+	}
+
+	var limit interface{}
+	field := NewResolver[string]("int64Default").
+		WithArgsFromStruct(Int64DefaultArgs{}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			limit = p.Args["limit"]
+			result := "ok"
+			return &result, nil
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{field}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ int64Default }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if limit != int64(10) {
+		t.Errorf("Expected limit to default to int64(10), got %#v", limit)
+	}
+
+	if sdl := PrintSchema(&schema); !strings.Contains(sdl, "int64Default(limit: Int64 = 10): String") {
+		t.Errorf("Expected the Int64 default to print unquoted:\n%s", sdl)
+	}
+}
+
+func TestDeprecatedTag(t *testing.T) {
+	type DeprecatedTagUser struct {
+		Name     string `json:"name" deprecated:"Use fullName instead" description:"Display name"`
+		FullName string `json:"fullName" deprecated:""`
+		Nickname string `json:"nickname" directive:"@deprecated"`
+	}
+
+	fields := GenerateGraphQLFields[DeprecatedTagUser]()
+	if got := fields["name"].DeprecationReason; got != "Use fullName instead" {
+		t.Errorf("Expected name to be deprecated, got %q", got)
+	}
+	if got := fields["fullName"].DeprecationReason; got != "" {
+		t.Errorf("Expected empty deprecated tag to leave fullName non-deprecated, got %q", got)
+	}
+	if got := fields["nickname"].DeprecationReason; got != graphql.DefaultDeprecationReason {
+		t.Errorf("Expected @deprecated directive to still apply, got %q", got)
+	}
+
+	// Arguments and input fields cannot be deprecated in graphql-go, the reason is
+	// added to their description
+	args := GenerateArgsFromStruct[DeprecatedTagUser]()
+	if got := args["name"].Description; got != "Display name\n\nDeprecated: Use fullName instead" {
+		t.Errorf("Unexpected argument description %q", got)
+	}
+	input := GenerateInputObject[DeprecatedTagUser]("DeprecatedTagUserInput")
+	if got := input.Fields()["name"].Description(); got != "Display name\n\nDeprecated: Use fullName instead" {
+		t.Errorf("Unexpected input field description %q", got)
+	}
+	if got := input.Fields()["fullName"].Description(); got != "" {
+		t.Errorf("Expected no description for fullName, got %q", got)
+	}
+}
+
 func TestGenerateArgsFromStruct_EmbeddedStruct(t *testing.T) {
 	type BaseEntity struct {
 		ID        string     `json:"id"`
@@ -1980,7 +2915,7 @@ func TestPageableResponse_WithNestedStruct(t *testing.T) {
 	}
 
 	// Verify interview fields
-	if interview["id"] != 1 {
+	if interview["id"] != int64(1) {
 		t.Errorf("Expected id=1, got %v", interview["id"])
 	}
 	if interview["uid"] != "interview-001" {
@@ -1996,7 +2931,7 @@ func TestPageableResponse_WithNestedStruct(t *testing.T) {
 		t.Fatalf("Expected map for advert, got %T", interview["advert"])
 	}
 
-	if advert["id"] != 100 {
+	if advert["id"] != int64(100) {
 		t.Errorf("Expected advert id=100, got %v", advert["id"])
 	}
 	if advert["advertName"] != "Software Engineer" {
@@ -2160,3 +3095,1375 @@ func TestDeeplyNestedGenericTypes(t *testing.T) {
 		t.Errorf("Expected 'deep value', got %v", level3Data["value"])
 	}
 }
+
+func TestNewArgsResolver_WithArgsValidation(t *testing.T) {
+	type LookupArgs struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	}
+
+	type Article struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	}
+
+	resolverCalled := false
+	resolver := NewArgsResolver[Article, LookupArgs]("article").
+		WithArgsValidation(func(args LookupArgs) error {
+			if (args.ID == "") == (args.Slug == "") {
+				return fmt.Errorf("exactly one of id or slug must be provided")
+			}
+			return nil
+		}).
+		WithResolver(func(ctx context.Context, p ResolveParams, args LookupArgs) (*Article, error) {
+			resolverCalled = true
+			return &Article{ID: args.ID, Slug: args.Slug}, nil
+		})
+
+	field := resolver.BuildQuery().Serve()
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantError bool
+	}{
+		{"Only id", map[string]interface{}{"id": "1"}, false},
+		{"Only slug", map[string]interface{}{"slug": "hello-world"}, false},
+		{"Both id and slug", map[string]interface{}{"id": "1", "slug": "hello-world"}, true},
+		{"Neither id nor slug", map[string]interface{}{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolverCalled = false
+			result, err := field.Resolve(graphql.ResolveParams{
+				Args:    tt.args,
+				Context: context.Background(),
+			})
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("Expected validation error")
+				}
+				if resolverCalled {
+					t.Error("Resolver should not be called when validation fails")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resolverCalled {
+				t.Error("Resolver should be called when validation passes")
+			}
+			if _, ok := result.(*Article); !ok {
+				t.Errorf("Expected *Article, got %T", result)
+			}
+		})
+	}
+}
+
+func TestNewResolver_WithFindResolver(t *testing.T) {
+	type FindUser struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	users := map[int]FindUser{1: {ID: 1, Name: "Alice"}}
+
+	field := NewResolver[FindUser]("findUser").
+		WithArgs(graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+		}).
+		WithFindResolver(func(p ResolveParams) (*FindUser, bool, error) {
+			id, err := GetArgInt(p, "id")
+			if err != nil {
+				return nil, false, err
+			}
+			if id < 0 {
+				return nil, false, fmt.Errorf("invalid id %d", id)
+			}
+			user, ok := users[id]
+			if !ok {
+				return nil, false, nil
+			}
+			return &user, true, nil
+		}).BuildQuery().Serve()
+
+	t.Run("Found", func(t *testing.T) {
+		result, err := field.Resolve(graphql.ResolveParams{Args: map[string]interface{}{"id": 1}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		user, ok := result.(*FindUser)
+		if !ok || user.Name != "Alice" {
+			t.Errorf("Expected Alice, got %#v", result)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		result, err := field.Resolve(graphql.ResolveParams{Args: map[string]interface{}{"id": 2}})
+		if err != nil {
+			t.Fatalf("Not found should not produce an error, got: %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected untyped nil result, got %#v", result)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		result, err := field.Resolve(graphql.ResolveParams{Args: map[string]interface{}{"id": -1}})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if result != nil {
+			t.Errorf("Expected nil result on error, got %#v", result)
+		}
+	})
+}
+
+func TestWithInputObject_RequiredNestedInput(t *testing.T) {
+	type ShippingAddress struct {
+		Street string `json:"street" graphql:"street,required"`
+		City   string `json:"city"`
+	}
+	type PlaceOrderRequest struct {
+		Note    string           `json:"note"`
+		Address *ShippingAddress `json:"address" graphql:"address,required"`
+	}
+
+	done := make(chan struct{})
+	var schema graphql.Schema
+	var err error
+	go func() {
+		defer close(done)
+		mutation := NewResolver[string]("placeOrder").
+			WithInputObject(PlaceOrderRequest{}).
+			WithResolver(func(p ResolveParams) (*string, error) {
+				result := "ok"
+				return &result, nil
+			}).
+			BuildMutation()
+
+		schema, err = NewSchemaBuilder(SchemaBuilderParams{
+			QueryFields:    []QueryField{getDefaultHelloQuery()},
+			MutationFields: []MutationField{mutation},
+		}).Build()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Building a nested input object did not finish (deadlock)")
+	}
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		wantError bool
+	}{
+		{
+			name:  "nested object provided",
+			query: `mutation { placeOrder(input: {note: "x", address: {street: "Main"}}) }`,
+		},
+		{
+			name:      "nested object missing",
+			query:     `mutation { placeOrder(input: {note: "x"}) }`,
+			wantError: true,
+		},
+		{
+			name:      "nested required field missing",
+			query:     `mutation { placeOrder(input: {note: "x", address: {city: "Dar"}}) }`,
+			wantError: true,
+		},
+		{
+			name:      "nested object missing in variables",
+			query:     `mutation($input: PlaceOrderRequestInput!) { placeOrder(input: $input) }`,
+			variables: map[string]interface{}{"input": map[string]interface{}{"note": "x"}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  tt.query,
+				VariableValues: tt.variables,
+			})
+			if hasErrors := len(result.Errors) > 0; hasErrors != tt.wantError {
+				t.Errorf("Expected errors = %v, got %v", tt.wantError, result.Errors)
+			}
+		})
+	}
+}
+
+func TestNewResolver_MapResultsAsEntries(t *testing.T) {
+	rows := NewResolver[[]map[string]interface{}]("entryRows").
+		WithResolver(func(p ResolveParams) (*[]map[string]interface{}, error) {
+			rows := []map[string]interface{}{
+				{"name": "Alice", "age": 30},
+				{"name": "Bob"},
+			}
+			return &rows, nil
+		}).
+		BuildQuery()
+
+	stock := NewResolver[map[string]int]("entryStock").
+		WithResolver(func(p ResolveParams) (*map[string]int, error) {
+			stock := map[string]int{"pears": 3, "apples": 5}
+			return &stock, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{rows, stock},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	// Values of unknown type use the shared JSON scalar
+	rowEntry, ok := schema.Type("JSONEntry").(*graphql.Object)
+	if !ok {
+		t.Fatalf("Expected a JSONEntry type, got %T", schema.Type("JSONEntry"))
+	}
+	if rowEntry.Fields()["value"].Type != JSON {
+		t.Errorf("Expected JSONEntry.value to be the JSON scalar, got %v", rowEntry.Fields()["value"].Type)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ entryRows { key value } entryStock { key value } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	data := result.Data.(map[string]interface{})
+
+	gotRows, _ := json.Marshal(data["entryRows"])
+	wantRows := `[[{"key":"age","value":30},{"key":"name","value":"Alice"}],[{"key":"name","value":"Bob"}]]`
+	if string(gotRows) != wantRows {
+		t.Errorf("entryRows = %s, want %s", gotRows, wantRows)
+	}
+
+	gotStock, _ := json.Marshal(data["entryStock"])
+	wantStock := `[{"key":"apples","value":5},{"key":"pears","value":3}]`
+	if string(gotStock) != wantStock {
+		t.Errorf("entryStock = %s, want %s", gotStock, wantStock)
+	}
+}
+
+func TestGetArgWithPresence(t *testing.T) {
+	type ProfilePatch struct {
+		Name *string `json:"name"`
+		Bio  *string `json:"bio"`
+	}
+
+	patchInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PresenceProfilePatch",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"bio":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	patch := NewResolver[string]("patchProfile").
+		WithArgs(graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(patchInput)},
+		}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			var input ProfilePatch
+			present, err := GetArgWithPresence(p, "input", &input)
+			if err != nil {
+				return nil, err
+			}
+			result := fmt.Sprintf("name=%v bio=%v bioNil=%v", present["name"], present["bio"], input.Bio == nil)
+			return &result, nil
+		}).
+		BuildMutation()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields:    []QueryField{getDefaultHelloQuery()},
+			MutationFields: []MutationField{patch},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		variables string
+		want      string
+	}{
+		{
+			name:      "absent field",
+			variables: `{"input":{"name":"Ada"}}`,
+			want:      "name=true bio=false bioNil=true",
+		},
+		{
+			name:      "explicit null field",
+			variables: `{"input":{"name":"Ada","bio":null}}`,
+			want:      "name=true bio=true bioNil=true",
+		},
+		{
+			name:      "field with value",
+			variables: `{"input":{"bio":"Mathematician"}}`,
+			want:      "name=false bio=true bioNil=false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := bytes.NewBufferString(`{"query":"mutation($input: PresenceProfilePatch!) { patchProfile(input: $input) }","variables":` + tt.variables + `}`)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			var response struct {
+				Data   map[string]interface{} `json:"data"`
+				Errors []interface{}          `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", response.Errors)
+			}
+			if got := response.Data["patchProfile"]; got != tt.want {
+				t.Errorf("patchProfile = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHTTP_PlaygroundSubscriptionDefaults(t *testing.T) {
+	type PlaygroundTick struct {
+		Count int    `json:"count"`
+		Label string `json:"label"`
+	}
+
+	tick := NewSubscription[PlaygroundTick]("playgroundTick").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *PlaygroundTick, error) {
+			return make(chan *PlaygroundTick), nil
+		}).
+		BuildSubscription()
+
+	handler := NewHTTP(&GraphContext{
+		Playground:          true,
+		EnableSubscriptions: true,
+		PubSub:              NewInMemoryPubSub(),
+		WebSocketPath:       "/graphql/ws",
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields:        []QueryField{getDefaultHelloQuery()},
+			SubscriptionFields: []SubscriptionField{tick},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/graphql", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, `subscriptionEndpoint: "ws://example.com/graphql/ws"`) {
+		t.Errorf("Expected WebSocket endpoint in playground, got:\n%s", body)
+	}
+	if !strings.Contains(body, `subscription {\n  playgroundTick {\n    count\n    label\n  }\n}\n`) {
+		t.Errorf("Expected default subscription query in playground, got:\n%s", body)
+	}
+}
+
+func TestNewHTTP_SubscriptionsUnavailable(t *testing.T) {
+	type UnavailableTick struct {
+		Count int `json:"count"`
+	}
+
+	tick := NewSubscription[UnavailableTick]("unavailableTick").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *UnavailableTick, error) {
+			return make(chan *UnavailableTick), nil
+		}).
+		BuildSubscription()
+
+	closedPubSub := NewInMemoryPubSub()
+	_ = closedPubSub.Close()
+
+	tests := []struct {
+		name   string
+		pubsub PubSub
+		want   string
+	}{
+		{name: "nil PubSub", pubsub: nil, want: "no PubSub is configured"},
+		{name: "closed PubSub", pubsub: closedPubSub, want: ErrPubSubClosed.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				EnableSubscriptions: true,
+				PubSub:              tt.pubsub,
+				SchemaParams: &SchemaBuilderParams{
+					QueryFields:        []QueryField{getDefaultHelloQuery()},
+					SubscriptionFields: []SubscriptionField{tick},
+				},
+			})
+			post := func(query string) *httptest.ResponseRecorder {
+				body, _ := json.Marshal(map[string]string{"query": query})
+				req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				handler(w, req)
+				return w
+			}
+
+			if w := post("subscription { unavailableTick { count } }"); w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("Expected 503 mentioning %q for a subscription, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Connection", "Upgrade")
+			w := httptest.NewRecorder()
+			handler(w, req)
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("Expected 503 for a WebSocket upgrade, got %d: %s", w.Code, w.Body.String())
+			}
+
+			w = post("{ hello }")
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Hello world") {
+				t.Errorf("Expected queries to keep working, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithResolverTimeout(t *testing.T) {
+	slowCancelled := make(chan struct{})
+
+	slow := NewResolver[string]("slowTimeoutField").
+		WithResolverTimeout(20 * time.Millisecond).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			<-p.Context.Done()
+			close(slowCancelled)
+			time.Sleep(500 * time.Millisecond)
+			result := "too late"
+			return &result, nil
+		}).
+		BuildQuery()
+
+	fast := NewResolver[string]("fastTimeoutField").
+		WithResolverTimeout(time.Second).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			result := "fast"
+			return &result, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{slow, fast},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	start := time.Now()
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ slowTimeoutField fastTimeoutField }`,
+		Context:       context.Background(),
+	})
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Expected query to return at the deadline, took %v", elapsed)
+	}
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "timed out") {
+		t.Fatalf("Expected a single timeout error, got %v", result.Errors)
+	}
+
+	data := result.Data.(map[string]interface{})
+	if data["slowTimeoutField"] != nil {
+		t.Errorf("Expected slowTimeoutField to be null, got %v", data["slowTimeoutField"])
+	}
+	if data["fastTimeoutField"] != "fast" {
+		t.Errorf("Expected fastTimeoutField 'fast', got %v", data["fastTimeoutField"])
+	}
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow resolver's context to be cancelled")
+	}
+}
+
+func TestNewHTTP_AccessLog(t *testing.T) {
+	var logs bytes.Buffer
+	handler := NewHTTP(&GraphContext{
+		AccessLog: true,
+		Logger:    slog.New(slog.NewJSONHandler(&logs, nil)),
+		UserDetailsFn: func(ctx context.Context, token string) (context.Context, interface{}, error) {
+			return ctx, map[string]interface{}{"id": 42, "name": "Test User"}, nil
+		},
+	})
+
+	body := bytes.NewBufferString(`{"query":"query GetGreeting { hello }"}`)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON log entry, got %q: %v", logs.String(), err)
+	}
+
+	want := map[string]interface{}{
+		"method":    "POST",
+		"path":      "/graphql",
+		"operation": "GetGreeting",
+		"user_id":   "42",
+		"status":    float64(http.StatusOK),
+		"bytes":     float64(w.Body.Len()),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("log entry %s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("Expected log entry to contain a duration")
+	}
+}
+
+func TestIDArgumentCoercion(t *testing.T) {
+	type idArgs struct {
+		ID  int64  `json:"id"`
+		Ref string `json:"ref"`
+	}
+
+	var decoded []idArgs
+	field := NewResolver[string]("idCoercionField").
+		WithArgs(graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			var args idArgs
+			if err := mapArgsToStruct(map[string]interface{}{"id": p.Args["id"], "ref": 123}, &args); err != nil {
+				return nil, err
+			}
+			var id int64
+			if err := GetArg(p, "id", &id); err != nil {
+				return nil, err
+			}
+			if id != args.ID {
+				return nil, fmt.Errorf("GetArg returned %d, mapArgsToStruct returned %d", id, args.ID)
+			}
+			decoded = append(decoded, args)
+			result := "ok"
+			return &result, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	for _, query := range []string{`{ idCoercionField(id: "123") }`, `{ idCoercionField(id: 123) }`} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Query %s failed: %v", query, result.Errors)
+		}
+	}
+
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 decoded argument sets, got %d", len(decoded))
+	}
+	for _, args := range decoded {
+		if args.ID != 123 || args.Ref != "123" {
+			t.Errorf("Expected ID 123 and Ref \"123\", got %+v", args)
+		}
+	}
+
+	var args idArgs
+	if err := mapArgsToStruct(map[string]interface{}{"id": "abc"}, &args); err == nil {
+		t.Error("Expected an error converting a non-numeric ID to int64")
+	}
+}
+
+func TestNewHTTP_ExecuteHooks(t *testing.T) {
+	type hookKey struct{}
+
+	field := NewResolver[string]("hookedField").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			value, _ := p.Context.Value(hookKey{}).(string)
+			return &value, nil
+		}).
+		BuildQuery()
+
+	var beforeQuery string
+	var afterResult *graphql.Result
+	var afterValue interface{}
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{field},
+		},
+		BeforeExecute: func(ctx context.Context, params *graphql.Params) context.Context {
+			beforeQuery = params.RequestString
+			return context.WithValue(ctx, hookKey{}, "from before")
+		},
+		AfterExecute: func(ctx context.Context, result *graphql.Result) {
+			afterResult = result
+			afterValue = ctx.Value(hookKey{})
+		},
+	})
+
+	body := bytes.NewBufferString(`{"query":"{ hookedField }"}`)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if beforeQuery != "{ hookedField }" {
+		t.Errorf("Expected BeforeExecute to receive the query, got %q", beforeQuery)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	data, _ := response["data"].(map[string]interface{})
+	if data["hookedField"] != "from before" {
+		t.Errorf("Expected resolver to see the BeforeExecute context value, got %v", response)
+	}
+
+	if afterResult == nil {
+		t.Fatal("Expected AfterExecute to be called")
+	}
+	resultData, _ := afterResult.Data.(map[string]interface{})
+	if resultData["hookedField"] != "from before" {
+		t.Errorf("Expected AfterExecute to receive the operation result, got %v", afterResult.Data)
+	}
+	if afterValue != "from before" {
+		t.Errorf("Expected AfterExecute context to carry the BeforeExecute value, got %v", afterValue)
+	}
+}
+
+func TestNew_ExecuteHooks(t *testing.T) {
+	type hookKey struct{}
+
+	field := NewResolver[string]("hookedField").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			value, _ := p.Context.Value(hookKey{}).(string)
+			return &value, nil
+		}).
+		BuildQuery()
+
+	var beforeQuery, beforeOperation string
+	var beforeVariables map[string]interface{}
+
+	handler, err := New(GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{field},
+		},
+		BeforeExecute: func(ctx context.Context, params *graphql.Params) context.Context {
+			beforeQuery = params.RequestString
+			beforeOperation = params.OperationName
+			beforeVariables = params.VariableValues
+			return context.WithValue(ctx, hookKey{}, "from before")
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"query":"query Hooked { hookedField }","operationName":"Hooked","variables":{"limit":3}}`)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if beforeQuery != "query Hooked { hookedField }" {
+		t.Errorf("Expected BeforeExecute to receive the query, got %q", beforeQuery)
+	}
+	if beforeOperation != "Hooked" {
+		t.Errorf("Expected BeforeExecute to receive the operation name, got %q", beforeOperation)
+	}
+	if beforeVariables["limit"] != float64(3) {
+		t.Errorf("Expected BeforeExecute to receive the variables, got %v", beforeVariables)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	data, _ := response["data"].(map[string]interface{})
+	if data["hookedField"] != "from before" {
+		t.Errorf("Expected resolver to see the BeforeExecute context value, got %v", response)
+	}
+}
+
+func TestUnifiedResolver_ServeReusesOutputType(t *testing.T) {
+	type ServeCacheUser struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	resolver := NewResolver[ServeCacheUser]("serveCacheUser").
+		WithFieldResolver("name", func(p graphql.ResolveParams) (interface{}, error) {
+			return "overridden", nil
+		}).
+		WithResolver(func(p ResolveParams) (*ServeCacheUser, error) {
+			return &ServeCacheUser{ID: 1, Name: "original"}, nil
+		})
+
+	if resolver.Serve().Type != resolver.Serve().Type {
+		t.Error("Expected repeated Serve calls to return the same output type")
+	}
+
+	// Build the schema twice; the override must still apply
+	var schema graphql.Schema
+	for i := 0; i < 2; i++ {
+		var err error
+		schema, err = NewSchemaBuilder(SchemaBuilderParams{
+			QueryFields: []QueryField{resolver.BuildQuery()},
+		}).Build()
+		if err != nil {
+			t.Fatalf("Failed to build schema: %v", err)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ serveCacheUser { id name } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	user := result.Data.(map[string]interface{})["serveCacheUser"].(map[string]interface{})
+	if user["name"] != "overridden" {
+		t.Errorf("Expected the field override to apply after repeated builds, got %v", user["name"])
+	}
+
+	// Switching to a list rebuilds the output type
+	resolver.AsList()
+	if _, ok := resolver.Serve().Type.(*graphql.List); !ok {
+		t.Errorf("Expected a list type after AsList, got %T", resolver.Serve().Type)
+	}
+}
+
+func TestWithFieldRename(t *testing.T) {
+	type RenamedUser struct {
+		ID       int    `json:"user_id"`
+		FullName string `json:"full_name"`
+		Email    string `json:"email"`
+	}
+
+	field := NewResolver[RenamedUser]("renamedUser").
+		WithFieldRename("FullName", "displayName").
+		WithFieldRename("user_id", "id").
+		WithFieldResolver("displayName", func(p graphql.ResolveParams) (interface{}, error) {
+			return strings.ToUpper(p.Source.(*RenamedUser).FullName), nil
+		}).
+		WithResolver(func(p ResolveParams) (*RenamedUser, error) {
+			return &RenamedUser{ID: 7, FullName: "Ada Lovelace", Email: "ada@example.com"}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	fields := schema.Type("RenamedUser").(*graphql.Object).Fields()
+	for _, name := range []string{"full_name", "user_id"} {
+		if _, exists := fields[name]; exists {
+			t.Errorf("Expected field %s to be renamed", name)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ renamedUser { id displayName email } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	user := result.Data.(map[string]interface{})["renamedUser"].(map[string]interface{})
+	if user["id"] != 7 {
+		t.Errorf("Expected id 7, got %v", user["id"])
+	}
+	if user["displayName"] != "ADA LOVELACE" {
+		t.Errorf("Expected displayName 'ADA LOVELACE', got %v", user["displayName"])
+	}
+	if user["email"] != "ada@example.com" {
+		t.Errorf("Expected email 'ada@example.com', got %v", user["email"])
+	}
+}
+
+func TestWithExcludeFields(t *testing.T) {
+	type ExcludedAccount struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		Password     string `json:"password"`
+		InternalFlag bool   `json:"internalFlag"`
+	}
+	type ExcludedAccountFilter struct {
+		Name     string `graphql:"name"`
+		Password string `graphql:"password"`
+	}
+
+	query := NewResolver[ExcludedAccount]("excludedAccount").
+		WithArgsFromStruct(ExcludedAccountFilter{}).
+		WithExcludeFields("password", "InternalFlag").
+		WithResolver(func(p ResolveParams) (*ExcludedAccount, error) {
+			return &ExcludedAccount{ID: 1, Name: "ada", Password: "secret", InternalFlag: true}, nil
+		}).
+		BuildQuery()
+
+	mutation := NewResolver[ExcludedAccount]("createExcludedAccount").
+		WithExcludeFields("password", "InternalFlag").
+		WithInputObject(ExcludedAccount{}).
+		WithResolver(func(p ResolveParams) (*ExcludedAccount, error) {
+			return &ExcludedAccount{ID: 2, Name: "grace"}, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{query},
+		MutationFields: []MutationField{mutation},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	objectFields := schema.Type("ExcludedAccount").(*graphql.Object).Fields()
+	inputFields := schema.Type("ExcludedAccountInput").(*graphql.InputObject).Fields()
+	for _, name := range []string{"password", "internalFlag"} {
+		if _, exists := objectFields[name]; exists {
+			t.Errorf("Expected field %s to be excluded from the object type", name)
+		}
+		if _, exists := inputFields[name]; exists {
+			t.Errorf("Expected field %s to be excluded from the input type", name)
+		}
+	}
+	if _, exists := objectFields["name"]; !exists {
+		t.Error("Expected field name to be kept in the object type")
+	}
+	if _, exists := inputFields["name"]; !exists {
+		t.Error("Expected field name to be kept in the input type")
+	}
+
+	for _, arg := range schema.QueryType().Fields()["excludedAccount"].Args {
+		if arg.Name() == "password" {
+			t.Error("Expected argument password to be excluded")
+		}
+	}
+
+	requests := []string{
+		`{ excludedAccount { id password } }`,
+		`{ excludedAccount { id internalFlag } }`,
+		`{ excludedAccount(password: "x") { id } }`,
+		`mutation { createExcludedAccount(input: {name: "grace", password: "x"}) { id } }`,
+	}
+	for _, request := range requests {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: request})
+		if len(result.Errors) == 0 {
+			t.Errorf("Expected error for %s", request)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ excludedAccount(name: "ada") { id name } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestNewHTTP_EchoCostHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				SchemaParams: &SchemaBuilderParams{
+					QueryFields: []QueryField{getDefaultHelloQuery()},
+				},
+				EchoCostHeader: tt.enabled,
+			})
+
+			body := bytes.NewBufferString(`{"query":"{ hello }"}`)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			cost := w.Header().Get("X-GraphQL-Cost")
+			if !tt.enabled {
+				if cost != "" {
+					t.Errorf("Expected no cost header when disabled, got %q", cost)
+				}
+				return
+			}
+			if n, err := strconv.Atoi(cost); err != nil || n <= 0 {
+				t.Errorf("Expected a positive numeric cost header, got %q", cost)
+			}
+		})
+	}
+}
+
+func TestNew_EchoCostHeader(t *testing.T) {
+	handler, err := New(GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{getDefaultHelloQuery()},
+		},
+		EchoCostHeader: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"query":"{ hello }"}`)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if n, err := strconv.Atoi(w.Header().Get("X-GraphQL-Cost")); err != nil || n <= 0 {
+		t.Errorf("Expected a positive numeric cost header, got %q", w.Header().Get("X-GraphQL-Cost"))
+	}
+	if !strings.Contains(w.Body.String(), "Hello world") {
+		t.Errorf("Expected the query to still execute, got %s", w.Body.String())
+	}
+}
+
+func TestNewHTTP_RequestTimeout(t *testing.T) {
+	resolverDone := make(chan error, 1)
+	slow := NewResolver[string]("slow").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			select {
+			case <-p.Context.Done():
+				resolverDone <- p.Context.Err()
+				return nil, p.Context.Err()
+			case <-time.After(2 * time.Second):
+				resolverDone <- nil
+				value := "done"
+				return &value, nil
+			}
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{getDefaultHelloQuery(), slow},
+		},
+		RequestTimeout: 50 * time.Millisecond,
+	})
+
+	post := func(query string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(`{"query":"` + query + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	start := time.Now()
+	w := post("{ slow }")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request to end at the timeout, took %v", elapsed)
+	}
+	if w.Code != http.StatusRequestTimeout {
+		t.Fatalf("Expected status 408, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Errors) != 1 {
+		t.Fatalf("Expected a GraphQL error payload, got %s", w.Body.String())
+	}
+	if response.Errors[0].Message != "request timed out after 50ms" {
+		t.Errorf("Unexpected error message %q", response.Errors[0].Message)
+	}
+	select {
+	case err := <-resolverDone:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the resolver context to be cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the resolver to observe the cancellation")
+	}
+
+	if w := post("{ hello }"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 within the timeout, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNew_RequestTimeout(t *testing.T) {
+	slow := NewResolver[string]("slow").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			select {
+			case <-p.Context.Done():
+				return nil, p.Context.Err()
+			case <-time.After(2 * time.Second):
+				value := "done"
+				return &value, nil
+			}
+		}).
+		BuildQuery()
+
+	handler, err := New(GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{getDefaultHelloQuery(), slow},
+		},
+		RequestTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	post := func(query string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(`{"query":"` + query + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	start := time.Now()
+	w := post("{ slow }")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request to end at the timeout, took %v", elapsed)
+	}
+	if w.Code != http.StatusRequestTimeout {
+		t.Fatalf("Expected status 408, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "request timed out after 50ms") {
+		t.Errorf("Expected a timeout error payload, got %s", w.Body.String())
+	}
+
+	if w := post("{ hello }"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 within the timeout, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWithRequestTimeout_FinishedBeforeDeadline(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	w, req, finish := withRequestTimeout(recorder, req, 20*time.Millisecond)
+
+	// The request completes just under the timeout, and the deadline passes before
+	// finish runs
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"data":{"hello":"world"}}`))
+	<-req.Context().Done()
+	finish()
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for a completed request, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Body.String(); got != `{"data":{"hello":"world"}}` {
+		t.Errorf("Expected the completed response, got %s", got)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected the response headers to be kept, got %q", got)
+	}
+}
+
+func TestNewHTTP_RequestTimeoutJustUnder(t *testing.T) {
+	almost := NewResolver[string]("almost").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			time.Sleep(60 * time.Millisecond)
+			value := "done"
+			return &value, nil
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{almost},
+		},
+		RequestTimeout: 100 * time.Millisecond,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query":"{ almost }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 under the timeout, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"almost":"done"`) {
+		t.Errorf("Expected the resolved value, got %s", w.Body.String())
+	}
+}
+
+func TestWithFieldResolverT(t *testing.T) {
+	type TypedOverrideEmployee struct {
+		ID        int    `json:"id"`
+		Name      string `json:"name"`
+		ManagerID int    `json:"managerId"`
+	}
+
+	employees := map[int]*TypedOverrideEmployee{
+		1: {ID: 1, Name: "Grace"},
+		2: {ID: 2, Name: "Ada", ManagerID: 1},
+		3: {ID: 3, Name: "Linus", ManagerID: 9},
+	}
+	resolver := NewResolver[TypedOverrideEmployee]("typedOverrideEmployee").
+		WithArgs(graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.Int}}).
+		WithResolver(func(p ResolveParams) (*TypedOverrideEmployee, error) {
+			id, _ := GetArgInt(p, "id")
+			return employees[id], nil
+		})
+	WithFieldResolverT(resolver, "name", func(employee TypedOverrideEmployee, p ResolveParams) (string, error) {
+		if employee.ManagerID == 0 {
+			return employee.Name, nil
+		}
+		manager, ok := employees[employee.ManagerID]
+		if !ok {
+			return "", NewGraphError("NOT_FOUND", "manager not found", map[string]interface{}{"managerId": employee.ManagerID})
+		}
+		return employee.Name + " (reports to " + manager.Name + ")", nil
+	})
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{resolver.BuildQuery()}},
+	})
+	serve := func(id int) map[string]interface{} {
+		body, _ := json.Marshal(map[string]string{"query": fmt.Sprintf("{ typedOverrideEmployee(id: %d) { name } }", id)})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	response := serve(2)
+	employee := response["data"].(map[string]interface{})["typedOverrideEmployee"].(map[string]interface{})
+	if employee["name"] != "Ada (reports to Grace)" {
+		t.Errorf("Expected the typed override to receive the parent, got %v", employee["name"])
+	}
+
+	response = serve(3)
+	errs, _ := response["errors"].([]interface{})
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", response)
+	}
+	extensions, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	if extensions["code"] != "NOT_FOUND" || extensions["managerId"] != float64(9) {
+		t.Errorf("Expected the coded error in extensions, got %v", errs[0])
+	}
+}
+
+func TestWithFieldResolver_CodedErrorFallsBack(t *testing.T) {
+	type FallbackEmployee struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// Errors of untyped overrides, also coded ones, fall back to the struct field
+	employee := NewResolver[FallbackEmployee]("fallbackEmployee").
+		WithFieldResolver("name", func(p graphql.ResolveParams) (interface{}, error) {
+			return nil, ErrNotFound
+		}).
+		WithResolver(func(p ResolveParams) (*FallbackEmployee, error) {
+			return &FallbackEmployee{ID: 1, Name: "Ada"}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{employee}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ fallbackEmployee { name } }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	data := result.Data.(map[string]interface{})["fallbackEmployee"].(map[string]interface{})
+	if data["name"] != "Ada" {
+		t.Errorf("Expected the struct field after the override failed, got %v", data["name"])
+	}
+}
+
+type OmitNullProfile struct {
+	ID       int       `json:"id"`
+	Nickname *string   `json:"nickname"`
+	Tags     []*string `json:"tags"`
+}
+
+func TestNewHTTP_OmitNullFields(t *testing.T) {
+	tag := "go"
+	profile := NewResolver[OmitNullProfile]("omitNullProfile").
+		WithResolver(func(p ResolveParams) (*OmitNullProfile, error) {
+			return &OmitNullProfile{ID: 1, Tags: []*string{&tag, nil}}, nil
+		}).
+		BuildQuery()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    string
+	}{
+		{name: "enabled", enabled: true, want: `{"data":{"omitNullProfile":{"id":1,"tags":["go",null]}}}`},
+		{name: "disabled", enabled: false, want: `{"data":{"omitNullProfile":{"id":1,"nickname":null,"tags":["go",null]}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				SchemaParams:   &SchemaBuilderParams{QueryFields: []QueryField{profile}},
+				OmitNullFields: tt.enabled,
+			})
+
+			body := bytes.NewBufferString(`{"query":"{ omitNullProfile { id nickname tags } }"}`)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+type TypeCollisionPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestTypeNameCollisions(t *testing.T) {
+	// Same-named Go types from different scopes, standing in for different packages
+	accountUser := func() (QueryField, QueryField) {
+		type TypeCollisionUser struct {
+			ID    int    `json:"id"`
+			Email string `json:"email"`
+		}
+		user := NewResolver[TypeCollisionUser]("accountUser").
+			WithResolver(func(p ResolveParams) (*TypeCollisionUser, error) {
+				return &TypeCollisionUser{ID: 1, Email: "ada@example.com"}, nil
+			}).
+			BuildQuery()
+		page := NewResolver[TypeCollisionPage[TypeCollisionUser]]("accountUsers").
+			WithResolver(func(p ResolveParams) (*TypeCollisionPage[TypeCollisionUser], error) {
+				return &TypeCollisionPage[TypeCollisionUser]{}, nil
+			}).
+			BuildQuery()
+		return user, page
+	}
+	billingUser := func() (QueryField, QueryField) {
+		type TypeCollisionUser struct {
+			ID      int     `json:"id"`
+			Balance float64 `json:"balance"`
+		}
+		user := NewResolver[TypeCollisionUser]("billingUser").
+			WithResolver(func(p ResolveParams) (*TypeCollisionUser, error) {
+				return &TypeCollisionUser{ID: 2, Balance: 9.5}, nil
+			}).
+			BuildQuery()
+		page := NewResolver[TypeCollisionPage[TypeCollisionUser]]("billingUsers").
+			WithResolver(func(p ResolveParams) (*TypeCollisionPage[TypeCollisionUser], error) {
+				return &TypeCollisionPage[TypeCollisionUser]{}, nil
+			}).
+			BuildQuery()
+		return user, page
+	}
+	sameFieldsUser := func() QueryField {
+		type TypeCollisionUser struct {
+			ID    int    `json:"id"`
+			Email string `json:"email"`
+		}
+		return NewResolver[TypeCollisionUser]("sameFieldsUser").
+			WithResolver(func(p ResolveParams) (*TypeCollisionUser, error) {
+				return &TypeCollisionUser{ID: 3, Email: "grace@example.com"}, nil
+			}).
+			BuildQuery()
+	}
+	accountQuery, accountPage := accountUser()
+	billingQuery, billingPage := billingUser()
+
+	t.Run("different fields fail the build", func(t *testing.T) {
+		_, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{accountQuery, billingQuery}}).Build()
+		if err == nil || !strings.Contains(err.Error(), `"TypeCollisionUser"`) {
+			t.Fatalf("Expected a type name conflict error, got %v", err)
+		}
+	})
+
+	t.Run("generic wrappers fail the build", func(t *testing.T) {
+		_, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{accountPage, billingPage}}).Build()
+		if err == nil || !strings.Contains(err.Error(), "is generated from Go types") {
+			t.Fatalf("Expected a type name conflict error, got %v", err)
+		}
+	})
+
+	t.Run("each type keeps its own fields", func(t *testing.T) {
+		schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{billingQuery}}).Build()
+		if err != nil {
+			t.Fatalf("Failed to build schema: %v", err)
+		}
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ billingUser { id balance } }"})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Expected billingUser to expose its own fields, got %v", result.Errors)
+		}
+	})
+
+	t.Run("same fields share the type", func(t *testing.T) {
+		schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{accountQuery, sameFieldsUser()}}).Build()
+		if err != nil {
+			t.Fatalf("Failed to build schema: %v", err)
+		}
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ accountUser { email } sameFieldsUser { email } }"})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	})
+}
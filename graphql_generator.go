@@ -1,9 +1,12 @@
 package graph
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -50,12 +53,20 @@ type FieldGenerator[T any] struct {
 	typeCache       map[reflect.Type]graphql.Output
 	processingTypes map[reflect.Type]bool
 	objectTypeName  *string
+	scope           *typeScope
 }
 
 func NewFieldGenerator[T any]() *FieldGenerator[T] {
+	return newScopedFieldGenerator[T](nil)
+}
+
+// newScopedFieldGenerator returns a generator registering the object types it
+// generates in scope, or in the global registries when scope is nil
+func newScopedFieldGenerator[T any](scope *typeScope) *FieldGenerator[T] {
 	return &FieldGenerator[T]{
 		typeCache:       make(map[reflect.Type]graphql.Output),
 		processingTypes: make(map[reflect.Type]bool),
+		scope:           scope,
 	}
 }
 
@@ -87,6 +98,7 @@ func (g *FieldGenerator[T]) generateFields(t reflect.Type) graphql.Fields {
 	}
 
 	fields := graphql.Fields{}
+	embeddedFrom := make(map[string]reflect.Type)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -101,10 +113,16 @@ func (g *FieldGenerator[T]) generateFields(t reflect.Type) graphql.Fields {
 			// Recursively get fields from embedded struct
 			embeddedFields := g.generateFields(embeddedType)
 			for name, embeddedField := range embeddedFields {
-				// Only add if not already present (child fields take precedence)
-				if _, exists := fields[name]; !exists {
-					fields[name] = embeddedField
+				// Only add if not already present (child fields and the first
+				// declared embedded struct take precedence)
+				if _, exists := fields[name]; exists {
+					if first, ok := embeddedFrom[name]; ok {
+						warnEmbeddedCollision(t, name, first, embeddedType)
+					}
+					continue
 				}
+				fields[name] = resolveFromEmbedded(t, field, embeddedField)
+				embeddedFrom[name] = embeddedType
 			}
 			continue
 		}
@@ -124,8 +142,9 @@ func (g *FieldGenerator[T]) generateFields(t reflect.Type) graphql.Fields {
 
 		description := field.Tag.Get("description")
 		fields[fieldName] = &graphql.Field{
-			Type:        graphqlType,
-			Description: description,
+			Type:              graphqlType,
+			Description:       description,
+			DeprecationReason: deprecationReason(field),
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				source := reflect.ValueOf(p.Source)
 				if source.Kind() == reflect.Ptr {
@@ -149,10 +168,51 @@ func (g *FieldGenerator[T]) generateFields(t reflect.Type) graphql.Fields {
 	return fields
 }
 
+// resolveFromEmbedded returns the field of an embedded struct resolving against the
+// embedded value, so a field promoted from two embedded structs or shadowed by a
+// field of the same Go name resolves the struct it was generated from
+func resolveFromEmbedded(parent reflect.Type, embedded reflect.StructField, field *graphql.Field) *graphql.Field {
+	if field.Resolve == nil || embedded.PkgPath != "" {
+		// Unexported embedded structs can't be read directly, rely on promotion
+		return field
+	}
+	resolve := field.Resolve
+	promoted := *field
+	promoted.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		source := reflect.ValueOf(p.Source)
+		for source.Kind() == reflect.Ptr && !source.IsNil() {
+			source = source.Elem()
+		}
+		if source.IsValid() && source.Type() == parent {
+			value := source.FieldByIndex(embedded.Index)
+			if value.Kind() == reflect.Ptr && value.IsNil() {
+				return nil, nil
+			}
+			p.Source = value.Interface()
+		}
+		return resolve(p)
+	}
+	return &promoted
+}
+
+// warnEmbeddedCollision logs a field defined by two embedded structs of t. The
+// struct declared first provides the field.
+func warnEmbeddedCollision(t reflect.Type, name string, first, second reflect.Type) {
+	slog.Default().Warn("graphql field defined by several embedded structs, using the first declared",
+		slog.String("type", t.String()),
+		slog.String("field", name),
+		slog.String("used", first.String()),
+		slog.String("ignored", second.String()),
+	)
+}
+
 func (g *FieldGenerator[T]) getGraphQLType(t reflect.Type, field reflect.StructField) graphql.Output {
-	isRequired := strings.Contains(field.Tag.Get("graphql"), "required")
+	isRequired := isNonNullField(field, g.scope.strict())
 
 	baseType := g.getBaseGraphQLType(t, g.objectTypeName)
+	if tagType := scalarForField(g.scope, t, field); tagType != nil {
+		baseType = tagType.(graphql.Output)
+	}
 
 	if baseType == nil {
 		return nil
@@ -167,6 +227,18 @@ func (g *FieldGenerator[T]) getGraphQLType(t reflect.Type, field reflect.StructF
 
 func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *string) graphql.Output {
 	g.objectTypeName = objectTypeName
+	if scalar := lookupScalarByType(g.scope, t); scalar != nil {
+		return scalar
+	}
+	if enumType := lookupEnumByType(t); enumType != nil {
+		return enumType
+	}
+	if abstract := lookupAbstractByType(t); abstract != nil {
+		return abstract
+	}
+	if isCustomValueType(t) {
+		return JSON
+	}
 	switch t.Kind() {
 	case reflect.Ptr:
 		return g.getBaseGraphQLType(t.Elem(), objectTypeName)
@@ -174,10 +246,13 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 	case reflect.String:
 		return graphql.String
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int64, reflect.Uint64:
+		return Int64
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		return graphql.Int
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		return graphql.Int
 
 	case reflect.Float32, reflect.Float64:
@@ -193,13 +268,8 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 		}
 		return graphql.NewList(elemType)
 
-	case reflect.Map:
-		return graphql.NewScalar(graphql.ScalarConfig{
-			Name: fmt.Sprintf("Map_%s", t.String()),
-			Serialize: func(value interface{}) interface{} {
-				return value
-			},
-		})
+	case reflect.Map, reflect.Interface:
+		return JSON
 
 	case reflect.Struct:
 		if t == reflect.TypeOf(time.Time{}) {
@@ -228,7 +298,7 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 			// Use the unified type registry from graphql_unified_resolver.go
 			// to prevent duplicate type creation across top-level and nested types
 			typeRegistryMu.RLock()
-			if existingType, exists := typeRegistry[nameObject]; exists {
+			if existingType, _ := lookupObjectType(g.scope, nameObject, t); existingType != nil {
 				typeRegistryMu.RUnlock()
 				return existingType
 			}
@@ -239,7 +309,8 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 			typeRegistryMu.Lock()
 
 			// Double-check in case another goroutine created it
-			if existingType, exists := typeRegistry[nameObject]; exists {
+			existingType, conflict := lookupObjectType(g.scope, nameObject, t)
+			if existingType != nil {
 				typeRegistryMu.Unlock()
 				return existingType
 			}
@@ -248,7 +319,8 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 			// We need to capture t in the closure
 			capturedType := t
 			newObjectType := graphql.NewObject(graphql.ObjectConfig{
-				Name: nameObject,
+				Name:       nameObject,
+				Interfaces: interfacesOf(capturedType),
 				Fields: (graphql.FieldsThunk)(func() graphql.Fields {
 					fields := g.generateFields(capturedType)
 					if len(fields) == 0 {
@@ -265,44 +337,53 @@ func (g *FieldGenerator[T]) getBaseGraphQLType(t reflect.Type, objectTypeName *s
 			})
 
 			// Register the new object type in the unified registry
-			typeRegistry[nameObject] = newObjectType
+			storeObjectType(g.scope, nameObject, t, newObjectType, conflict)
 			typeRegistryMu.Unlock()
 
 			return newObjectType
 		}
-	case reflect.Interface:
-		return graphql.NewScalar(graphql.ScalarConfig{
-			Name: "Interface",
-			Serialize: func(value interface{}) interface{} {
-				return value
-			},
-		})
-
 	default:
 		return nil
 	}
 }
 
 func (g *FieldGenerator[T]) getFieldName(field reflect.StructField) string {
-	jsonTag := field.Tag.Get("json")
-	if jsonTag != "" {
-		parts := strings.Split(jsonTag, ",")
-		if parts[0] != "" {
-			return parts[0]
-		}
+	if name, ok := taggedFieldName(field); ok {
+		return name
 	}
+	return g.toGraphQLFieldName(field.Name)
+}
 
-	graphqlTag := field.Tag.Get("graphql")
-	if graphqlTag != "" {
-		parts := strings.Split(graphqlTag, ",")
-		for _, part := range parts {
-			if !strings.Contains(part, "=") && part != "required" {
-				return part
+// renameFields moves generated fields to their new GraphQL names. Keys of renames are
+// either generated field names or Go field names of t.
+func (g *FieldGenerator[T]) renameFields(fields graphql.Fields, t reflect.Type, renames map[string]string) {
+	for from, to := range renames {
+		name := from
+		if _, exists := fields[name]; !exists && t != nil && t.Kind() == reflect.Struct {
+			if structField, ok := t.FieldByName(from); ok {
+				name = g.getFieldName(structField)
 			}
 		}
+		if field, exists := fields[name]; exists && name != to {
+			fields[to] = field
+			delete(fields, name)
+		}
 	}
+}
 
-	return g.toGraphQLFieldName(field.Name)
+// excludedNames resolves excluded fields, given as generated names or Go field names
+// of t, to the set of generated names to drop
+func (g *FieldGenerator[T]) excludedNames(t reflect.Type, excluded map[string]bool) map[string]bool {
+	names := make(map[string]bool, len(excluded))
+	for name := range excluded {
+		names[name] = true
+		if t != nil && t.Kind() == reflect.Struct {
+			if structField, ok := t.FieldByName(name); ok {
+				names[g.getFieldName(structField)] = true
+			}
+		}
+	}
+	return names
 }
 
 func (g *FieldGenerator[T]) toGraphQLFieldName(name string) string {
@@ -326,6 +407,82 @@ func GenerateInputObject[T any](name string) *graphql.InputObject {
 	})
 }
 
+// InputObjectFromValue generates an input object from the type of value, like
+// GenerateInputObject, using the non-zero fields of value as the default values of
+// the input fields. Zero fields keep the default of their `default` tag, if any.
+//
+// Example:
+//
+//	defaults := SearchInput{Limit: config.PageSize, Sort: "created_at"}
+//	input := graph.InputObjectFromValue("SearchInput", defaults)
+func InputObjectFromValue[T any](name string, value T) *graphql.InputObject {
+	gen := NewFieldGenerator[T]()
+	v := reflect.ValueOf(value)
+	fields := gen.generateInputFields(v.Type())
+	for fieldName, defaultValue := range gen.inputFieldDefaults(v) {
+		if field, exists := fields[fieldName]; exists {
+			field.DefaultValue = defaultValue
+		}
+	}
+
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   name,
+		Fields: fields,
+	})
+}
+
+// inputFieldDefaults returns the non-zero fields of the struct value v by input field
+// name. Nested structs and maps are converted to their JSON representation.
+func (g *FieldGenerator[T]) inputFieldDefaults(v reflect.Value) map[string]interface{} {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	defaults := make(map[string]interface{})
+	if v.Kind() != reflect.Struct {
+		return defaults
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// Embedded fields are flattened, child fields take precedence
+		if field.Anonymous {
+			for name, value := range g.inputFieldDefaults(v.Field(i)) {
+				if _, exists := defaults[name]; !exists {
+					defaults[name] = value
+				}
+			}
+			continue
+		}
+
+		fieldName := g.getFieldName(field)
+		if field.PkgPath != "" || fieldName == "-" || v.Field(i).IsZero() {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		for fieldValue.Kind() == reflect.Ptr {
+			fieldValue = fieldValue.Elem()
+		}
+		switch fieldValue.Kind() {
+		case reflect.Struct, reflect.Map:
+			data, err := json.Marshal(fieldValue.Interface())
+			if err != nil {
+				continue
+			}
+			var decoded interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				continue
+			}
+			defaults[fieldName] = decoded
+		default:
+			defaults[fieldName] = fieldValue.Interface()
+		}
+	}
+	return defaults
+}
+
 func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObjectConfigFieldMap {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -336,6 +493,7 @@ func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObj
 	}
 
 	fields := graphql.InputObjectConfigFieldMap{}
+	embeddedFrom := make(map[string]reflect.Type)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -350,10 +508,16 @@ func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObj
 			// Recursively get fields from embedded struct
 			embeddedFields := g.generateInputFields(embeddedType)
 			for name, embeddedField := range embeddedFields {
-				// Only add if not already present (child fields take precedence)
-				if _, exists := fields[name]; !exists {
-					fields[name] = embeddedField
+				// Only add if not already present (child fields and the first
+				// declared embedded struct take precedence)
+				if _, exists := fields[name]; exists {
+					if first, ok := embeddedFrom[name]; ok {
+						warnEmbeddedCollision(t, name, first, embeddedType)
+					}
+					continue
 				}
+				fields[name] = embeddedField
+				embeddedFrom[name] = embeddedType
 			}
 			continue
 		}
@@ -372,7 +536,7 @@ func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObj
 			continue
 		}
 
-		description := field.Tag.Get("description")
+		description := inputDescription(field)
 		defaultValue := field.Tag.Get("default")
 
 		fieldConfig := &graphql.InputObjectFieldConfig{
@@ -381,7 +545,7 @@ func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObj
 		}
 
 		if defaultValue != "" {
-			fieldConfig.DefaultValue = defaultValue
+			fieldConfig.DefaultValue = tagDefaultValue(defaultValue, graphqlType)
 		}
 
 		fields[fieldName] = fieldConfig
@@ -391,9 +555,12 @@ func (g *FieldGenerator[T]) generateInputFields(t reflect.Type) graphql.InputObj
 }
 
 func (g *FieldGenerator[T]) getInputType(t reflect.Type, field reflect.StructField) graphql.Input {
-	isRequired := strings.Contains(field.Tag.Get("graphql"), "required")
+	isRequired := isNonNullInputField(field)
 
 	baseType := g.getBaseInputType(t, field.Name)
+	if tagType := scalarForField(g.scope, t, field); tagType != nil {
+		baseType = tagType.(graphql.Input)
+	}
 
 	if baseType == nil {
 		return nil
@@ -407,9 +574,12 @@ func (g *FieldGenerator[T]) getInputType(t reflect.Type, field reflect.StructFie
 }
 
 func (g *FieldGenerator[T]) getInputTypeWithContext(t reflect.Type, field reflect.StructField, parentTypeName string) graphql.Input {
-	isRequired := strings.Contains(field.Tag.Get("graphql"), "required")
+	isRequired := isNonNullInputField(field)
 
 	baseType := g.getBaseInputTypeWithContext(t, field.Name, parentTypeName)
+	if tagType := scalarForField(g.scope, t, field); tagType != nil {
+		baseType = tagType.(graphql.Input)
+	}
 
 	if baseType == nil {
 		return nil
@@ -427,6 +597,12 @@ func (g *FieldGenerator[T]) getBaseInputType(t reflect.Type, fieldName string) g
 }
 
 func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldName string, parentTypeName string) graphql.Input {
+	if scalar := lookupScalarByType(g.scope, t); scalar != nil {
+		return scalar
+	}
+	if enumType := lookupEnumByType(t); enumType != nil {
+		return enumType
+	}
 	switch t.Kind() {
 	case reflect.Ptr:
 		return g.getBaseInputTypeWithContext(t.Elem(), fieldName, parentTypeName)
@@ -434,10 +610,13 @@ func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldNam
 	case reflect.String:
 		return graphql.String
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int64, reflect.Uint64:
+		return Int64
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		return graphql.Int
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		return graphql.Int
 
 	case reflect.Float32, reflect.Float64:
@@ -453,6 +632,9 @@ func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldNam
 		}
 		return graphql.NewList(elemType)
 
+	case reflect.Map, reflect.Interface:
+		return JSON
+
 	case reflect.Struct:
 		// Use parent type name for anonymous structs, otherwise use the field name
 		var inputTypeName string
@@ -464,9 +646,9 @@ func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldNam
 			inputTypeName = getInputTypeName(t, fieldName)
 		}
 
-		// Check if input type already exists in the global registry (from unified resolver)
+		// Check if input type already exists in the registry of the scope (from unified resolver)
 		inputTypeRegistryMu.RLock()
-		if existingType, exists := inputTypeRegistry[inputTypeName]; exists {
+		if existingType, exists := g.scope.inputTypes()[inputTypeName]; exists {
 			inputTypeRegistryMu.RUnlock()
 			return existingType
 		}
@@ -477,7 +659,7 @@ func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldNam
 		inputTypeRegistryMu.Lock()
 
 		// Double-check in case another goroutine created it
-		if existingType, exists := inputTypeRegistry[inputTypeName]; exists {
+		if existingType, exists := g.scope.inputTypes()[inputTypeName]; exists {
 			inputTypeRegistryMu.Unlock()
 			return existingType
 		}
@@ -492,7 +674,8 @@ func (g *FieldGenerator[T]) getBaseInputTypeWithContext(t reflect.Type, fieldNam
 		})
 
 		// Register the new input type
-		inputTypeRegistry[inputTypeName] = newInputType
+		g.scope.inputTypes()[inputTypeName] = newInputType
+		inputGoTypes[newInputType] = t
 		inputTypeRegistryMu.Unlock()
 
 		return newInputType
@@ -560,7 +743,7 @@ func GenerateArgsFromStruct[T any]() graphql.FieldConfigArgument {
 			continue
 		}
 
-		description := field.Tag.Get("description")
+		description := inputDescription(field)
 		defaultValue := field.Tag.Get("default")
 
 		argConfig := &graphql.ArgumentConfig{
@@ -569,7 +752,7 @@ func GenerateArgsFromStruct[T any]() graphql.FieldConfigArgument {
 		}
 
 		if defaultValue != "" {
-			argConfig.DefaultValue = defaultValue
+			argConfig.DefaultValue = tagDefaultValue(defaultValue, graphqlType)
 		}
 
 		args[fieldName] = argConfig
@@ -624,7 +807,7 @@ func processStructArgs[T any](gen *FieldGenerator[T], t reflect.Type) graphql.Fi
 			continue
 		}
 
-		description := field.Tag.Get("description")
+		description := inputDescription(field)
 		defaultValue := field.Tag.Get("default")
 
 		argConfig := &graphql.ArgumentConfig{
@@ -633,7 +816,7 @@ func processStructArgs[T any](gen *FieldGenerator[T], t reflect.Type) graphql.Fi
 		}
 
 		if defaultValue != "" {
-			argConfig.DefaultValue = defaultValue
+			argConfig.DefaultValue = tagDefaultValue(defaultValue, graphqlType)
 		}
 
 		args[fieldName] = argConfig
@@ -694,8 +877,9 @@ func (g *FieldGenerator[T]) createWrapperObject(t reflect.Type, typeName string)
 
 				description := field.Tag.Get("description")
 				fields[fieldName] = &graphql.Field{
-					Type:        graphqlType,
-					Description: description,
+					Type:              graphqlType,
+					Description:       description,
+					DeprecationReason: deprecationReason(field),
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						source := reflect.ValueOf(p.Source)
 						if source.Kind() == reflect.Ptr {
@@ -717,3 +901,28 @@ func (g *FieldGenerator[T]) createWrapperObject(t reflect.Type, typeName string)
 		}),
 	})
 }
+
+// tagDefaultValue converts the value of a `default` tag to the Go value of its
+// GraphQL type, so Int, Int64, Float and Boolean arguments default to an int, an
+// int64, a float64 and a bool instead of a string. Values that don't parse are kept as strings.
+func tagDefaultValue(raw string, t graphql.Input) interface{} {
+	switch graphql.GetNullable(t) {
+	case graphql.Int:
+		if value, err := strconv.Atoi(raw); err == nil {
+			return value
+		}
+	case Int64:
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value
+		}
+	case graphql.Float:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case graphql.Boolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	}
+	return raw
+}
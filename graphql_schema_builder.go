@@ -1,6 +1,10 @@
 package graph
 
 import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
 	"github.com/graphql-go/graphql"
 )
 
@@ -30,6 +34,37 @@ type SchemaBuilderParams struct {
 	// SubscriptionFields: List of subscription fields to include in the schema
 	// Requires WebSocket support and PubSub configuration
 	SubscriptionFields []SubscriptionField `group:"subscription_fields"`
+
+	// Scalars: Custom scalars to include in the schema (optional)
+	// Fields and arguments of the query and mutation resolvers of this schema select
+	// them with a graphql tag option naming the scalar (`graphql:"price,decimal"` or `graphql:"scalar=Decimal"`). Unlike
+	// RegisterScalar they are not registered globally, so other schemas don't see them.
+	Scalars []*graphql.Scalar
+
+	// ScalarTypes: Go types mapped to custom scalars in this schema only (optional)
+	// Fields and arguments of these types in the query and mutation resolvers use the
+	// scalar, like RegisterScalar, and the scalars are included in the schema:
+	//
+	//	ScalarTypes: map[reflect.Type]*graphql.Scalar{
+	//	    reflect.TypeOf(Decimal("")): DecimalScalar,
+	//	}
+	ScalarTypes map[reflect.Type]*graphql.Scalar
+
+	// Debug: Fail Build for misconfigured fields, such as a subscription built without
+	// WithResolver or WithSubscriptionTopic. Otherwise they are logged as warnings and
+	// fail when they are used. NewHTTP and New set it in DEBUG mode.
+	Debug bool
+
+	// StrictNullability: Derive output field nullability from Go types. Pointer fields
+	// are nullable even when tagged `graphql:"required"`, since they can hold nil, and
+	// other fields are non-null when tagged `graphql:"required"`:
+	//
+	//	ApprovalStatus *bool `json:"approvalStatus" graphql:"approvalStatus,required"` // Boolean
+	//	Active         bool  `json:"active" graphql:"active,required"`                 // Boolean!
+	//
+	// It applies to this schema only: the object types of its resolvers are generated
+	// for it rather than shared with other schemas.
+	StrictNullability bool
 }
 
 // SchemaBuilder builds GraphQL schemas from QueryFields and MutationFields.
@@ -38,6 +73,31 @@ type SchemaBuilder struct {
 	queryFields        []QueryField
 	mutationFields     []MutationField
 	subscriptionFields []SubscriptionField
+	scalars            []*graphql.Scalar
+	scalarTypes        map[reflect.Type]*graphql.Scalar
+	debug              bool
+	strictNullability  bool
+}
+
+// scopedField is implemented by fields that can generate their types in the scope of
+// the schema being built
+type scopedField interface {
+	serveInScope(scope *typeScope) *graphql.Field
+}
+
+// serveField returns the field configuration of field, with its types generated in
+// scope when the field supports it
+func serveField(field interface{ Serve() *graphql.Field }, scope *typeScope) *graphql.Field {
+	if scoped, ok := field.(scopedField); ok && scope != nil {
+		return scoped.serveInScope(scope)
+	}
+	return field.Serve()
+}
+
+// configChecker is implemented by fields that can report a misconfiguration when
+// the schema is built
+type configChecker interface {
+	configError() error
 }
 
 // NewSchemaBuilder creates a new schema builder with the provided query and mutation fields.
@@ -55,6 +115,10 @@ func NewSchemaBuilder(params SchemaBuilderParams) *SchemaBuilder {
 		queryFields:        params.QueryFields,
 		mutationFields:     params.MutationFields,
 		subscriptionFields: params.SubscriptionFields,
+		scalars:            params.Scalars,
+		scalarTypes:        params.ScalarTypes,
+		debug:              params.Debug,
+		strictNullability:  params.StrictNullability,
 	}
 }
 
@@ -64,6 +128,7 @@ func NewSchemaBuilder(params SchemaBuilderParams) *SchemaBuilder {
 // Returns an error if:
 //   - Schema construction fails due to type conflicts
 //   - Field configurations are invalid
+//   - A subscription has no resolver, when Debug is set
 //
 // The schema can have:
 //   - Only queries (no mutations)
@@ -71,23 +136,56 @@ func NewSchemaBuilder(params SchemaBuilderParams) *SchemaBuilder {
 //   - Both queries and mutations
 //   - Neither (empty schema)
 func (sb *SchemaBuilder) Build() (graphql.Schema, error) {
+	// Schemas with their own generation settings or scalars get their own types
+	var scope *typeScope
+	if sb.strictNullability || len(sb.scalars) > 0 || len(sb.scalarTypes) > 0 {
+		scope = newTypeScope(sb.strictNullability, sb.scalars, sb.scalarTypes)
+	}
+
+	// Subscription fields (fields with a Subscribe function) only work in the subscription root
 	queryFields := graphql.Fields{}
 	for _, field := range sb.queryFields {
-		queryFields[field.Name()] = field.Serve()
+		queryFields[field.Name()] = serveField(field, scope)
+		if queryFields[field.Name()].Subscribe != nil {
+			return graphql.Schema{}, fmt.Errorf("query field %q is a subscription field; pass it in SubscriptionFields instead of QueryFields", field.Name())
+		}
 	}
 
 	mutationFields := graphql.Fields{}
 	for _, field := range sb.mutationFields {
-		mutationFields[field.Name()] = field.Serve()
+		mutationFields[field.Name()] = serveField(field, scope)
+		if mutationFields[field.Name()].Subscribe != nil {
+			return graphql.Schema{}, fmt.Errorf("mutation field %q is a subscription field; pass it in SubscriptionFields instead of MutationFields", field.Name())
+		}
 	}
 
 	subscriptionFields := graphql.Fields{}
 	for _, field := range sb.subscriptionFields {
 		subscriptionFields[field.Name()] = field.Serve()
+		if checker, ok := field.(configChecker); ok {
+			if err := checker.configError(); err != nil {
+				if sb.debug {
+					return graphql.Schema{}, err
+				}
+				slog.Warn("misconfigured subscription field", "field", field.Name(), "error", err)
+			}
+		}
 	}
 
 	schemaConfig := graphql.SchemaConfig{}
 
+	// Include the custom scalars even when no field uses them
+	for _, scalar := range sb.scalars {
+		if scalar != nil {
+			schemaConfig.Types = append(schemaConfig.Types, scalar)
+		}
+	}
+	for _, scalar := range sb.scalarTypes {
+		if scalar != nil {
+			schemaConfig.Types = append(schemaConfig.Types, scalar)
+		}
+	}
+
 	if len(queryFields) > 0 {
 		schemaConfig.Query = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "Query",
@@ -109,5 +207,88 @@ func (sb *SchemaBuilder) Build() (graphql.Schema, error) {
 		})
 	}
 
-	return graphql.NewSchema(schemaConfig)
+	if err := checkTypeNameConflicts(schemaConfig.Query, schemaConfig.Mutation, schemaConfig.Subscription); err != nil {
+		return graphql.Schema{}, err
+	}
+
+	schema, err := graphql.NewSchema(schemaConfig)
+	if err != nil {
+		return schema, err
+	}
+
+	// Implementations of registered interfaces are not reachable from the interface
+	// itself; add them until the schema has all implementations of its interfaces
+	for missing := interfaceImplementations(schema); len(missing) > 0; missing = interfaceImplementations(schema) {
+		schemaConfig.Types = append(schemaConfig.Types, missing...)
+		if schema, err = graphql.NewSchema(schemaConfig); err != nil {
+			return schema, err
+		}
+	}
+	return schema, nil
+}
+
+// checkTypeNameConflicts returns an error when two different object types reachable
+// from the root types have the same name, as generated for same-named Go types of
+// different packages with different fields
+func checkTypeNameConflicts(roots ...*graphql.Object) error {
+	seen := make(map[string]*graphql.Object)
+	var visit func(t graphql.Type) error
+	visit = func(t graphql.Type) error {
+		for {
+			if wrapper, ok := t.(*graphql.List); ok {
+				t = wrapper.OfType
+			} else if wrapper, ok := t.(*graphql.NonNull); ok {
+				t = wrapper.OfType
+			} else {
+				break
+			}
+		}
+		object, ok := t.(*graphql.Object)
+		if !ok || object == nil {
+			return nil
+		}
+		if other, exists := seen[object.Name()]; exists {
+			if other != object {
+				return typeNameConflictError(object.Name(), other, object)
+			}
+			return nil
+		}
+		seen[object.Name()] = object
+		for _, field := range object.Fields() {
+			if err := visit(field.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if root == nil {
+			continue
+		}
+		if err := visit(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeNameConflictError describes two object types generated with the same name
+func typeNameConflictError(name string, first, second *graphql.Object) error {
+	typeRegistryMu.RLock()
+	firstType, secondType := objectGoTypes[first], objectGoTypes[second]
+	typeRegistryMu.RUnlock()
+	return fmt.Errorf("GraphQL type name %q is generated from Go types %s and %s, which have different fields; rename one of them",
+		name, goTypeName(firstType), goTypeName(secondType))
+}
+
+// goTypeName returns the package-qualified name of t
+func goTypeName(t reflect.Type) string {
+	if t == nil {
+		return "(unknown)"
+	}
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
 }
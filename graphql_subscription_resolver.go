@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/graphql-go/graphql"
 )
@@ -31,6 +32,7 @@ type SubscriptionField interface {
 type subscriptionField struct {
 	name  string
 	field *graphql.Field
+	err   error
 }
 
 func (s *subscriptionField) Serve() *graphql.Field {
@@ -41,6 +43,11 @@ func (s *subscriptionField) Name() string {
 	return s.name
 }
 
+// configError returns the misconfiguration found by BuildSubscription, if any
+func (s *subscriptionField) configError() error {
+	return s.err
+}
+
 // SubscriptionResolver builds type-safe subscription fields with extensive customization capabilities.
 // It provides a fluent API similar to UnifiedResolver for building subscriptions.
 //
@@ -107,13 +114,95 @@ type SubscriptionResolver[T any] struct {
 	name            string
 	description     string
 	args            graphql.FieldConfigArgument
+	argsType        reflect.Type
 	resolver        SubscriptionResolveFn[T]
 	filterFn        SubscriptionFilterFn[T]
+	transformFn     SubscriptionTransformFn[T]
+	initialValueFn  SubscriptionInitialValueFn[T]
+	batchWindow     time.Duration
 	middleware      []FieldMiddleware
 	fieldMiddleware map[string][]FieldMiddleware
 	fieldResolvers  map[string]graphql.FieldResolveFn
 	generatedType   *graphql.Object
 	objectName      string
+	metrics         SubscriptionMetrics
+	bufferSize      int
+	overflowPolicy  OverflowPolicy
+}
+
+// OverflowPolicy controls what happens to a subscription event when the
+// subscription's output buffer is full because the client is reading slowly.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the client has room for the event (default)
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the incoming event and keeps the buffered ones
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest buffered event to make room for the incoming one
+	OverflowDropOldest
+)
+
+// defaultSubscriptionBufferSize is the output buffer size used when neither the
+// subscription nor the server configures one
+const defaultSubscriptionBufferSize = 10
+
+// SubscriptionDefaults holds server-wide subscription settings. They apply to every
+// subscription that does not configure its own buffer or metrics.
+// NewHTTP fills them from GraphContext; when executing subscriptions manually,
+// attach them to the context with WithSubscriptionDefaults.
+type SubscriptionDefaults struct {
+	// BufferSize: Output buffer size per subscription (default: 10)
+	BufferSize int
+
+	// OverflowPolicy: What to do with events when the buffer is full (default: OverflowBlock)
+	OverflowPolicy OverflowPolicy
+
+	// Metrics: Metrics used by subscriptions without their own WithMetrics
+	Metrics SubscriptionMetrics
+}
+
+type subscriptionDefaultsKey struct{}
+
+// subscriptionArgsKey stores the arguments decoded for WithArgsFromStruct
+type subscriptionArgsKey struct{}
+
+// WithSubscriptionDefaults returns a context carrying server-wide subscription defaults.
+//
+// Example:
+//
+//	ctx = graph.WithSubscriptionDefaults(ctx, graph.SubscriptionDefaults{
+//	    BufferSize:     100,
+//	    OverflowPolicy: graph.OverflowDropOldest,
+//	})
+func WithSubscriptionDefaults(ctx context.Context, defaults SubscriptionDefaults) context.Context {
+	return context.WithValue(ctx, subscriptionDefaultsKey{}, defaults)
+}
+
+// subscriptionDefaultsFromContext returns the subscription defaults attached to ctx
+func subscriptionDefaultsFromContext(ctx context.Context) SubscriptionDefaults {
+	defaults, _ := ctx.Value(subscriptionDefaultsKey{}).(SubscriptionDefaults)
+	return defaults
+}
+
+// SubscriptionArgs returns the arguments decoded for a subscription configured with
+// WithArgsFromStruct. They are available in the resolver, middleware and filter. It
+// returns false when the subscription has no struct arguments of type A.
+//
+// Example:
+//
+//	WithFilter(func(ctx context.Context, event *MessageEvent, p ResolveParams) bool {
+//	    filter, _ := graph.SubscriptionArgs[MessageFilter](p)
+//	    return filter.Author == "" || event.Author == filter.Author
+//	})
+func SubscriptionArgs[A any](p ResolveParams) (*A, bool) {
+	if p.Context == nil {
+		return nil, false
+	}
+	args, ok := p.Context.Value(subscriptionArgsKey{}).(*A)
+	return args, ok
 }
 
 // SubscriptionResolveFn is the resolver function for subscriptions.
@@ -163,6 +252,32 @@ type SubscriptionResolveFn[T any] func(ctx context.Context, p ResolveParams) (<-
 //	}
 type SubscriptionFilterFn[T any] func(ctx context.Context, data *T, p ResolveParams) bool
 
+// SubscriptionTransformFn transforms or enriches an event before it is sent to the client.
+// Returning an error sends it to the client as a GraphQL error for that event; the
+// subscription keeps running. Returning a nil event skips it.
+//
+// Example:
+//
+//	func(ctx context.Context, data *MessageEvent) (*MessageEvent, error) {
+//	    author, err := loadUser(ctx, data.AuthorID)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    data.Author = author
+//	    return data, nil
+//	}
+type SubscriptionTransformFn[T any] func(ctx context.Context, data *T) (*T, error)
+
+// SubscriptionInitialValueFn loads the current value sent as the first event of a
+// subscription, before its live events. Returning a nil value sends no first event.
+//
+// Example:
+//
+//	func(ctx context.Context, p ResolveParams) (*Metrics, error) {
+//	    return metricsService.Current(ctx)
+//	}
+type SubscriptionInitialValueFn[T any] func(ctx context.Context, p ResolveParams) (*T, error)
+
 // NewSubscription creates a new subscription resolver with the specified name.
 // The type parameter T determines the event type that will be sent to subscribers.
 //
@@ -217,6 +332,33 @@ func (s *SubscriptionResolver[T]) WithArgs(args graphql.FieldConfigArgument) *Su
 	return s
 }
 
+// WithArgsFromStruct generates the subscription arguments from a struct, using the same
+// tags as UnifiedResolver.WithArgsFromStruct. The arguments are decoded into the struct
+// before the resolver runs and are available via SubscriptionArgs.
+//
+// Example:
+//
+//	type MessageFilter struct {
+//	    ChannelID string `graphql:"channelID,required"`
+//	    Author    string `graphql:"author"`
+//	}
+//
+//	NewSubscription[MessageEvent]("messageAdded").
+//	    WithArgsFromStruct(MessageFilter{}).
+//	    WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *MessageEvent, error) {
+//	        filter, _ := SubscriptionArgs[MessageFilter](p)
+//	        return subscribeToChannel(ctx, filter.ChannelID)
+//	    })
+func (s *SubscriptionResolver[T]) WithArgsFromStruct(structType interface{}) *SubscriptionResolver[T] {
+	t := reflect.TypeOf(structType)
+	s.args = generateArgsFromType(t)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s.argsType = t
+	return s
+}
+
 // WithResolver sets the subscription resolver function.
 // The resolver should return a channel that emits events of type T.
 //
@@ -245,6 +387,62 @@ func (s *SubscriptionResolver[T]) WithResolver(fn SubscriptionResolveFn[T]) *Sub
 	return s
 }
 
+// WithSubscriptionTopic sets a resolver that forwards the events published on a PubSub
+// topic, decoded into T. topicFn picks the topic from the subscription's arguments.
+// The PubSub subscription is torn down when the client unsubscribes or disconnects,
+// so no subscription is leaked. Messages that cannot be decoded into T are skipped.
+//
+// Example:
+//
+//	sub := graph.NewSubscription[MessageEvent]("messageAdded").
+//	    WithArgs(graphql.FieldConfigArgument{
+//	        "channelID": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+//	    }).
+//	    WithSubscriptionTopic(pubsub, func(p graph.ResolveParams) string {
+//	        channelID, _ := graph.GetArgString(p, "channelID")
+//	        return "messages:" + channelID
+//	    }).
+//	    BuildSubscription()
+func (s *SubscriptionResolver[T]) WithSubscriptionTopic(pubsub PubSub, topicFn func(p ResolveParams) string) *SubscriptionResolver[T] {
+	s.resolver = func(ctx context.Context, p ResolveParams) (<-chan *T, error) {
+		if pubsub == nil {
+			return nil, fmt.Errorf("pubsub not configured for subscription %s", s.name)
+		}
+
+		// Cancelling subCtx ends the PubSub subscription
+		subCtx, cancel := context.WithCancel(ctx)
+		messages := pubsub.Subscribe(subCtx, topicFn(p))
+
+		events := make(chan *T)
+		go func() {
+			defer close(events)
+			defer cancel()
+			for {
+				select {
+				case <-subCtx.Done():
+					return
+				case msg, ok := <-messages:
+					if !ok {
+						return
+					}
+					event, err := UnmarshalSubscriptionMessage[T](msg)
+					if err != nil {
+						continue
+					}
+					select {
+					case events <- event:
+					case <-subCtx.Done():
+						return
+					}
+				}
+			}
+		}()
+
+		return events, nil
+	}
+	return s
+}
+
 // WithFilter adds a filter function to filter events before sending to clients.
 // Only events that pass the filter (return true) will be sent.
 //
@@ -259,6 +457,60 @@ func (s *SubscriptionResolver[T]) WithFilter(fn SubscriptionFilterFn[T]) *Subscr
 	return s
 }
 
+// WithEventTransform adds a function that transforms each event after the filter
+// and before delivery, e.g. to hydrate related objects.
+// Transform errors are delivered to the client as GraphQL errors.
+//
+// Example:
+//
+//	WithEventTransform(func(ctx context.Context, data *MessageEvent) (*MessageEvent, error) {
+//	    enriched := *data
+//	    enriched.Author, _ = loadUser(ctx, data.AuthorID)
+//	    return &enriched, nil
+//	})
+func (s *SubscriptionResolver[T]) WithEventTransform(fn SubscriptionTransformFn[T]) *SubscriptionResolver[T] {
+	s.transformFn = fn
+	return s
+}
+
+// WithInitialValue sends a snapshot of the current value as the first event, followed
+// by the live events, e.g. for dashboards that must render before the next update.
+// The snapshot is loaded after the resolver has subscribed, so no update published in
+// between is missed; such an update is delivered after the snapshot. Like live events,
+// the snapshot passes the filter and the transform. An error is delivered to the
+// client as a GraphQL error in place of the snapshot, and the live events still follow.
+//
+// Example:
+//
+//	sub := NewSubscription[Metrics]("metricsUpdated").
+//	    WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "metrics" }).
+//	    WithInitialValue(func(ctx context.Context, p ResolveParams) (*Metrics, error) {
+//	        return metricsService.Current(ctx)
+//	    }).
+//	    BuildSubscription()
+func (s *SubscriptionResolver[T]) WithInitialValue(fn SubscriptionInitialValueFn[T]) *SubscriptionResolver[T] {
+	s.initialValueFn = fn
+	return s
+}
+
+// WithBatchWindow coalesces the events of high-frequency subscriptions: events arriving
+// within d of the first pending event are delivered together as one []T.
+// The subscription field type becomes a list of the event type, so clients receive
+// one payload per flush. Filters and transforms still run per event.
+//
+// Example:
+//
+//	sub := NewSubscription[PriceTick]("priceTicks").
+//	    WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "prices" }).
+//	    WithBatchWindow(100 * time.Millisecond).
+//	    BuildSubscription()
+//
+//	// subscription { priceTicks { symbol price } } receives [{...}, {...}] per flush
+func (s *SubscriptionResolver[T]) WithBatchWindow(d time.Duration) *SubscriptionResolver[T] {
+	s.batchWindow = d
+	return s
+}
+
 // WithMiddleware adds middleware to the subscription resolver.
 // Middleware is executed in the order it's added.
 //
@@ -271,6 +523,32 @@ func (s *SubscriptionResolver[T]) WithMiddleware(middleware FieldMiddleware) *Su
 	return s
 }
 
+// WithMetrics records subscription lifecycle metrics: active subscriptions,
+// events delivered, events dropped and subscription durations.
+//
+// Example:
+//
+//	metrics := NewMetricsRegistry()
+//	WithMetrics(metrics)
+func (s *SubscriptionResolver[T]) WithMetrics(metrics SubscriptionMetrics) *SubscriptionResolver[T] {
+	s.metrics = metrics
+	return s
+}
+
+// WithSubscriptionBuffer sets the output buffer size and overflow policy for this
+// subscription, overriding the server-wide SubscriptionDefaults.
+// Events dropped by the overflow policy are reported via SubscriptionMetrics.EventDropped.
+//
+// Example:
+//
+//	// Keep only the 50 most recent price updates for slow clients
+//	WithSubscriptionBuffer(50, OverflowDropOldest)
+func (s *SubscriptionResolver[T]) WithSubscriptionBuffer(size int, policy OverflowPolicy) *SubscriptionResolver[T] {
+	s.bufferSize = size
+	s.overflowPolicy = policy
+	return s
+}
+
 // WithFieldResolver overrides the resolver for a specific field in the event type.
 // This allows customizing how specific fields are resolved.
 //
@@ -325,10 +603,23 @@ func (s *SubscriptionResolver[T]) BuildSubscription() SubscriptionField {
 	subscribeFn := s.buildSubscribeFn()
 	resolveFn := s.buildResolveFn()
 
+	// Batched subscriptions deliver lists of events
+	var fieldType graphql.Output = s.generatedType
+	if s.batchWindow > 0 {
+		fieldType = graphql.NewList(s.generatedType)
+	}
+
+	// Without a resolver the field fails on subscribe; report it when the schema is built
+	var err error
+	if s.resolver == nil {
+		err = fmt.Errorf("subscription resolver not configured for %s", s.name)
+	}
+
 	return &subscriptionField{
 		name: s.name,
+		err:  err,
 		field: &graphql.Field{
-			Type:        s.generatedType,
+			Type:        fieldType,
 			Args:        s.args,
 			Description: s.description,
 			Subscribe:   subscribeFn,
@@ -407,24 +698,65 @@ func (s *SubscriptionResolver[T]) buildSubscribeFn() graphql.FieldResolveFn {
 
 		// Call the resolver to get the event channel
 		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if s.argsType != nil && s.argsType.Kind() == reflect.Struct {
+			decoded := reflect.New(s.argsType)
+			if err := mapArgsToStruct(p.Args, decoded.Interface()); err != nil {
+				return nil, fmt.Errorf("failed to decode arguments for %s: %w", s.name, err)
+			}
+			ctx = context.WithValue(ctx, subscriptionArgsKey{}, decoded.Interface())
+			p.Context = ctx
+		}
 		eventChannel, err := wrappedResolver(ctx, ResolveParams(p))
 		if err != nil {
 			return nil, err
 		}
 
+		// Resolve buffer settings: subscription options win over server-wide defaults
+		defaults := subscriptionDefaultsFromContext(ctx)
+		bufferSize, policy := s.bufferSize, s.overflowPolicy
+		if bufferSize <= 0 {
+			bufferSize, policy = defaults.BufferSize, defaults.OverflowPolicy
+		}
+		if bufferSize <= 0 {
+			bufferSize = defaultSubscriptionBufferSize
+		}
+		metrics := s.metrics
+		if metrics == nil {
+			metrics = defaults.Metrics
+		}
+
 		// Convert the typed channel to interface{} channel for graphql-go
-		outputChannel := make(chan interface{}, 10)
+		outputChannel := make(chan interface{}, bufferSize)
+
+		if metrics != nil {
+			metrics.SubscriptionStarted(s.name)
+		}
+		startedAt := time.Now()
 
 		go func() {
 			defer close(outputChannel)
+			if metrics != nil {
+				defer func() {
+					metrics.SubscriptionEnded(s.name, time.Since(startedAt))
+				}()
+			}
+			if s.initialValueFn != nil && !s.sendInitialValue(ctx, ResolveParams(p), outputChannel, policy, metrics) {
+				return
+			}
+			if s.batchWindow > 0 {
+				s.forwardBatches(ctx, ResolveParams(p), eventChannel, outputChannel, policy, metrics)
+				return
+			}
 			for event := range eventChannel {
-				// Apply filter if defined
-				if s.filterFn != nil && !s.filterFn(ctx, event, ResolveParams(p)) {
+				value, deliver := s.prepareEvent(ctx, event, ResolveParams(p))
+				if !deliver {
 					continue
 				}
-				// Send the dereferenced event (graphql-go expects the actual struct, not pointer)
-				if event != nil {
-					outputChannel <- *event
+				if !s.sendEvent(ctx, outputChannel, value, policy, metrics) {
+					return
 				}
 			}
 		}()
@@ -433,10 +765,169 @@ func (s *SubscriptionResolver[T]) buildSubscribeFn() graphql.FieldResolveFn {
 	}
 }
 
+// prepareEvent applies the filter and transform to an event. It returns the value to
+// deliver (the dereferenced event, or the transform error) and whether to deliver it.
+func (s *SubscriptionResolver[T]) prepareEvent(ctx context.Context, event *T, p ResolveParams) (interface{}, bool) {
+	// Apply filter if defined
+	if s.filterFn != nil && !s.filterFn(ctx, event, p) {
+		return nil, false
+	}
+	if event == nil {
+		return nil, false
+	}
+	// Apply transform if defined; errors are sent in place of the event
+	if s.transformFn != nil {
+		transformed, err := s.transformFn(ctx, event)
+		if err != nil {
+			return err, true
+		}
+		if transformed == nil {
+			return nil, false
+		}
+		event = transformed
+	}
+	// Send the dereferenced event (graphql-go expects the actual struct, not pointer)
+	return *event, true
+}
+
+// sendInitialValue loads the snapshot of WithInitialValue and delivers it, or its
+// error, as the first event. It returns false when the subscription context is done.
+func (s *SubscriptionResolver[T]) sendInitialValue(ctx context.Context, p ResolveParams, output chan interface{}, policy OverflowPolicy, metrics SubscriptionMetrics) bool {
+	initial, err := s.initialValueFn(ctx, p)
+	if err != nil {
+		return s.sendEvent(ctx, output, err, policy, metrics)
+	}
+	value, deliver := s.prepareEvent(ctx, initial, p)
+	if !deliver {
+		return true
+	}
+	// Batched subscriptions deliver lists, so the snapshot is a batch of its own
+	if event, ok := value.(T); ok && s.batchWindow > 0 {
+		value = []T{event}
+	}
+	return s.sendEvent(ctx, output, value, policy, metrics)
+}
+
+// forwardBatches collects events for the batch window and delivers them as []T.
+// The window starts with the first event of a batch; pending events are flushed
+// when the event channel closes. Transform errors flush the pending batch and are
+// delivered on their own.
+func (s *SubscriptionResolver[T]) forwardBatches(ctx context.Context, p ResolveParams, events <-chan *T, output chan interface{}, policy OverflowPolicy, metrics SubscriptionMetrics) {
+	var batch []T
+	var timer *time.Timer
+	var flushC <-chan time.Time
+
+	flush := func() bool {
+		if timer != nil {
+			timer.Stop()
+			timer, flushC = nil, nil
+		}
+		if len(batch) == 0 {
+			return true
+		}
+		pending := batch
+		batch = nil
+		return s.sendEvent(ctx, output, pending, policy, metrics)
+	}
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				flush()
+				return
+			}
+			value, deliver := s.prepareEvent(ctx, event, p)
+			if !deliver {
+				continue
+			}
+			if err, isErr := value.(error); isErr {
+				if !flush() || !s.sendEvent(ctx, output, err, policy, metrics) {
+					return
+				}
+				continue
+			}
+			batch = append(batch, value.(T))
+			if timer == nil {
+				timer = time.NewTimer(s.batchWindow)
+				flushC = timer.C
+			}
+		case <-flushC:
+			timer, flushC = nil, nil
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendEvent delivers an event (or an event error) to the output channel according to
+// the overflow policy. It returns false when the subscription context is done.
+func (s *SubscriptionResolver[T]) sendEvent(ctx context.Context, output chan interface{}, event interface{}, policy OverflowPolicy, metrics SubscriptionMetrics) bool {
+	delivered := func() {
+		if metrics != nil {
+			metrics.EventDelivered(s.name)
+		}
+	}
+	dropped := func() {
+		if metrics != nil {
+			metrics.EventDropped(s.name)
+		}
+	}
+
+	switch policy {
+	case OverflowDropNewest:
+		select {
+		case output <- event:
+			delivered()
+		default:
+			dropped()
+		}
+		return ctx.Err() == nil
+
+	case OverflowDropOldest:
+		for {
+			select {
+			case output <- event:
+				delivered()
+				return ctx.Err() == nil
+			default:
+			}
+			// Buffer is full: evict the oldest event (unless the client just took it)
+			select {
+			case <-output:
+				dropped()
+			default:
+			}
+		}
+	}
+
+	select {
+	case output <- event:
+		delivered()
+		return true
+	case <-ctx.Done():
+		// The client went away while the event was waiting to be sent
+		dropped()
+		return false
+	}
+}
+
 // buildResolveFn creates the resolve function that processes each event
 func (s *SubscriptionResolver[T]) buildResolveFn() graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		// The source is the event emitted from the channel
+		// Errors from the event transform are reported for this event
+		if err, ok := p.Source.(error); ok {
+			return nil, err
+		}
 		// Return it as-is (it's already the dereferenced struct)
 		return p.Source, nil
 	}
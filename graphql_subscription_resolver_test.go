@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,6 +125,79 @@ func TestSubscription_WithArgs(t *testing.T) {
 	}
 }
 
+// Test WithArgsFromStruct
+func TestSubscription_WithArgsFromStruct(t *testing.T) {
+	type ArgsEvent struct {
+		ChannelID string `json:"channelID"`
+		Author    string `json:"author"`
+	}
+	type ArgsEventFilter struct {
+		ChannelID string `graphql:"channelID,required"`
+		Author    string `graphql:"author"`
+		Limit     int    `graphql:"limit"`
+	}
+
+	sub := NewSubscription[ArgsEvent]("argsEvents").
+		WithArgsFromStruct(ArgsEventFilter{}).
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *ArgsEvent, error) {
+			filter, ok := SubscriptionArgs[ArgsEventFilter](p)
+			if !ok {
+				return nil, fmt.Errorf("arguments were not decoded")
+			}
+			ch := make(chan *ArgsEvent, filter.Limit+1)
+			for i := 0; i <= filter.Limit; i++ {
+				ch <- &ArgsEvent{ChannelID: filter.ChannelID, Author: fmt.Sprintf("user-%d", i)}
+			}
+			close(ch)
+			return ch, nil
+		}).
+		WithFilter(func(ctx context.Context, data *ArgsEvent, p ResolveParams) bool {
+			filter, _ := SubscriptionArgs[ArgsEventFilter](p)
+			return filter.Author == "" || data.Author == filter.Author
+		}).
+		BuildSubscription()
+
+	field := sub.Serve()
+	channelArg, ok := field.Args["channelID"]
+	if !ok {
+		t.Fatalf("Expected 'channelID' argument, got %v", field.Args)
+	}
+	if _, nonNull := channelArg.Type.(*graphql.NonNull); !nonNull {
+		t.Errorf("Expected channelID to be required, got %s", channelArg.Type)
+	}
+	if _, ok := field.Args["limit"]; !ok {
+		t.Error("Expected 'limit' argument")
+	}
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{sub},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Schema build error: %v", err)
+	}
+
+	results := graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { argsEvents(channelID: "general", author: "user-2", limit: 3) { channelID author } }`,
+		Context:       context.Background(),
+	})
+
+	var events []map[string]interface{}
+	for result := range results {
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if data, ok := result.Data.(map[string]interface{}); ok {
+			events = append(events, data["argsEvents"].(map[string]interface{}))
+		}
+	}
+
+	if len(events) != 1 || events[0]["channelID"] != "general" || events[0]["author"] != "user-2" {
+		t.Errorf("Expected one event from user-2 on general, got %v", events)
+	}
+}
+
 // Test WithResolver
 func TestSubscription_WithResolver(t *testing.T) {
 	type Event struct {
@@ -388,6 +462,40 @@ func TestSubscription_NoResolver_Error(t *testing.T) {
 	}
 }
 
+// Test that a missing resolver fails the schema build in debug mode
+func TestSubscription_NoResolver_BuildError(t *testing.T) {
+	type Event struct {
+		ID string `json:"id"`
+	}
+
+	params := SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{NewSubscription[Event]("events").BuildSubscription()},
+		Debug:              true,
+	}
+
+	_, err := NewSchemaBuilder(params).Build()
+	if err == nil {
+		t.Fatal("Expected a build error when resolver not configured")
+	}
+	expectedMsg := "subscription resolver not configured for events"
+	if err.Error() != expectedMsg {
+		t.Errorf("Expected error '%s', got '%s'", expectedMsg, err.Error())
+	}
+
+	// Outside debug mode the schema still builds
+	params.Debug = false
+	if _, err := NewSchemaBuilder(params).Build(); err != nil {
+		t.Errorf("Expected no build error outside debug mode, got %v", err)
+	}
+
+	// NewHTTP builds in debug mode when DEBUG is set
+	_, err = buildSchemaFromContext(&GraphContext{DEBUG: true, SchemaParams: &params})
+	if err == nil || err.Error() != expectedMsg {
+		t.Errorf("Expected error '%s' in DEBUG mode, got %v", expectedMsg, err)
+	}
+}
+
 // Test context cancellation
 func TestSubscription_ContextCancellation(t *testing.T) {
 	type Event struct {
@@ -777,4 +885,510 @@ func TestSubscription_ResolveFunction(t *testing.T) {
 	if resultEvent.ID != event.ID || resultEvent.Message != event.Message {
 		t.Errorf("Expected %+v, got %+v", event, resultEvent)
 	}
-}
\ No newline at end of file
+}
+
+// Test subscription lifecycle metrics
+func TestSubscription_WithMetrics(t *testing.T) {
+	type MetricsEvent struct {
+		ID string `json:"id"`
+	}
+
+	metrics := NewMetricsRegistry()
+	source := make(chan *MetricsEvent, 10)
+
+	sub := NewSubscription[MetricsEvent]("metricsEvents").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *MetricsEvent, error) {
+			return source, nil
+		}).
+		WithMetrics(metrics).
+		BuildSubscription()
+
+	if active := metrics.ActiveSubscriptions("metricsEvents"); active != 0 {
+		t.Fatalf("Expected 0 active subscriptions before subscribe, got %d", active)
+	}
+
+	result, err := sub.Serve().Subscribe(graphql.ResolveParams{
+		Context: context.Background(),
+	})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+
+	if active := metrics.ActiveSubscriptions("metricsEvents"); active != 1 {
+		t.Errorf("Expected 1 active subscription after subscribe, got %d", active)
+	}
+
+	outputCh, ok := result.(chan interface{})
+	if !ok {
+		t.Fatalf("Expected channel, got %T", result)
+	}
+
+	source <- &MetricsEvent{ID: "1"}
+	source <- &MetricsEvent{ID: "2"}
+	close(source)
+
+	for range outputCh {
+	}
+
+	if active := metrics.ActiveSubscriptions("metricsEvents"); active != 0 {
+		t.Errorf("Expected 0 active subscriptions after close, got %d", active)
+	}
+	if delivered := metrics.EventsDelivered("metricsEvents"); delivered != 2 {
+		t.Errorf("Expected 2 delivered events, got %d", delivered)
+	}
+	if count, _ := metrics.SubscriptionDurations("metricsEvents"); count != 1 {
+		t.Errorf("Expected 1 recorded duration, got %d", count)
+	}
+}
+
+// waitUntil polls condition until it holds, failing the test after a second
+func waitUntil(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Test that events pending when the client goes away are counted as dropped
+func TestSubscription_WithMetrics_DroppedOnCancel(t *testing.T) {
+	type DroppedEvent struct {
+		ID string `json:"id"`
+	}
+
+	metrics := NewMetricsRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Produce more events than the output buffer can hold
+	source := make(chan *DroppedEvent, 20)
+	for i := 0; i < 20; i++ {
+		source <- &DroppedEvent{ID: fmt.Sprintf("%d", i)}
+	}
+
+	sub := NewSubscription[DroppedEvent]("droppedEvents").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *DroppedEvent, error) {
+			return source, nil
+		}).
+		WithMetrics(metrics).
+		BuildSubscription()
+
+	result, err := sub.Serve().Subscribe(graphql.ResolveParams{Context: ctx})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	outputCh := result.(chan interface{})
+
+	// Wait until the buffer is full and the forwarder holds the next event, then
+	// cancel without reading
+	waitUntil(t, "the forwarder to fill the buffer", func() bool {
+		return len(outputCh) == cap(outputCh) && len(source) == 20-cap(outputCh)-1
+	})
+	cancel()
+
+	for range outputCh {
+	}
+
+	if dropped := metrics.EventsDropped("droppedEvents"); dropped != 1 {
+		t.Errorf("Expected 1 dropped event, got %d", dropped)
+	}
+	if active := metrics.ActiveSubscriptions("droppedEvents"); active != 0 {
+		t.Errorf("Expected 0 active subscriptions after cancel, got %d", active)
+	}
+}
+
+// Test that server-wide subscription defaults apply when a subscription sets no buffer
+func TestSubscription_DefaultBufferFromContext(t *testing.T) {
+	type BufferedEvent struct {
+		ID int `json:"id"`
+	}
+
+	metrics := NewMetricsRegistry()
+	source := make(chan *BufferedEvent, 10)
+	for i := 0; i < 10; i++ {
+		source <- &BufferedEvent{ID: i}
+	}
+	close(source)
+
+	sub := NewSubscription[BufferedEvent]("defaultBufferedEvents").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *BufferedEvent, error) {
+			return source, nil
+		}).
+		BuildSubscription()
+
+	ctx := WithSubscriptionDefaults(context.Background(), SubscriptionDefaults{
+		BufferSize:     3,
+		OverflowPolicy: OverflowDropOldest,
+		Metrics:        metrics,
+	})
+	result, err := sub.Serve().Subscribe(graphql.ResolveParams{Context: ctx})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	outputCh := result.(chan interface{})
+
+	if cap(outputCh) != 3 {
+		t.Errorf("Expected buffer size 3 from defaults, got %d", cap(outputCh))
+	}
+
+	// Let the forwarder drain the source before reading
+	waitUntil(t, "the oldest events to be dropped", func() bool {
+		return metrics.EventsDropped("defaultBufferedEvents") == 7
+	})
+
+	var ids []int
+	for event := range outputCh {
+		ids = append(ids, event.(BufferedEvent).ID)
+	}
+
+	if fmt.Sprint(ids) != "[7 8 9]" {
+		t.Errorf("Expected only the newest events [7 8 9], got %v", ids)
+	}
+	if dropped := metrics.EventsDropped("defaultBufferedEvents"); dropped != 7 {
+		t.Errorf("Expected 7 dropped events, got %d", dropped)
+	}
+}
+
+// Test that WithSubscriptionBuffer overrides the server-wide defaults
+func TestSubscription_WithSubscriptionBuffer(t *testing.T) {
+	type OwnBufferEvent struct {
+		ID int `json:"id"`
+	}
+
+	metrics := NewMetricsRegistry()
+	source := make(chan *OwnBufferEvent, 5)
+	for i := 0; i < 5; i++ {
+		source <- &OwnBufferEvent{ID: i}
+	}
+	close(source)
+
+	sub := NewSubscription[OwnBufferEvent]("ownBufferEvents").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *OwnBufferEvent, error) {
+			return source, nil
+		}).
+		WithSubscriptionBuffer(2, OverflowDropNewest).
+		WithMetrics(metrics).
+		BuildSubscription()
+
+	ctx := WithSubscriptionDefaults(context.Background(), SubscriptionDefaults{
+		BufferSize:     100,
+		OverflowPolicy: OverflowBlock,
+	})
+	result, err := sub.Serve().Subscribe(graphql.ResolveParams{Context: ctx})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	outputCh := result.(chan interface{})
+
+	if cap(outputCh) != 2 {
+		t.Errorf("Expected subscription buffer size 2, got %d", cap(outputCh))
+	}
+
+	waitUntil(t, "the newest events to be dropped", func() bool {
+		return metrics.EventsDropped("ownBufferEvents") == 3
+	})
+
+	var ids []int
+	for event := range outputCh {
+		ids = append(ids, event.(OwnBufferEvent).ID)
+	}
+	if fmt.Sprint(ids) != "[0 1]" {
+		t.Errorf("Expected the oldest events [0 1], got %v", ids)
+	}
+}
+
+// Test that events are transformed before delivery and transform errors are surfaced
+func TestSubscription_WithEventTransform(t *testing.T) {
+	type TransformEvent struct {
+		ID     string `json:"id"`
+		Author string `json:"author"`
+	}
+
+	sub := NewSubscription[TransformEvent]("transformEvents").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *TransformEvent, error) {
+			ch := make(chan *TransformEvent, 3)
+			ch <- &TransformEvent{ID: "1"}
+			ch <- &TransformEvent{ID: "bad"}
+			ch <- &TransformEvent{ID: "2"}
+			close(ch)
+			return ch, nil
+		}).
+		WithEventTransform(func(ctx context.Context, data *TransformEvent) (*TransformEvent, error) {
+			if data.ID == "bad" {
+				return nil, fmt.Errorf("cannot load author for event %s", data.ID)
+			}
+			enriched := *data
+			enriched.Author = "author-" + data.ID
+			return &enriched, nil
+		}).
+		BuildSubscription()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{sub},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Schema build error: %v", err)
+	}
+
+	results := graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { transformEvents { id author } }`,
+		Context:       context.Background(),
+	})
+
+	var authors []string
+	var errs []string
+	for result := range results {
+		for _, e := range result.Errors {
+			errs = append(errs, e.Message)
+		}
+		if data, ok := result.Data.(map[string]interface{}); ok {
+			if event, ok := data["transformEvents"].(map[string]interface{}); ok {
+				authors = append(authors, event["author"].(string))
+			}
+		}
+	}
+
+	if len(authors) != 2 || authors[0] != "author-1" || authors[1] != "author-2" {
+		t.Errorf("Expected transformed authors [author-1 author-2], got %v", authors)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0], "cannot load author for event bad") {
+		t.Errorf("Expected the transform error to be surfaced, got %v", errs)
+	}
+}
+
+// Test that WithSubscriptionTopic forwards events and tears down the PubSub subscription
+func TestSubscription_WithSubscriptionTopic(t *testing.T) {
+	type TopicEvent struct {
+		ID string `json:"id"`
+	}
+
+	pubsub := NewInMemoryPubSub()
+	defer pubsub.Close()
+
+	sub := NewSubscription[TopicEvent]("topicEvents").
+		WithArgs(graphql.FieldConfigArgument{
+			"channel": &graphql.ArgumentConfig{Type: graphql.String},
+		}).
+		WithSubscriptionTopic(pubsub, func(p ResolveParams) string {
+			channel, _ := GetArgString(p, "channel")
+			return "events:" + channel
+		}).
+		BuildSubscription()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result, err := sub.Serve().Subscribe(graphql.ResolveParams{
+		Args:    map[string]interface{}{"channel": "general"},
+		Context: ctx,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	outputCh := result.(chan interface{})
+
+	if count := pubsub.SubscriberCount("events:general"); count != 1 {
+		t.Fatalf("Expected 1 subscriber, got %d", count)
+	}
+
+	if err := pubsub.Publish(context.Background(), "events:general", TopicEvent{ID: "1"}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	select {
+	case event := <-outputCh:
+		if event.(TopicEvent).ID != "1" {
+			t.Errorf("Expected event 1, got %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+
+	// Client disconnects
+	cancel()
+
+	waitUntil(t, "the subscriber count to return to 0", func() bool {
+		return pubsub.SubscriberCount("events:general") == 0
+	})
+
+	// The output channel is closed once forwarding stops
+	for range outputCh {
+	}
+}
+
+// Test that the initial value is delivered before the published events
+func TestSubscription_WithInitialValue(t *testing.T) {
+	type SnapshotEvent struct {
+		Value int `json:"value"`
+	}
+
+	pubsub := NewInMemoryPubSub()
+	defer pubsub.Close()
+
+	tests := []struct {
+		name       string
+		initialErr error
+	}{
+		{name: "snapshot"},
+		{name: "snapshot error", initialErr: fmt.Errorf("snapshot unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := NewSubscription[SnapshotEvent]("snapshotEvents").
+				WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "snapshot" }).
+				WithInitialValue(func(ctx context.Context, p ResolveParams) (*SnapshotEvent, error) {
+					if tt.initialErr != nil {
+						return nil, tt.initialErr
+					}
+					return &SnapshotEvent{Value: 1}, nil
+				}).
+				BuildSubscription()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			result, err := sub.Serve().Subscribe(graphql.ResolveParams{Context: ctx})
+			if err != nil {
+				t.Fatalf("Subscribe error: %v", err)
+			}
+			outputCh := result.(chan interface{})
+
+			// Published before the snapshot is read, still delivered after it
+			for _, value := range []int{2, 3} {
+				if err := pubsub.Publish(context.Background(), "snapshot", SnapshotEvent{Value: value}); err != nil {
+					t.Fatalf("Publish error: %v", err)
+				}
+			}
+
+			var received []interface{}
+			for len(received) < 3 {
+				select {
+				case event := <-outputCh:
+					received = append(received, event)
+				case <-time.After(time.Second):
+					t.Fatalf("Timed out waiting for events, got %v", received)
+				}
+			}
+
+			if tt.initialErr != nil {
+				if err, ok := received[0].(error); !ok || err != tt.initialErr {
+					t.Errorf("Expected the snapshot error first, got %v", received[0])
+				}
+			} else if received[0] != (SnapshotEvent{Value: 1}) {
+				t.Errorf("Expected the snapshot first, got %v", received[0])
+			}
+			if received[1] != (SnapshotEvent{Value: 2}) || received[2] != (SnapshotEvent{Value: 3}) {
+				t.Errorf("Expected the published events after the snapshot, got %v", received[1:])
+			}
+		})
+	}
+}
+
+// Test that events within the batch window are delivered together
+func TestSubscription_WithBatchWindow(t *testing.T) {
+	type BatchTick struct {
+		Seq int `json:"seq"`
+	}
+
+	source := make(chan *BatchTick)
+	sub := NewSubscription[BatchTick]("batchTicks").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *BatchTick, error) {
+			return source, nil
+		}).
+		WithBatchWindow(50 * time.Millisecond).
+		BuildSubscription()
+
+	field := sub.Serve()
+	if _, ok := field.Type.(*graphql.List); !ok {
+		t.Fatalf("Expected batched subscription type to be a list, got %v", field.Type)
+	}
+
+	result, err := field.Subscribe(graphql.ResolveParams{Context: context.Background()})
+	if err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	outputCh := result.(chan interface{})
+
+	// Flood a first burst, wait for its batch, then a second burst
+	for i := 1; i <= 5; i++ {
+		source <- &BatchTick{Seq: i}
+	}
+	var batches [][]BatchTick
+	select {
+	case batch := <-outputCh:
+		batches = append(batches, batch.([]BatchTick))
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the first batch")
+	}
+	for i := 6; i <= 8; i++ {
+		source <- &BatchTick{Seq: i}
+	}
+	close(source)
+
+	for batch := range outputCh {
+		batches = append(batches, batch.([]BatchTick))
+	}
+
+	if len(batches) != 2 || len(batches[0]) != 5 || len(batches[1]) != 3 {
+		t.Fatalf("Expected batches of 5 and 3 events, got %v", batches)
+	}
+	for i, tick := range append(batches[0], batches[1]...) {
+		if tick.Seq != i+1 {
+			t.Errorf("Expected event %d in order, got %d", i+1, tick.Seq)
+		}
+	}
+}
+
+func TestSchemaBuilder_RejectsSubscriptionFieldOutsideSubscriptionRoot(t *testing.T) {
+	type MisplacedEvent struct {
+		ID string `json:"id"`
+	}
+
+	sub := NewSubscription[MisplacedEvent]("misplacedEvent").
+		WithResolver(func(ctx context.Context, p ResolveParams) (<-chan *MisplacedEvent, error) {
+			return make(chan *MisplacedEvent), nil
+		}).
+		BuildSubscription()
+
+	tests := []struct {
+		name   string
+		params SchemaBuilderParams
+		want   string
+	}{
+		{
+			name:   "query root",
+			params: SchemaBuilderParams{QueryFields: []QueryField{sub}},
+			want:   `query field "misplacedEvent" is a subscription field; pass it in SubscriptionFields instead of QueryFields`,
+		},
+		{
+			name: "mutation root",
+			params: SchemaBuilderParams{
+				QueryFields:    []QueryField{getDefaultHelloQuery()},
+				MutationFields: []MutationField{sub},
+			},
+			want: `mutation field "misplacedEvent" is a subscription field; pass it in SubscriptionFields instead of MutationFields`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSchemaBuilder(tt.params).Build()
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	_, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{sub},
+	}).Build()
+	if err != nil {
+		t.Errorf("Expected subscription root to accept the field, got %v", err)
+	}
+}
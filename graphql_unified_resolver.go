@@ -2,8 +2,11 @@ package graph
 
 import (
 	"context"
+	"encoding"
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,8 +23,179 @@ var (
 	inputTypeRegistryMu sync.RWMutex
 )
 
+// Go types of the generated object types, and the object types of Go types whose
+// GraphQL name was already taken by a Go type with other fields. Conflicting object
+// types are kept out of typeRegistry, so a schema containing both fails to build
+// instead of one silently using the other's fields. Guarded by typeRegistryMu.
+var (
+	objectGoTypes      = make(map[*graphql.Object]reflect.Type)
+	conflictingObjects = make(map[reflect.Type]*graphql.Object)
+)
+
+// Go types of the generated input object types. Guarded by inputTypeRegistryMu.
+var inputGoTypes = make(map[*graphql.InputObject]reflect.Type)
+
+// typeScope holds the types a SchemaBuilder generates with its own settings, such as
+// StrictNullability and its scalars. They are kept out of the global registries, so
+// schemas built with different settings don't share types generated from the same
+// Go types. Object types registered with RegisterObjectType are shared by all scopes.
+// A nil scope stands for the global registries. The object types are guarded by
+// typeRegistryMu and the input types by inputTypeRegistryMu; the settings don't change.
+type typeScope struct {
+	strictNullability bool
+	scalarsByType     map[reflect.Type]*graphql.Scalar
+	scalarsByName     map[string]*graphql.Scalar
+
+	objects     map[string]*graphql.Object
+	conflicting map[reflect.Type]*graphql.Object
+	inputs      map[string]*graphql.InputObject
+}
+
+// newTypeScope returns an empty scope for the types of one schema. Fields select the
+// scalars by tag, and fields of the Go types of scalarTypes use their scalar.
+func newTypeScope(strictNullability bool, scalars []*graphql.Scalar, scalarTypes map[reflect.Type]*graphql.Scalar) *typeScope {
+	scope := &typeScope{
+		strictNullability: strictNullability,
+		scalarsByType:     make(map[reflect.Type]*graphql.Scalar),
+		scalarsByName:     make(map[string]*graphql.Scalar),
+		objects:           make(map[string]*graphql.Object),
+		conflicting:       make(map[reflect.Type]*graphql.Object),
+		inputs:            make(map[string]*graphql.InputObject),
+	}
+	for _, scalar := range scalars {
+		if scalar != nil {
+			scope.scalarsByName[strings.ToLower(scalar.Name())] = scalar
+		}
+	}
+	for t, scalar := range scalarTypes {
+		if scalar != nil {
+			scope.scalarsByType[t] = scalar
+			scope.scalarsByName[strings.ToLower(scalar.Name())] = scalar
+		}
+	}
+	return scope
+}
+
+// strict reports whether output nullability is derived from Go pointer types
+func (s *typeScope) strict() bool {
+	return s != nil && s.strictNullability
+}
+
+// scalarByType returns the scalar of the scope mapped to Go type t
+func (s *typeScope) scalarByType(t reflect.Type) (*graphql.Scalar, bool) {
+	if s == nil {
+		return nil, false
+	}
+	scalar, ok := s.scalarsByType[t]
+	return scalar, ok
+}
+
+// scalarByName returns the scalar of the scope named name, ignoring case
+func (s *typeScope) scalarByName(name string) (*graphql.Scalar, bool) {
+	if s == nil {
+		return nil, false
+	}
+	scalar, ok := s.scalarsByName[strings.ToLower(name)]
+	return scalar, ok
+}
+
+// inputTypes returns the input object types generated in the scope. The caller must
+// hold inputTypeRegistryMu.
+func (s *typeScope) inputTypes() map[string]*graphql.InputObject {
+	if s == nil {
+		return inputTypeRegistry
+	}
+	return s.inputs
+}
+
+// lookupObjectType returns the object type generated for Go type t under name in
+// scope, if any. The type registered under name is reused when it was generated from
+// t or from a Go type with the same fields, or registered with RegisterObjectType.
+// Otherwise conflict is true and t needs an object type of its own. The caller must
+// hold typeRegistryMu.
+func lookupObjectType(scope *typeScope, name string, t reflect.Type) (existing *graphql.Object, conflict bool) {
+	objects, conflicting := typeRegistry, conflictingObjects
+	if scope != nil {
+		if registered, exists := typeRegistry[name]; exists {
+			if _, generated := objectGoTypes[registered]; !generated {
+				return registered, false
+			}
+		}
+		objects, conflicting = scope.objects, scope.conflicting
+	}
+
+	registered, exists := objects[name]
+	if !exists {
+		return nil, false
+	}
+	t = derefType(t)
+	source, generated := objectGoTypes[registered]
+	if !generated || t == nil || source == t || sameStructFields(source, t) {
+		return registered, false
+	}
+	return conflicting[t], true
+}
+
+// storeObjectType records the object type generated for Go type t under name in
+// scope. The caller must hold typeRegistryMu for writing.
+func storeObjectType(scope *typeScope, name string, t reflect.Type, object *graphql.Object, conflict bool) {
+	objects, conflicting := typeRegistry, conflictingObjects
+	if scope != nil {
+		objects, conflicting = scope.objects, scope.conflicting
+	}
+	t = derefType(t)
+	if conflict {
+		conflicting[t] = object
+	} else {
+		objects[name] = object
+	}
+	if t != nil {
+		objectGoTypes[object] = t
+	}
+}
+
+// scopedObjectType returns the object type registered under name in scope, creating
+// it with typeFactory like RegisterObjectType. Without a scope it is RegisterObjectType.
+func scopedObjectType(scope *typeScope, name string, typeFactory func() *graphql.Object) *graphql.Object {
+	if scope == nil {
+		return RegisterObjectType(name, typeFactory)
+	}
+	typeRegistryMu.Lock()
+	defer typeRegistryMu.Unlock()
+	if existingType, exists := scope.objects[name]; exists {
+		return existingType
+	}
+	newType := typeFactory()
+	scope.objects[name] = newType
+	return newType
+}
+
+// sameStructFields reports whether two struct types have the same fields, with the
+// same names and Go types
+func sameStructFields(a, b reflect.Type) bool {
+	if a.Kind() != reflect.Struct || b.Kind() != reflect.Struct || a.NumField() != b.NumField() {
+		return false
+	}
+	for i := 0; i < a.NumField(); i++ {
+		fieldA, fieldB := a.Field(i), b.Field(i)
+		if fieldA.Name != fieldB.Name || fieldA.Type != fieldB.Type || getFieldName(fieldA) != getFieldName(fieldB) {
+			return false
+		}
+	}
+	return true
+}
+
+// derefType strips the pointers of t
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
 // RegisterObjectType registers a GraphQL object type in the global registry
 // Returns existing type if already registered, otherwise creates and registers new type
+// Struct types generated later under the same name use the registered type
 func RegisterObjectType(name string, typeFactory func() *graphql.Object) *graphql.Object {
 	typeRegistryMu.RLock()
 	if existingType, exists := typeRegistry[name]; exists {
@@ -77,6 +251,7 @@ type UnifiedResolver[T any] struct {
 	isList                 bool
 	isListManuallyAssigned bool
 	isPaginated            bool
+	isConnection           bool
 	isMutation             bool
 	fieldOverrides         map[string]graphql.FieldResolveFn
 	fieldMiddleware        map[string][]FieldMiddleware
@@ -86,7 +261,21 @@ type UnifiedResolver[T any] struct {
 	nullableInput          bool
 	inputName              string
 	resolverMiddlewares    []FieldMiddleware // Middleware stack applied to the main resolver
-}
+	resolverTimeout        time.Duration     // Deadline for a single main resolver invocation
+	fieldCaches            map[string]*FieldCache
+	argPreprocessors       []ArgPreprocessor
+	stringSanitization     *StringSanitization
+	outputType             graphql.Output // Assembled on the first Serve call
+	fieldRenames           map[string]string
+	excludedFields         map[string]bool
+	argsType               reflect.Type
+	typedArgs              graphql.FieldConfigArgument                        // Arguments generated from Go types
+	typedArgsInScope       func(scope *typeScope) graphql.FieldConfigArgument // Generates typedArgs in a schema's scope
+}
+
+// ArgPreprocessor transforms the raw GraphQL arguments before they reach middleware
+// and the resolver (and before typed resolvers map them to structs)
+type ArgPreprocessor func(args map[string]interface{}) map[string]interface{}
 
 // FieldMiddleware wraps a field resolver with additional functionality (auth, logging, caching, etc.)
 type FieldMiddleware func(next FieldResolveFn) FieldResolveFn
@@ -461,6 +650,9 @@ func NewResolver[T any](name string) *UnifiedResolver[T] {
 		fieldOverrides:  make(map[string]graphql.FieldResolveFn),
 		fieldMiddleware: make(map[string][]FieldMiddleware),
 		customFields:    make(graphql.Fields),
+		fieldCaches:     make(map[string]*FieldCache),
+		fieldRenames:    make(map[string]string),
+		excludedFields:  make(map[string]bool),
 	}
 
 	// Auto-detect type characteristics
@@ -488,12 +680,43 @@ func NewResolver[T any](name string) *UnifiedResolver[T] {
 func (r *UnifiedResolver[T]) AsList() *UnifiedResolver[T] {
 	r.isList = true
 	r.isListManuallyAssigned = true
+	r.outputType = nil
 	return r
 }
 
 func (r *UnifiedResolver[T]) AsPaginated() *UnifiedResolver[T] {
 	r.isPaginated = true
+	r.isConnection = false
 	r.isList = false // Paginated overrides list
+	r.outputType = nil
+	return r
+}
+
+// AsConnection configures the field as a Relay cursor connection of T, returning a
+// TConnection type with edges { node cursor }, pageInfo and totalCount, and taking the
+// first/after/last/before arguments. Use WithConnectionResolver to resolve it, and
+// NewConnectionFromSlice to build the connection from an ordered result set.
+// AsPaginated is unaffected and keeps its offset-based items/totalCount/pageInfo shape.
+//
+// Example:
+//
+//	NewResolver[User]("users").
+//	    AsConnection().
+//	    WithConnectionResolver(func(p graph.ResolveParams, args graph.PaginationArgs) (*graph.CursorConnection[User], error) {
+//	        users, err := userService.List(p.Context)
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return graph.NewConnectionFromSlice(users, args, func(u User) string {
+//	            return strconv.Itoa(u.ID)
+//	        })
+//	    }).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) AsConnection() *UnifiedResolver[T] {
+	r.isConnection = true
+	r.isPaginated = false
+	r.isList = false
+	r.outputType = nil
 	return r
 }
 
@@ -529,25 +752,36 @@ func (r *UnifiedResolver[T]) WithInputObject(inputType interface{}) *UnifiedReso
 		fieldName = r.inputName
 	}
 
-	inputGraphQLType := r.generateInputObject(inputType, inputName)
-	if r.nullableInput {
-		r.args = graphql.FieldConfigArgument{
-			fieldName: &graphql.ArgumentConfig{
-				Type:        inputGraphQLType,
-				Description: "Input data",
-			},
+	nullable := r.nullableInput
+	inputArgs := func(scope *typeScope) graphql.FieldConfigArgument {
+		inputGraphQLType := r.generateInputObject(scope, inputType, inputName)
+		if nullable {
+			return graphql.FieldConfigArgument{
+				fieldName: &graphql.ArgumentConfig{
+					Type:        inputGraphQLType,
+					Description: "Input data",
+				},
+			}
 		}
-	} else {
-		r.args = graphql.FieldConfigArgument{
+		return graphql.FieldConfigArgument{
 			fieldName: &graphql.ArgumentConfig{
 				Type:        graphql.NewNonNull(inputGraphQLType),
 				Description: "Input data",
 			},
 		}
 	}
+	r.setTypedArgs(inputArgs)
 	return r
 }
 
+// setTypedArgs sets the arguments generated from Go types by generate, which
+// generates them again for schemas with their own scope
+func (r *UnifiedResolver[T]) setTypedArgs(generate func(scope *typeScope) graphql.FieldConfigArgument) {
+	r.args = generate(nil)
+	r.typedArgs = r.args
+	r.typedArgsInScope = generate
+}
+
 // Basic Configuration
 func (r *UnifiedResolver[T]) WithDescription(desc string) *UnifiedResolver[T] {
 	r.description = desc
@@ -561,17 +795,24 @@ func (r *UnifiedResolver[T]) WithArgs(args graphql.FieldConfigArgument) *Unified
 
 func (r *UnifiedResolver[T]) WithArgsFromStruct(structType interface{}) *UnifiedResolver[T] {
 	t := reflect.TypeOf(structType)
-	r.args = generateArgsFromType(t)
+	r.setTypedArgs(func(scope *typeScope) graphql.FieldConfigArgument {
+		return generateArgsFromTypeWithContext(scope, t, "")
+	})
+	r.argsType = t
+	for r.argsType != nil && r.argsType.Kind() == reflect.Ptr {
+		r.argsType = r.argsType.Elem()
+	}
 	return r
 }
 
 // generateArgsFromType creates GraphQL arguments from a struct type
 func generateArgsFromType(t reflect.Type) graphql.FieldConfigArgument {
-	return generateArgsFromTypeWithContext(t, "")
+	return generateArgsFromTypeWithContext(nil, t, "")
 }
 
-// generateArgsFromTypeWithContext creates GraphQL arguments from a struct type with parent context
-func generateArgsFromTypeWithContext(t reflect.Type, parentTypeName string) graphql.FieldConfigArgument {
+// generateArgsFromTypeWithContext creates GraphQL arguments from a struct type with parent
+// context, generating their input types in scope
+func generateArgsFromTypeWithContext(scope *typeScope, t reflect.Type, parentTypeName string) graphql.FieldConfigArgument {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -586,7 +827,7 @@ func generateArgsFromTypeWithContext(t reflect.Type, parentTypeName string) grap
 	}
 
 	args := graphql.FieldConfigArgument{}
-	gen := NewFieldGenerator[any]()
+	gen := newScopedFieldGenerator[any](scope)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -605,7 +846,7 @@ func generateArgsFromTypeWithContext(t reflect.Type, parentTypeName string) grap
 			continue
 		}
 
-		description := field.Tag.Get("description")
+		description := inputDescription(field)
 		defaultValue := field.Tag.Get("default")
 
 		argConfig := &graphql.ArgumentConfig{
@@ -614,7 +855,7 @@ func generateArgsFromTypeWithContext(t reflect.Type, parentTypeName string) grap
 		}
 
 		if defaultValue != "" {
-			argConfig.DefaultValue = defaultValue
+			argConfig.DefaultValue = tagDefaultValue(defaultValue, graphqlType)
 		}
 
 		args[fieldName] = argConfig
@@ -719,6 +960,39 @@ func (r *UnifiedResolver[T]) WithResolver(resolver func(p ResolveParams) (*T, er
 	return r
 }
 
+// WithFindResolver sets a resolver for lookups where "not found" is an expected outcome.
+// The bool result reports whether the item exists: when it is false the field resolves
+// to a clean null without an error, and when an error is returned it is surfaced to the
+// client as usual. This removes the ambiguity of returning (nil, nil) from WithResolver.
+//
+// Example usage:
+//
+//	NewResolver[User]("user").
+//		WithArgs(graphql.FieldConfigArgument{
+//			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+//		}).
+//		WithFindResolver(func(p graph.ResolveParams) (*User, bool, error) {
+//			id, _ := GetArgInt(p, "id")
+//			user, err := userService.FindByID(id)
+//			if errors.Is(err, sql.ErrNoRows) {
+//				return nil, false, nil
+//			}
+//			return user, err == nil, err
+//		}).BuildQuery()
+func (r *UnifiedResolver[T]) WithFindResolver(resolver func(p ResolveParams) (*T, bool, error)) *UnifiedResolver[T] {
+	r.resolver = func(p graphql.ResolveParams) (interface{}, error) {
+		result, found, err := resolver(ResolveParams(p))
+		if err != nil {
+			return nil, err
+		}
+		if !found || result == nil {
+			return nil, nil
+		}
+		return result, nil
+	}
+	return r
+}
+
 // WithMiddleware adds middleware to the main resolver.
 // Middleware functions are applied in the order they are added (first added = outermost layer).
 // This is the foundation for all resolver-level middleware (auth, logging, caching, etc.).
@@ -737,12 +1011,114 @@ func (r *UnifiedResolver[T]) WithMiddleware(middleware FieldMiddleware) *Unified
 	return r
 }
 
+// WithResolverTimeout limits how long the main resolver may run.
+// The resolver receives a context (p.Context) that is cancelled after d, and if it has not
+// returned by then the field fails with an error wrapping context.DeadlineExceeded.
+// Only the main resolver invocation is bounded: nested field resolvers are not, and
+// the deadline is separate from any timeout on the whole request. Each attempt made by
+// RetryMiddleware gets its own deadline.
+//
+// Example usage:
+//
+//	NewResolver[Weather]("weather").
+//		WithResolverTimeout(2 * time.Second).
+//		WithResolver(func(p ResolveParams) (*Weather, error) {
+//			return weatherAPI.Fetch(p.Context, p.Args["city"].(string))
+//		}).
+//		BuildQuery()
+func (r *UnifiedResolver[T]) WithResolverTimeout(d time.Duration) *UnifiedResolver[T] {
+	r.resolverTimeout = d
+	return r
+}
+
+// WithArgPreprocessor registers a function that normalizes the raw arguments before
+// they are used: trimming or lowercasing values, or mapping legacy argument names to
+// their replacements. Preprocessors run in the order they are added, before any
+// middleware. Each receives a copy of the arguments and returns the arguments to use.
+//
+// Example usage:
+//
+//	NewResolver[User]("user").
+//		WithArgs(graphql.FieldConfigArgument{
+//			"id":     &graphql.ArgumentConfig{Type: graphql.Int},
+//			"userId": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Deprecated: use id"},
+//		}).
+//		WithArgPreprocessor(func(args map[string]interface{}) map[string]interface{} {
+//			if legacy, ok := args["userId"]; ok {
+//				args["id"] = legacy
+//				delete(args, "userId")
+//			}
+//			return args
+//		}).
+//		WithResolver(...)
+func (r *UnifiedResolver[T]) WithArgPreprocessor(fn ArgPreprocessor) *UnifiedResolver[T] {
+	r.argPreprocessors = append(r.argPreprocessors, fn)
+	return r
+}
+
+// preprocessArgs runs the argument preprocessors before calling resolver
+func preprocessArgs(preprocessors []ArgPreprocessor, resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		args := make(map[string]interface{}, len(p.Args))
+		for key, value := range p.Args {
+			args[key] = value
+		}
+		for _, preprocess := range preprocessors {
+			args = preprocess(args)
+		}
+		p.Args = args
+		return resolver(p)
+	}
+}
+
+// withTimeout bounds a resolver invocation by timeout, returning early when the
+// resolver ignores the cancelled context
+func withTimeout(name string, timeout time.Duration, resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	type outcome struct {
+		result interface{}
+		err    error
+	}
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		parent := p.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		p.Context = ctx
+
+		// Buffered so the resolver goroutine can finish after a timeout
+		done := make(chan outcome, 1)
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					done <- outcome{err: fmt.Errorf("resolver '%s' panicked: %v", name, rec)}
+				}
+			}()
+			result, err := resolver(p)
+			done <- outcome{result: result, err: err}
+		}()
+
+		select {
+		case o := <-done:
+			return o.result, o.err
+		case <-ctx.Done():
+			if parent.Err() != nil {
+				return nil, parent.Err()
+			}
+			return nil, fmt.Errorf("resolver '%s' timed out after %s: %w", name, timeout, context.DeadlineExceeded)
+		}
+	}
+}
+
 // TypedArgsResolver provides type-safe argument handling
 type TypedArgsResolver[T any, A any] struct {
-	base     *UnifiedResolver[T]
-	argName  []string
-	argType  reflect.Type
-	isScalar bool
+	base          *UnifiedResolver[T]
+	argName       []string
+	argType       reflect.Type
+	isScalar      bool
+	argsValidator func(args A) error
 }
 
 // NewTypedResolver creates a resolver with type-safe arguments
@@ -783,7 +1159,9 @@ func NewArgsResolver[T any, A any](name string, argName ...string) *TypedArgsRes
 		// Struct type - auto-generate args from struct fields
 		// Pass the parent type name for anonymous struct naming
 		parentTypeName := argsType.Name()
-		base.args = generateArgsFromTypeWithContext(argsType, parentTypeName)
+		base.setTypedArgs(func(scope *typeScope) graphql.FieldConfigArgument {
+			return generateArgsFromTypeWithContext(scope, argsType, parentTypeName)
+		})
 	} else {
 		// Primitive type (string, int, bool, etc.) - create single argument
 		fieldName := "input"
@@ -815,13 +1193,18 @@ func getPrimitiveGraphQLType(t reflect.Type) graphql.Input {
 	if t == nil {
 		return nil
 	}
+	if enumType := lookupEnumByType(t); enumType != nil {
+		return enumType
+	}
 
 	switch t.Kind() {
 	case reflect.String:
 		return graphql.String
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int64, reflect.Uint64:
+		return Int64
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		return graphql.Int
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		return graphql.Int
 	case reflect.Float32, reflect.Float64:
 		return graphql.Float
@@ -876,12 +1259,46 @@ func (r *TypedArgsResolver[T, A]) WithResolver(resolver func(ctx context.Context
 			}
 		}
 
+		// Run cross-field validation on the decoded arguments
+		if r.argsValidator != nil {
+			if err := r.argsValidator(args); err != nil {
+				return nil, err
+			}
+		}
+
 		// Call the typed resolver
 		return resolver(ctx, ResolveParams(p), args)
 	}
 	return r
 }
 
+// WithArgsValidation sets a validation function that runs after the arguments are
+// decoded into A and before the resolver is called. Use it for constraints that
+// span multiple fields, which cannot be expressed with struct tags alone.
+// A non-nil error is returned to the client and the resolver is not called.
+//
+// Example usage:
+//
+//	NewArgsResolver[[]Event, ListEventsArgs]("events").
+//		WithArgsValidation(func(args ListEventsArgs) error {
+//			if !args.StartDate.Before(args.EndDate) {
+//				return errors.New("startDate must be before endDate")
+//			}
+//			return nil
+//		}).
+//		WithResolver(...)
+func (r *TypedArgsResolver[T, A]) WithArgsValidation(fn func(args A) error) *TypedArgsResolver[T, A] {
+	r.argsValidator = fn
+	return r
+}
+
+// WithArgPreprocessor registers a function that normalizes the raw arguments before
+// they are decoded into A. See UnifiedResolver.WithArgPreprocessor.
+func (r *TypedArgsResolver[T, A]) WithArgPreprocessor(fn ArgPreprocessor) *TypedArgsResolver[T, A] {
+	r.base.WithArgPreprocessor(fn)
+	return r
+}
+
 // BuildQuery builds and returns a QueryField
 func (r *TypedArgsResolver[T, A]) BuildQuery() QueryField {
 	return r.base.BuildQuery()
@@ -952,7 +1369,11 @@ func (r *UnifiedResolver[T]) wrapTypedResolver(typedResolver interface{}) graphq
 			if r.inputName != "" {
 				inputFieldName = r.inputName
 			}
-			if inputData, exists := p.Args[inputFieldName]; exists && i == 0 {
+			if decoded, ok := decodedInputValue(p.Context, paramType); ok && i == 0 {
+				// First parameter already decoded by DecodeInput
+				args[i] = decoded
+				continue
+			} else if inputData, exists := p.Args[inputFieldName]; exists && i == 0 {
 				// First parameter from input argument (mutations)
 				err = mapstructure.Decode(inputData, paramInterface)
 			} else if i == 0 && numIn == 1 {
@@ -1034,11 +1455,120 @@ func (r *UnifiedResolver[T]) WithFieldResolvers(overrides map[string]graphql.Fie
 	return r
 }
 
+// WithFieldResolverT overrides the resolver of a field with a typed function receiving
+// the parent object as T, whether the source is a T, a *T or a map. Unlike
+// WithFieldResolver, whose errors fall back to the struct field, its errors are
+// returned to the client, and a GraphError keeps its code in the error's extensions.
+//
+// Example:
+//
+//	graph.WithFieldResolverT(NewResolver[User]("user"), "manager",
+//	    func(user User, p graph.ResolveParams) (*User, error) {
+//	        manager, ok := users[user.ManagerID]
+//	        if !ok {
+//	            return nil, graph.NewGraphError("NOT_FOUND", "manager not found")
+//	        }
+//	        return manager, nil
+//	    })
+func WithFieldResolverT[T any, R any](r *UnifiedResolver[T], fieldName string, resolver func(parent T, p ResolveParams) (R, error)) *UnifiedResolver[T] {
+	return r.WithFieldResolver(fieldName, func(p graphql.ResolveParams) (interface{}, error) {
+		parent, err := parentAs[T](p.Source)
+		if err != nil {
+			return nil, &typedOverrideError{err: fmt.Errorf("field %s: %w", fieldName, err)}
+		}
+		result, err := resolver(parent, ResolveParams(p))
+		if err != nil {
+			return nil, &typedOverrideError{err: err}
+		}
+		return result, nil
+	})
+}
+
+// typedOverrideError marks an error of a WithFieldResolverT override, which is
+// returned to the client instead of falling back to the struct field
+type typedOverrideError struct {
+	err error
+}
+
+func (e *typedOverrideError) Error() string {
+	return e.err.Error()
+}
+
+func (e *typedOverrideError) Unwrap() error {
+	return e.err
+}
+
+// reportedOverrideError returns the error of a WithFieldResolverT override, which is
+// reported instead of falling back to the struct field
+func reportedOverrideError(err error) (error, bool) {
+	var typedErr *typedOverrideError
+	if errors.As(err, &typedErr) {
+		return typedErr.err, true
+	}
+	return nil, false
+}
+
+// parentAs converts the source of a field to T
+func parentAs[T any](source interface{}) (T, error) {
+	var parent T
+	switch value := source.(type) {
+	case T:
+		return value, nil
+	case *T:
+		if value != nil {
+			return *value, nil
+		}
+		return parent, nil
+	case map[string]interface{}:
+		err := mapArgsToStruct(value, &parent)
+		return parent, err
+	}
+	return parent, fmt.Errorf("unexpected parent type %T", source)
+}
+
 func (r *UnifiedResolver[T]) WithFieldMiddleware(fieldName string, middleware FieldMiddleware) *UnifiedResolver[T] {
 	r.fieldMiddleware[fieldName] = append(r.fieldMiddleware[fieldName], middleware)
 	return r
 }
 
+// WithExcludeFields drops fields from the generated object type, the input object
+// and the arguments generated from structs, for types whose tags you can't change
+// (e.g. third-party structs). Fields are identified by Go field name or generated name.
+// Input object types are shared by name, so exclusions apply to every use of them.
+//
+// Example:
+//
+//	NewResolver[thirdparty.Account]("account").
+//	    WithExcludeFields("password", "InternalFlag").
+//	    WithResolver(getAccount).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) WithExcludeFields(fields ...string) *UnifiedResolver[T] {
+	for _, field := range fields {
+		r.excludedFields[field] = true
+	}
+	return r
+}
+
+// WithFieldRename exposes a field under a different GraphQL name than its json tag,
+// e.g. when the json tags describe another wire format. The field is identified by
+// its Go field name or its generated name; it still resolves from the same struct field.
+// Field resolvers and middleware refer to the renamed field by its new name.
+//
+// Example:
+//
+//	type User struct {
+//	    FullName string `json:"full_name"`
+//	}
+//
+//	NewResolver[User]("user").
+//	    WithFieldRename("FullName", "displayName").
+//	    WithResolver(getUser).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) WithFieldRename(field, graphqlName string) *UnifiedResolver[T] {
+	r.fieldRenames[field] = graphqlName
+	return r
+}
+
 // WithPermission adds permission middleware to the resolver (similar to Python @permission_classes decorator)
 // This is now just a convenience wrapper around WithMiddleware for backwards compatibility
 func (r *UnifiedResolver[T]) WithPermission(middleware FieldMiddleware) *UnifiedResolver[T] {
@@ -1065,10 +1595,18 @@ func (r *UnifiedResolver[T]) WithLazyField(fieldName string, loader func(interfa
 }
 
 func (r *UnifiedResolver[T]) WithCachedField(fieldName string, cacheKeyFunc func(graphql.ResolveParams) string, resolver graphql.FieldResolveFn) *UnifiedResolver[T] {
-	r.fieldOverrides[fieldName] = CachedFieldResolver(cacheKeyFunc, resolver)
+	cache := NewFieldCache()
+	r.fieldCaches[fieldName] = cache
+	r.fieldOverrides[fieldName] = cache.Resolver(cacheKeyFunc, resolver)
 	return r
 }
 
+// FieldCache returns the cache used by WithCachedField for fieldName, or nil if the
+// field is not cached. Invalidate it from mutations to bust cached reads.
+func (r *UnifiedResolver[T]) FieldCache(fieldName string) *FieldCache {
+	return r.fieldCaches[fieldName]
+}
+
 func (r *UnifiedResolver[T]) WithAsyncField(fieldName string, resolver graphql.FieldResolveFn) *UnifiedResolver[T] {
 	r.fieldOverrides[fieldName] = AsyncFieldResolver(resolver)
 	return r
@@ -1097,10 +1635,147 @@ func (r *UnifiedResolver[T]) Name() string {
 }
 
 func (r *UnifiedResolver[T]) Serve() *graphql.Field {
+	return r.serve(nil)
+}
+
+// serveInScope returns the field with its output type generated in the scope of a
+// schema builder instead of the global registries
+func (r *UnifiedResolver[T]) serveInScope(scope *typeScope) *graphql.Field {
+	return r.serve(scope)
+}
+
+// serve assembles the field, generating its types in scope
+func (r *UnifiedResolver[T]) serve(scope *typeScope) *graphql.Field {
+	// Maps have no GraphQL equivalent and are exposed as lists of key/value entries
+	var instance T
+	mapType := reflect.TypeOf(instance)
+	if mapType != nil && mapType.Kind() == reflect.Slice {
+		mapType = mapType.Elem()
+	}
+	isMapResult := !r.isPaginated && isStringKeyedMap(mapType)
+
+	// The output type, including field overrides, is assembled on the first call only.
+	// Scoped output types belong to one schema and are assembled for each of them.
+	outputType := r.outputType
+	if scope != nil {
+		outputType = r.buildOutputType(scope, isMapResult, mapType)
+	} else if outputType == nil {
+		outputType = r.buildOutputType(nil, isMapResult, mapType)
+		r.outputType = outputType
+	}
+
+	// Apply middleware stack to the resolver
+	resolver := r.resolver
+
+	// Bound the resolver itself, so middleware runs outside the deadline
+	if r.resolverTimeout > 0 && resolver != nil {
+		resolver = withTimeout(r.name, r.resolverTimeout, resolver)
+	}
+
+	// Convert and apply middlewares if any exist
+	if len(r.resolverMiddlewares) > 0 {
+		// Wrap graphql.FieldResolveFn to our FieldResolveFn
+		wrappedResolver := wrapGraphQLResolver(resolver)
+
+		// Apply resolver middlewares in order (first added = outermost layer)
+		wrappedResolver = applyMiddlewares(wrappedResolver, r.resolverMiddlewares)
+
+		// Convert back to graphql.FieldResolveFn
+		resolver = unwrapGraphQLResolver(wrappedResolver)
+	}
+
+	// Normalize raw arguments before middleware and the resolver see them
+	if len(r.argPreprocessors) > 0 && resolver != nil {
+		resolver = preprocessArgs(r.argPreprocessors, resolver)
+	}
+
+	// Sanitize string arguments before the preprocessors
+	if resolver != nil {
+		resolver = sanitizeArgs(r.stringSanitization, resolver)
+	}
+
+	// Attach a stack trace to errors in DEBUG mode with IncludeStackTraceInDebug
+	if resolver != nil {
+		resolver = withErrorStacks(resolver)
+	}
+
+	// Convert map results to entries
+	if isMapResult && resolver != nil {
+		mapResolver := resolver
+		resolver = func(p graphql.ResolveParams) (interface{}, error) {
+			result, err := mapResolver(p)
+			if err != nil {
+				return nil, err
+			}
+			return mapToEntries(result)
+		}
+	}
+
+	// Observe contexts derived by ContextMiddleware on enclosing fields
+	if resolver != nil {
+		resolver = withFieldContext(resolver)
+	}
+
+	return &graphql.Field{
+		Type:        outputType,
+		Description: r.description,
+		Args:        r.servedArgs(scope),
+		Resolve:     resolver,
+	}
+}
+
+// servedArgs returns the arguments of the field without excluded fields
+// and with the connection arguments of AsConnection
+func (r *UnifiedResolver[T]) servedArgs(scope *typeScope) graphql.FieldConfigArgument {
+	served := r.args
+	if scope != nil && r.typedArgsInScope != nil {
+		served = r.argsInScope(scope)
+	}
+	if r.isConnection {
+		served = withConnectionArgs(served)
+	}
+	if len(r.excludedFields) == 0 {
+		return served
+	}
+	excluded := NewFieldGenerator[any]().excludedNames(r.argsType, r.excludedFields)
+	args := make(graphql.FieldConfigArgument, len(served))
+	for name, arg := range served {
+		if !excluded[name] {
+			args[name] = arg
+		}
+	}
+	return args
+}
+
+// argsInScope returns the arguments with those generated from Go types generated
+// again in scope. Arguments replaced since they were generated are kept.
+func (r *UnifiedResolver[T]) argsInScope(scope *typeScope) graphql.FieldConfigArgument {
+	scoped := r.typedArgsInScope(scope)
+	args := make(graphql.FieldConfigArgument, len(r.args))
+	for name, arg := range r.args {
+		if arg == r.typedArgs[name] && scoped[name] != nil {
+			arg = scoped[name]
+		}
+		args[name] = arg
+	}
+	return args
+}
+
+// buildOutputType assembles the GraphQL output type returned by the resolver, with the
+// object types it uses generated in scope
+func (r *UnifiedResolver[T]) buildOutputType(scope *typeScope, isMapResult bool, mapType reflect.Type) graphql.Output {
 	var outputType graphql.Output
+	var instance T
 
-	if r.isPaginated {
-		outputType = r.generatePaginatedType()
+	if isMapResult {
+		outputType = graphql.NewList(mapEntryType(scope, mapType.Elem()))
+		if t := reflect.TypeOf(instance); t.Kind() == reflect.Slice {
+			outputType = graphql.NewList(outputType)
+		}
+	} else if r.isConnection {
+		outputType = r.generateConnectionType(scope)
+	} else if r.isPaginated {
+		outputType = r.generatePaginatedType(scope)
 	} else if r.isList && r.isListManuallyAssigned {
 		// Check if the element type is a scalar
 		var instance T
@@ -1113,64 +1788,53 @@ func (r *UnifiedResolver[T]) Serve() *graphql.Field {
 		}
 
 		// Check if element type is scalar
-		elementScalarType := r.getScalarType(elementType)
+		elementScalarType := r.getScalarType(scope, elementType)
 		if elementScalarType != nil {
 			// List of scalars
 			outputType = graphql.NewList(elementScalarType)
 		} else {
 			// List of objects
-			outputType = graphql.NewList(r.generateObjectTypeWithOverrides())
+			outputType = graphql.NewList(r.generateObjectTypeWithOverrides(scope))
 		}
 	} else {
 		// Check if T is a primitive/scalar type
 		var instance T
 		t := reflect.TypeOf(instance)
-		scalarType := r.getScalarType(t)
+		scalarType := r.getScalarType(scope, t)
 
 		if scalarType != nil {
 			// Use scalar type directly for primitives
 			outputType = scalarType
 		} else {
 			// Generate object type for struct types
-			outputType = r.generateObjectTypeWithOverrides()
+			outputType = r.generateObjectTypeWithOverrides(scope)
 		}
 	}
 
-	// Apply middleware stack to the resolver
-	resolver := r.resolver
-
-	// Convert and apply middlewares if any exist
-	if len(r.resolverMiddlewares) > 0 {
-		// Wrap graphql.FieldResolveFn to our FieldResolveFn
-		wrappedResolver := wrapGraphQLResolver(resolver)
-
-		// Apply resolver middlewares in order (first added = outermost layer)
-		wrappedResolver = applyMiddlewares(wrappedResolver, r.resolverMiddlewares)
-
-		// Convert back to graphql.FieldResolveFn
-		resolver = unwrapGraphQLResolver(wrappedResolver)
-	}
-
-	return &graphql.Field{
-		Type:        outputType,
-		Description: r.description,
-		Args:        r.args,
-		Resolve:     resolver,
-	}
+	return outputType
 }
 
-// getScalarType returns the GraphQL scalar type for primitive Go types
-func (r *UnifiedResolver[T]) getScalarType(t reflect.Type) graphql.Output {
+// getScalarType returns the GraphQL scalar type for primitive Go types and the Go types
+// mapped to scalars of scope
+func (r *UnifiedResolver[T]) getScalarType(scope *typeScope, t reflect.Type) graphql.Output {
 	if t == nil {
 		return nil
 	}
+	if scalar, ok := scope.scalarByType(t); ok {
+		return scalar
+	}
+	if enumType := lookupEnumByType(t); enumType != nil {
+		return enumType
+	}
 
 	switch t.Kind() {
 	case reflect.String:
 		return graphql.String
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int64, reflect.Uint64:
+		return Int64
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		return graphql.Int
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
 		return graphql.Int
 	case reflect.Float32, reflect.Float64:
 		return graphql.Float
@@ -1182,10 +1846,18 @@ func (r *UnifiedResolver[T]) getScalarType(t reflect.Type) graphql.Output {
 }
 
 // Internal Generation Methods
-func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
+func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides(scope *typeScope) *graphql.Object {
+	var instance T
+	typeToUse := reflect.TypeOf(instance)
+
+	// If T is a slice type, extract the element type for field generation
+	if typeToUse != nil && typeToUse.Kind() == reflect.Slice {
+		typeToUse = typeToUse.Elem()
+	}
+
 	// Check if type already exists in registry
 	typeRegistryMu.RLock()
-	if existingType, exists := typeRegistry[r.objectName]; exists {
+	if existingType, _ := lookupObjectType(scope, r.objectName, typeToUse); existingType != nil {
 		typeRegistryMu.RUnlock()
 		return existingType
 	}
@@ -1196,19 +1868,13 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 	typeRegistryMu.Lock()
 
 	// Double-check in case another goroutine created it
-	if existingType, exists := typeRegistry[r.objectName]; exists {
+	existingType, conflict := lookupObjectType(scope, r.objectName, typeToUse)
+	if existingType != nil {
 		typeRegistryMu.Unlock()
 		return existingType
 	}
 
-	gen := NewFieldGenerator[T]()
-	var instance T
-	typeToUse := reflect.TypeOf(instance)
-
-	// If T is a slice type, extract the element type for field generation
-	if typeToUse != nil && typeToUse.Kind() == reflect.Slice {
-		typeToUse = typeToUse.Elem()
-	}
+	gen := newScopedFieldGenerator[T](scope)
 
 	// Capture variables for the closure
 	capturedTypeToUse := typeToUse
@@ -1216,11 +1882,14 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 	capturedFieldOverrides := r.fieldOverrides
 	capturedFieldMiddleware := r.fieldMiddleware
 	capturedCustomFields := r.customFields
+	capturedFieldRenames := r.fieldRenames
+	capturedExcludedFields := r.excludedFields
 
 	// Create the object type with a FieldsThunk for lazy field generation
 	// This avoids deadlock by releasing the lock before fields are generated
 	newType := graphql.NewObject(graphql.ObjectConfig{
-		Name: r.objectName,
+		Name:       r.objectName,
+		Interfaces: interfacesOf(capturedTypeToUse),
 		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
 			var baseFields graphql.Fields
 
@@ -1242,6 +1911,12 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 				baseFields = gen.generateFields(capturedTypeToUse)
 			}
 
+			// Drop excluded fields, then rename fields before overrides, which use the new names
+			for name := range gen.excludedNames(capturedTypeToUse, capturedExcludedFields) {
+				delete(baseFields, name)
+			}
+			gen.renameFields(baseFields, capturedTypeToUse, capturedFieldRenames)
+
 			// Apply field resolver overrides
 			for fieldName, override := range capturedFieldOverrides {
 				if field, exists := baseFields[fieldName]; exists {
@@ -1261,6 +1936,9 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 						field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
 							result, err := finalResolve(p)
 							if err != nil {
+								if overrideErr, ok := reportedOverrideError(err); ok {
+									return nil, overrideErr
+								}
 								// Fallback to original resolver
 								return originalResolve(p)
 							}
@@ -1272,8 +1950,20 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 				}
 			}
 
+			// Let overridden fields observe contexts derived by ContextMiddleware on the parent
+			for fieldName := range capturedFieldOverrides {
+				if field, exists := baseFields[fieldName]; exists && field.Resolve != nil {
+					field.Resolve = withFieldContext(field.Resolve)
+				}
+			}
+
 			// Add custom fields
 			for fieldName, customField := range capturedCustomFields {
+				if customField.Resolve != nil {
+					field := *customField
+					field.Resolve = withFieldContext(customField.Resolve)
+					customField = &field
+				}
 				baseFields[fieldName] = customField
 			}
 
@@ -1282,14 +1972,14 @@ func (r *UnifiedResolver[T]) generateObjectTypeWithOverrides() *graphql.Object {
 	})
 
 	// Register the type
-	typeRegistry[r.objectName] = newType
+	storeObjectType(scope, r.objectName, typeToUse, newType, conflict)
 	typeRegistryMu.Unlock()
 
 	return newType
 }
 
-func (r *UnifiedResolver[T]) generatePaginatedType() *graphql.Object {
-	itemType := r.generateObjectTypeWithOverrides()
+func (r *UnifiedResolver[T]) generatePaginatedType(scope *typeScope) *graphql.Object {
+	itemType := r.generateObjectTypeWithOverrides(scope)
 
 	return graphql.NewObject(graphql.ObjectConfig{
 		Name: r.objectName + "Connection",
@@ -1325,10 +2015,10 @@ func (r *UnifiedResolver[T]) generatePaginatedType() *graphql.Object {
 	})
 }
 
-func (r *UnifiedResolver[T]) generateInputObject(inputType interface{}, name string) *graphql.InputObject {
+func (r *UnifiedResolver[T]) generateInputObject(scope *typeScope, inputType interface{}, name string) *graphql.InputObject {
 	// Check if input type already exists in registry
 	inputTypeRegistryMu.RLock()
-	if existingType, exists := inputTypeRegistry[name]; exists {
+	if existingType, exists := scope.inputTypes()[name]; exists {
 		inputTypeRegistryMu.RUnlock()
 		return existingType
 	}
@@ -1339,7 +2029,7 @@ func (r *UnifiedResolver[T]) generateInputObject(inputType interface{}, name str
 	defer inputTypeRegistryMu.Unlock()
 
 	// Double-check in case another goroutine created it
-	if existingType, exists := inputTypeRegistry[name]; exists {
+	if existingType, exists := scope.inputTypes()[name]; exists {
 		return existingType
 	}
 
@@ -1348,16 +2038,24 @@ func (r *UnifiedResolver[T]) generateInputObject(inputType interface{}, name str
 		t = t.Elem()
 	}
 
-	gen := NewFieldGenerator[any]()
-	fields := gen.generateInputFields(t)
-
+	// Generate fields lazily: nested input objects look up the registry while
+	// their fields are generated, which would deadlock while we hold the lock
+	gen := newScopedFieldGenerator[any](scope)
+	excluded := r.excludedFields
 	newInputType := graphql.NewInputObject(graphql.InputObjectConfig{
-		Name:   name,
-		Fields: fields,
+		Name: name,
+		Fields: (graphql.InputObjectConfigFieldMapThunk)(func() graphql.InputObjectConfigFieldMap {
+			fields := gen.generateInputFields(t)
+			for name := range gen.excludedNames(t, excluded) {
+				delete(fields, name)
+			}
+			return fields
+		}),
 	})
 
 	// Register the input type
-	inputTypeRegistry[name] = newInputType
+	scope.inputTypes()[name] = newInputType
+	inputGoTypes[newInputType] = t
 	return newInputType
 }
 
@@ -1412,19 +2110,168 @@ func AuthMiddleware(requiredRole string) FieldMiddleware {
 	}
 }
 
-// CacheMiddleware caches field results based on a key function
+// CacheMiddleware caches field results based on a key function.
+// Use FieldCache.Middleware instead when the cache must be invalidated.
+// The cache is safe for concurrent resolvers and is shared by all requests; include
+// the user in the key for per-user data, or use WithRequestCachedField to cache for
+// a single request.
 func CacheMiddleware(cacheKey func(ResolveParams) string) FieldMiddleware {
-	cache := make(map[string]interface{})
+	return NewFieldCache().Middleware(cacheKey)
+}
+
+// RetryMiddleware retries a resolver on transient errors.
+// The resolver is called up to attempts times. Between attempts the middleware waits for
+// backoff(attempt), where attempt starts at 1 for the first retry. Only errors for which
+// retryable returns true are retried; any other error is returned immediately.
+// A nil backoff retries without waiting and a nil retryable retries every error.
+// Waiting stops early when the request context is cancelled.
+//
+// Example:
+//
+//	NewResolver[User]("user").
+//	    WithResolver(fetchUserFromAPI).
+//	    WithMiddleware(graph.RetryMiddleware(3,
+//	        func(attempt int) time.Duration { return time.Duration(attempt) * 100 * time.Millisecond },
+//	        func(err error) bool { return errors.Is(err, ErrServiceUnavailable) },
+//	    )).
+//	    BuildQuery()
+func RetryMiddleware(attempts int, backoff func(int) time.Duration, retryable func(error) bool) FieldMiddleware {
+	if attempts < 1 {
+		attempts = 1
+	}
 	return func(next FieldResolveFn) FieldResolveFn {
 		return func(p ResolveParams) (interface{}, error) {
-			key := cacheKey(p)
-			if cached, exists := cache[key]; exists {
-				return cached, nil
+			var result interface{}
+			var err error
+			for attempt := 0; attempt < attempts; attempt++ {
+				if attempt > 0 && backoff != nil {
+					if wait := backoff(attempt); wait > 0 {
+						if p.Context == nil {
+							time.Sleep(wait)
+						} else {
+							timer := time.NewTimer(wait)
+							select {
+							case <-timer.C:
+							case <-p.Context.Done():
+								timer.Stop()
+								return nil, err
+							}
+						}
+					}
+				}
+
+				result, err = next(p)
+				if err == nil {
+					return result, nil
+				}
+				if retryable != nil && !retryable(err) {
+					return result, err
+				}
 			}
-			result, err := next(p)
-			if err == nil {
-				cache[key] = result
+			return result, err
+		}
+	}
+}
+
+// ErrCircuitOpen is returned by CircuitBreakerMiddleware while the breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerOptions configures CircuitBreakerMiddleware
+type CircuitBreakerOptions struct {
+	// FailureThreshold: Consecutive failures that open the breaker (default: 5)
+	FailureThreshold int
+
+	// Cooldown: How long the breaker stays open before a trial call is let through (default: 30s)
+	Cooldown time.Duration
+
+	// IsFailure: Reports whether an error counts as a failure (default: every error)
+	// Use it to ignore errors that don't indicate an unhealthy downstream, e.g. not found.
+	IsFailure func(error) bool
+}
+
+// circuitBreaker tracks consecutive failures of a resolver
+type circuitBreaker struct {
+	opts     CircuitBreakerOptions
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	trial    bool // a trial call is in flight after the cooldown
+}
+
+// allow reports whether a call may proceed and whether it is the trial call
+func (b *circuitBreaker) allow() (allowed, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true, false
+	}
+	if b.trial || time.Since(b.openedAt) < b.opts.Cooldown {
+		return false, false
+	}
+	b.trial = true
+	return true, true
+}
+
+// record updates the breaker with the outcome of a call
+func (b *circuitBreaker) record(failed, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if trial {
+		b.trial = false
+	}
+	if !failed {
+		b.failures = 0
+		b.open = false
+		return
+	}
+	b.failures++
+	if trial || b.failures >= b.opts.FailureThreshold {
+		b.open = true
+		b.openedAt = time.Now()
+	}
+}
+
+// CircuitBreakerMiddleware protects a failing downstream by fast-failing calls.
+// After FailureThreshold consecutive failures the breaker opens and calls return
+// ErrCircuitOpen without running the resolver. Once Cooldown has elapsed a single
+// trial call is let through: success closes the breaker, failure opens it again.
+// The breaker state is shared by all calls of the resolver the middleware wraps.
+//
+// Example:
+//
+//	NewResolver[Weather]("weather").
+//	    WithResolver(fetchWeatherFromAPI).
+//	    WithMiddleware(graph.CircuitBreakerMiddleware(graph.CircuitBreakerOptions{
+//	        FailureThreshold: 3,
+//	        Cooldown:         10 * time.Second,
+//	    })).
+//	    BuildQuery()
+func CircuitBreakerMiddleware(opts CircuitBreakerOptions) FieldMiddleware {
+	if opts.FailureThreshold < 1 {
+		opts.FailureThreshold = 5
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	breaker := &circuitBreaker{opts: opts}
+
+	return func(next FieldResolveFn) FieldResolveFn {
+		return func(p ResolveParams) (interface{}, error) {
+			allowed, trial := breaker.allow()
+			if !allowed {
+				return nil, ErrCircuitOpen
 			}
+
+			// A panicking call counts as a failure, so a panicking trial call doesn't
+			// leave the breaker waiting for it forever
+			failed := true
+			defer func() {
+				breaker.record(failed, trial)
+			}()
+
+			result, err := next(p)
+			failed = err != nil && (opts.IsFailure == nil || opts.IsFailure(err))
 			return result, err
 		}
 	}
@@ -1451,22 +2298,12 @@ func AsyncFieldResolver(resolver graphql.FieldResolveFn) graphql.FieldResolveFn
 	}
 }
 
-// CachedFieldResolver caches field results with a key function
+// CachedFieldResolver caches field results with a key function.
+// Use FieldCache.Resolver instead when the cache must be invalidated.
+// The cache is safe for concurrent resolvers and is shared by all requests; see
+// WithRequestCachedField.
 func CachedFieldResolver(cacheKey func(graphql.ResolveParams) string, resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
-	cache := make(map[string]interface{})
-
-	return func(p graphql.ResolveParams) (interface{}, error) {
-		key := cacheKey(p)
-		if cached, exists := cache[key]; exists {
-			return cached, nil
-		}
-
-		result, err := resolver(p)
-		if err == nil {
-			cache[key] = result
-		}
-		return result, err
-	}
+	return NewFieldCache().Resolver(cacheKey, resolver)
 }
 
 // LazyFieldResolver loads a field only when requested
@@ -1549,24 +2386,10 @@ func mapArgsToStruct(args map[string]interface{}, output interface{}) error {
 	return nil
 }
 
-// getFieldName extracts the field name from struct tags
+// getFieldName extracts the field name from struct tags (see SetFieldNameTags)
 func getFieldName(field reflect.StructField) string {
-	// Check json tag first
-	if jsonTag := field.Tag.Get("json"); jsonTag != "" {
-		parts := strings.Split(jsonTag, ",")
-		if parts[0] != "" {
-			return parts[0]
-		}
-	}
-
-	// Check graphql tag
-	if graphqlTag := field.Tag.Get("graphql"); graphqlTag != "" {
-		parts := strings.Split(graphqlTag, ",")
-		for _, part := range parts {
-			if !strings.Contains(part, "=") && part != "required" {
-				return part
-			}
-		}
+	if name, ok := taggedFieldName(field); ok {
+		return name
 	}
 
 	// Convert field name to camelCase
@@ -1583,6 +2406,52 @@ func toCamelCase(name string) string {
 	return string(runes)
 }
 
+// coerceIDValue converts between string and integer representations of an ID.
+// The GraphQL ID scalar accepts both "123" and 123 and always yields a string, so
+// integer fields must be parsed from strings and string fields formatted from integers
+// (reflect's int-to-string conversion would produce a rune instead).
+// It reports whether the value was handled.
+func coerceIDValue(fieldValue reflect.Value, argValue interface{}) (bool, error) {
+	arg := reflect.ValueOf(argValue)
+	if !arg.IsValid() {
+		return false, nil
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.String:
+		switch arg.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fieldValue.SetString(strconv.FormatInt(arg.Int(), 10))
+			return true, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fieldValue.SetString(strconv.FormatUint(arg.Uint(), 10))
+			return true, nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if arg.Kind() == reflect.String {
+			n, err := strconv.ParseInt(arg.String(), 10, fieldValue.Type().Bits())
+			if err != nil {
+				return true, fmt.Errorf("cannot convert %q to %s", arg.String(), fieldValue.Type())
+			}
+			fieldValue.SetInt(n)
+			return true, nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if arg.Kind() == reflect.String {
+			n, err := strconv.ParseUint(arg.String(), 10, fieldValue.Type().Bits())
+			if err != nil {
+				return true, fmt.Errorf("cannot convert %q to %s", arg.String(), fieldValue.Type())
+			}
+			fieldValue.SetUint(n)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // setFieldValue sets a reflect.Value with the appropriate type conversion
 func setFieldValue(fieldValue reflect.Value, argValue interface{}) error {
 	argReflectValue := reflect.ValueOf(argValue)
@@ -1592,6 +2461,11 @@ func setFieldValue(fieldValue reflect.Value, argValue interface{}) error {
 		if argValue == nil {
 			return nil // Leave as nil
 		}
+		// Values that are already of the pointer type (e.g. *Upload) are assigned directly
+		if argReflectValue.Type().AssignableTo(fieldValue.Type()) {
+			fieldValue.Set(argReflectValue)
+			return nil
+		}
 		// Create new instance of the pointer type
 		newValue := reflect.New(fieldValue.Type().Elem())
 		if err := setFieldValue(newValue.Elem(), argValue); err != nil {
@@ -1601,6 +2475,18 @@ func setFieldValue(fieldValue reflect.Value, argValue interface{}) error {
 		return nil
 	}
 
+	// GraphQL IDs may arrive as strings or integers
+	if ok, err := coerceIDValue(fieldValue, argValue); ok {
+		return err
+	}
+
+	// Strings decode into text unmarshalers such as uuid.UUID
+	if str, ok := argValue.(string); ok && fieldValue.Kind() != reflect.String && fieldValue.CanAddr() {
+		if unmarshaler, ok := fieldValue.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(str))
+		}
+	}
+
 	// Handle type conversion
 	if argReflectValue.Type().ConvertibleTo(fieldValue.Type()) {
 		fieldValue.Set(argReflectValue.Convert(fieldValue.Type()))
@@ -3,6 +3,9 @@ package graph
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
@@ -121,6 +124,172 @@ func countSelectionSetAliases(selectionSet *ast.SelectionSet) int {
 	return count
 }
 
+// countRootFields returns the largest number of root field selections in any operation.
+// Aliased fields count separately, and fragments spread at the root level are expanded.
+func countRootFields(doc *ast.Document) int {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && frag.Name != nil {
+			fragments[frag.Name.Value] = frag
+		}
+	}
+
+	max := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.SelectionSet != nil {
+			if count := countSelectionSetFields(op.SelectionSet, fragments, map[string]bool{}); count > max {
+				max = count
+			}
+		}
+	}
+	return max
+}
+
+// countSelectionSetFields counts the fields of a selection set without descending into sub-selections
+func countSelectionSetFields(selectionSet *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visited map[string]bool) int {
+	count := 0
+
+	for _, selection := range selectionSet.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			count++
+		case *ast.InlineFragment:
+			if sel.SelectionSet != nil {
+				count += countSelectionSetFields(sel.SelectionSet, fragments, visited)
+			}
+		case *ast.FragmentSpread:
+			if sel.Name == nil || visited[sel.Name.Value] {
+				continue
+			}
+			if frag, exists := fragments[sel.Name.Value]; exists && frag.SelectionSet != nil {
+				visited[sel.Name.Value] = true
+				count += countSelectionSetFields(frag.SelectionSet, fragments, visited)
+			}
+		}
+	}
+
+	return count
+}
+
+// findMaxListSize returns the largest integer passed to an argument or input object
+// field named argName, and the field it was passed to. Variables are resolved from
+// variables, then from their default values.
+func findMaxListSize(doc *ast.Document, argName string, variables map[string]interface{}) (string, int) {
+	maxField, maxSize := "", 0
+	for _, def := range doc.Definitions {
+		var selectionSet *ast.SelectionSet
+		defaults := make(map[string]ast.Value)
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			selectionSet = d.SelectionSet
+			for _, variable := range d.VariableDefinitions {
+				if variable.Variable != nil && variable.DefaultValue != nil {
+					defaults[variable.Variable.Name.Value] = variable.DefaultValue
+				}
+			}
+		case *ast.FragmentDefinition:
+			selectionSet = d.SelectionSet
+		}
+		if selectionSet == nil {
+			continue
+		}
+		walkSelectionSetFields(selectionSet, func(field *ast.Field) {
+			for _, arg := range field.Arguments {
+				if arg.Name == nil {
+					continue
+				}
+				for _, size := range listSizes(arg.Name.Value, arg.Value, argName, variables, defaults) {
+					if size > maxSize {
+						maxField, maxSize = field.Name.Value, size
+					}
+				}
+			}
+		})
+	}
+	return maxField, maxSize
+}
+
+// walkSelectionSetFields calls visit for every field of a selection set and its
+// sub-selections. Fragment definitions are walked separately.
+func walkSelectionSetFields(selectionSet *ast.SelectionSet, visit func(field *ast.Field)) {
+	for _, selection := range selectionSet.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			visit(sel)
+			if sel.SelectionSet != nil {
+				walkSelectionSetFields(sel.SelectionSet, visit)
+			}
+		case *ast.InlineFragment:
+			if sel.SelectionSet != nil {
+				walkSelectionSetFields(sel.SelectionSet, visit)
+			}
+		}
+	}
+}
+
+// listSizes returns the integers passed to argName in the value of the argument or
+// input object field name
+func listSizes(name string, value ast.Value, argName string, variables map[string]interface{}, defaults map[string]ast.Value) []int {
+	if variable, ok := value.(*ast.Variable); ok {
+		if raw, exists := variables[variable.Name.Value]; exists {
+			return rawListSizes(name, raw, argName)
+		}
+		if def, exists := defaults[variable.Name.Value]; exists {
+			return listSizes(name, def, argName, variables, defaults)
+		}
+		return nil
+	}
+
+	var sizes []int
+	switch v := value.(type) {
+	case *ast.IntValue:
+		if n, err := strconv.Atoi(v.Value); err == nil && name == argName {
+			sizes = append(sizes, n)
+		}
+	case *ast.ObjectValue:
+		for _, field := range v.Fields {
+			sizes = append(sizes, listSizes(field.Name.Value, field.Value, argName, variables, defaults)...)
+		}
+	case *ast.ListValue:
+		for _, item := range v.Values {
+			sizes = append(sizes, listSizes(name, item, argName, variables, defaults)...)
+		}
+	}
+	return sizes
+}
+
+// rawListSizes returns the integers passed to argName in a variable value
+func rawListSizes(name string, value interface{}, argName string) []int {
+	var sizes []int
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			sizes = append(sizes, rawListSizes(field, fieldValue, argName)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			sizes = append(sizes, rawListSizes(name, item, argName)...)
+		}
+	default:
+		if name != argName {
+			return nil
+		}
+		switch n := v.(type) {
+		case int:
+			sizes = append(sizes, n)
+		case int64:
+			sizes = append(sizes, int(n))
+		case float64:
+			sizes = append(sizes, int(n))
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				sizes = append(sizes, int(i))
+			}
+		}
+	}
+	return sizes
+}
+
 // calculateQueryComplexity calculates query complexity based on depth and field count
 func calculateQueryComplexity(node ast.Node, multiplier int) int {
 	complexity := 0
@@ -143,6 +312,218 @@ func calculateQueryComplexity(node ast.Node, multiplier int) int {
 	return complexity
 }
 
+// listSizeArguments are the arguments whose value multiplies the cost of the selections
+// of a list field in the weighted complexity
+var listSizeArguments = []string{"first", "last", "pageSize", "limit"}
+
+// weightedComplexity calculates query complexity from field weights. A field costs its
+// weight, plus its weight times its list size times the cost of its selections. The
+// weight of Type.field comes from costs, then from the complexity tag of the Go struct
+// field the GraphQL field was generated from, and defaults to 1. The list size is the
+// largest first, last, pageSize or limit argument of the field, and defaults to 1.
+type weightedComplexity struct {
+	schema    *graphql.Schema
+	costs     map[string]int
+	variables map[string]interface{}
+	defaults  map[string]ast.Value
+	fragments map[string]*ast.FragmentDefinition
+	tags      map[string]map[string]int
+}
+
+// calculateWeightedComplexity returns the weighted complexity of the operations of doc
+func calculateWeightedComplexity(doc *ast.Document, schema *graphql.Schema, variables map[string]interface{}, costs map[string]int) int {
+	w := &weightedComplexity{
+		schema:    schema,
+		costs:     costs,
+		variables: variables,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		tags:      make(map[string]map[string]int),
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && frag.Name != nil {
+			w.fragments[frag.Name.Value] = frag
+		}
+	}
+
+	complexity := 0
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || op.SelectionSet == nil {
+			continue
+		}
+		w.defaults = make(map[string]ast.Value)
+		for _, variable := range op.VariableDefinitions {
+			if variable.Variable != nil && variable.DefaultValue != nil {
+				w.defaults[variable.Variable.Name.Value] = variable.DefaultValue
+			}
+		}
+
+		var root graphql.Type
+		if schema != nil {
+			switch op.Operation {
+			case ast.OperationTypeQuery:
+				root = schema.QueryType()
+			case ast.OperationTypeMutation:
+				root = schema.MutationType()
+			case ast.OperationTypeSubscription:
+				root = schema.SubscriptionType()
+			}
+		}
+		complexity += w.selectionSet(op.SelectionSet, root, map[string]bool{})
+	}
+	return complexity
+}
+
+// selectionSet returns the cost of the selections of a value of type parent
+func (w *weightedComplexity) selectionSet(selectionSet *ast.SelectionSet, parent graphql.Type, visiting map[string]bool) int {
+	complexity := 0
+	for _, selection := range selectionSet.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			complexity += w.field(sel, parent, visiting)
+		case *ast.InlineFragment:
+			if sel.SelectionSet != nil {
+				complexity += w.selectionSet(sel.SelectionSet, w.typeCondition(sel.TypeCondition, parent), visiting)
+			}
+		case *ast.FragmentSpread:
+			if sel.Name == nil || visiting[sel.Name.Value] {
+				continue
+			}
+			if frag, exists := w.fragments[sel.Name.Value]; exists && frag.SelectionSet != nil {
+				visiting[sel.Name.Value] = true
+				complexity += w.selectionSet(frag.SelectionSet, w.typeCondition(frag.TypeCondition, parent), visiting)
+				delete(visiting, sel.Name.Value)
+			}
+		}
+	}
+	return complexity
+}
+
+// field returns the cost of a field of a value of type parent
+func (w *weightedComplexity) field(field *ast.Field, parent graphql.Type, visiting map[string]bool) int {
+	var typeName string
+	var fields graphql.FieldDefinitionMap
+	switch p := parent.(type) {
+	case *graphql.Object:
+		typeName, fields = p.Name(), p.Fields()
+	case *graphql.Interface:
+		typeName, fields = p.Name(), p.Fields()
+	}
+
+	name := field.Name.Value
+	weight := 1
+	if tagWeight, ok := w.tagWeights(parent, typeName)[name]; ok {
+		weight = tagWeight
+	}
+	if cost, ok := w.costs[typeName+"."+name]; ok {
+		weight = cost
+	}
+	if field.SelectionSet == nil {
+		return weight
+	}
+
+	var fieldType graphql.Type
+	if def, ok := fields[name]; ok {
+		fieldType = def.Type
+	}
+	for {
+		switch t := fieldType.(type) {
+		case *graphql.List:
+			fieldType = t.OfType
+			continue
+		case *graphql.NonNull:
+			fieldType = t.OfType
+			continue
+		}
+		break
+	}
+
+	size := 1
+	for _, arg := range field.Arguments {
+		if arg.Name == nil {
+			continue
+		}
+		for _, argName := range listSizeArguments {
+			for _, n := range listSizes(arg.Name.Value, arg.Value, argName, w.variables, w.defaults) {
+				if n > size {
+					size = n
+				}
+			}
+		}
+	}
+	return weight + weight*size*w.selectionSet(field.SelectionSet, fieldType, visiting)
+}
+
+// typeCondition returns the type named by a fragment's type condition, or parent
+func (w *weightedComplexity) typeCondition(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil || condition.Name == nil || w.schema == nil {
+		return parent
+	}
+	if t := w.schema.Type(condition.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+// tagWeights returns the complexity tag weights of the fields of a generated type
+func (w *weightedComplexity) tagWeights(parent graphql.Type, typeName string) map[string]int {
+	if typeName == "" {
+		return nil
+	}
+	weights, cached := w.tags[typeName]
+	if !cached {
+		if t, exists := generatedGoType(parent); exists {
+			weights = collectFieldComplexity(t)
+		}
+		w.tags[typeName] = weights
+	}
+	return weights
+}
+
+// collectFieldComplexity returns the weights given by the complexity tags of the
+// fields of a struct, keyed by GraphQL field name. As with field generation, fields
+// declared on the outer struct take precedence over fields of embedded structs.
+//
+//	type Author struct {
+//	    Name  string `json:"name"`
+//	    Posts []Post `json:"posts" complexity:"10"`
+//	}
+func collectFieldComplexity(t reflect.Type) map[string]int {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	gen := NewFieldGenerator[any]()
+	result := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			for name, weight := range collectFieldComplexity(field.Type) {
+				if _, exists := result[name]; !exists {
+					result[name] = weight
+				}
+			}
+			continue
+		}
+
+		fieldName := gen.getFieldName(field)
+		if field.PkgPath != "" || fieldName == "-" {
+			continue
+		}
+
+		if weight, err := strconv.Atoi(strings.TrimSpace(field.Tag.Get("complexity"))); err == nil && weight >= 0 {
+			result[fieldName] = weight
+		} else {
+			delete(result, fieldName)
+		}
+	}
+	return result
+}
+
 // calculateSelectionSetComplexity calculates complexity for a selection set
 func calculateSelectionSetComplexity(selectionSet *ast.SelectionSet, multiplier int) int {
 	complexity := 0
@@ -171,6 +552,40 @@ func calculateSelectionSetComplexity(selectionSet *ast.SelectionSet, multiplier
 	return complexity
 }
 
+// Default limits of ValidateGraphQLQuery and SecurityRules
+const (
+	DefaultMaxDepth      = 10
+	DefaultMaxAliases    = 4
+	DefaultMaxComplexity = 200
+)
+
+// QueryLimits configures the limits of ValidateGraphQLQuery and NewSecurityRules.
+// Zero values keep the defaults: depth 10, aliases 4 and complexity 200.
+type QueryLimits struct {
+	// MaxDepth: Maximum nesting depth of a query
+	MaxDepth int
+
+	// MaxAliases: Maximum number of aliases in a query
+	MaxAliases int
+
+	// MaxComplexity: Maximum estimated complexity of a query
+	MaxComplexity int
+}
+
+// withDefaults returns the limits with unset values replaced by the defaults
+func (l QueryLimits) withDefaults() QueryLimits {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultMaxDepth
+	}
+	if l.MaxAliases <= 0 {
+		l.MaxAliases = DefaultMaxAliases
+	}
+	if l.MaxComplexity <= 0 {
+		l.MaxComplexity = DefaultMaxComplexity
+	}
+	return l
+}
+
 // ValidateGraphQLQuery validates a GraphQL query against security rules.
 // This function implements multiple layers of protection against malicious or expensive queries.
 //
@@ -180,6 +595,8 @@ func calculateSelectionSetComplexity(selectionSet *ast.SelectionSet, multiplier
 //   - Max Complexity: 200 (prevents computationally expensive queries)
 //   - Introspection: Blocked (__schema and __type queries are rejected)
 //
+// The limits can be changed by passing QueryLimits; unset limits keep their defaults.
+//
 // Returns an error if:
 //   - Query depth exceeds 10 levels
 //   - Query contains more than 4 aliases
@@ -195,8 +612,17 @@ func calculateSelectionSetComplexity(selectionSet *ast.SelectionSet, multiplier
 //	}
 //	// Query is safe to execute
 //
+//	// Allow deeper queries
+//	err := graph.ValidateGraphQLQuery(queryString, schema, graph.QueryLimits{MaxDepth: 15})
+//
 // Enable this in production with GraphContext.EnableValidation = true.
-func ValidateGraphQLQuery(queryString string, schema *graphql.Schema) error {
+func ValidateGraphQLQuery(queryString string, schema *graphql.Schema, limits ...QueryLimits) error {
+	var limit QueryLimits
+	if len(limits) > 0 {
+		limit = limits[0]
+	}
+	limit = limit.withDefaults()
+
 	// Handle empty query
 	if queryString == "" {
 		return nil
@@ -230,22 +656,22 @@ func ValidateGraphQLQuery(queryString string, schema *graphql.Schema) error {
 	}
 
 	// Apply validation rules
-	// Limit query depth to 10 (matching Python's QueryDepthLimiter(max_depth=10))
-	maxDepth := 10
+	// Limit query depth, 10 by default (matching Python's QueryDepthLimiter(max_depth=10))
+	maxDepth := limit.MaxDepth
 	depth := calculateQueryDepth(doc, 0)
 	if depth > maxDepth {
 		return fmt.Errorf("query depth exceeds maximum allowed depth of %d (actual: %d)", maxDepth, depth)
 	}
 
 	// Limit max aliases to 10 (matching Python's MaxAliasesLimiter(max_alias_count=10))
-	maxAliases := 4
+	maxAliases := limit.MaxAliases
 	aliasCount := countAliases(doc)
 	if aliasCount > maxAliases {
 		return fmt.Errorf("query contains too many aliases. Maximum allowed: %d, found: %d", maxAliases, aliasCount)
 	}
 
 	// Optional: Limit query complexity
-	maxComplexity := 200
+	maxComplexity := limit.MaxComplexity
 	complexity := calculateQueryComplexity(doc, 1)
 	if complexity > maxComplexity {
 		return fmt.Errorf("query complexity exceeds maximum allowed complexity of %d (actual: %d)", maxComplexity, complexity)
@@ -254,6 +680,27 @@ func ValidateGraphQLQuery(queryString string, schema *graphql.Schema) error {
 	return nil
 }
 
+// flattenValidationError converts an error returned by a rule into validation errors.
+// Errors combined in a MultiValidationError keep the order in which the rule reported them.
+func flattenValidationError(rule ValidationRule, err error) []*ValidationError {
+	switch e := err.(type) {
+	case *ValidationError:
+		return []*ValidationError{e}
+	case *MultiValidationError:
+		var flattened []*ValidationError
+		for _, inner := range e.Errors {
+			flattened = append(flattened, flattenValidationError(rule, inner)...)
+		}
+		return flattened
+	default:
+		// Wrap non-ValidationError errors
+		return []*ValidationError{{
+			Rule:    rule.Name(),
+			Message: err.Error(),
+		}}
+	}
+}
+
 // hasIntrospection checks if the query contains introspection fields
 func hasIntrospection(node ast.Node) bool {
 	switch n := node.(type) {
@@ -319,6 +766,10 @@ func hasIntrospectionInSelectionSet(selectionSet *ast.SelectionSet) bool {
 //   - *ValidationError for single rule failure
 //   - *MultiValidationError for multiple rule failures
 //
+// Errors are always reported in rule registration order. A rule that returns a
+// *MultiValidationError has its errors inlined at its position, in the order given.
+// Errors of rules listed in options.WarnOnly are not returned.
+//
 // Example:
 //
 //	rules := []ValidationRule{
@@ -334,8 +785,19 @@ func ExecuteValidationRules(
 	userDetails interface{},
 	options *ValidationOptions,
 ) error {
+	return executeValidationRules(&ValidationContext{
+		Query:       queryString,
+		Schema:      schema,
+		UserDetails: userDetails,
+	}, rules, options)
+}
+
+// executeValidationRules parses ctx.Query into ctx.Document and runs the rules against it.
+// The HTTP handler uses it directly to pass request details (variables, request,
+// complexity estimator) that ExecuteValidationRules has no parameters for.
+func executeValidationRules(ctx *ValidationContext, rules []ValidationRule, options *ValidationOptions) error {
 	// Handle empty query
-	if queryString == "" {
+	if ctx.Query == "" {
 		return nil
 	}
 
@@ -354,7 +816,7 @@ func ExecuteValidationRules(
 
 	// Parse the query string into an AST
 	src := source.NewSource(&source.Source{
-		Body: []byte(queryString),
+		Body: []byte(ctx.Query),
 		Name: "GraphQL request",
 	})
 
@@ -365,15 +827,9 @@ func ExecuteValidationRules(
 		// If parsing fails, let the GraphQL handler deal with it
 		return nil
 	}
+	ctx.Document = doc
 
-	// Create validation context
-	ctx := &ValidationContext{
-		Document:    doc,
-		Schema:      schema,
-		UserDetails: userDetails,
-	}
-
-	// Execute all rules
+	// Execute all rules sequentially so errors are reported in rule registration order
 	var errors []*ValidationError
 	for _, rule := range rules {
 		// Skip disabled rules
@@ -383,15 +839,11 @@ func ExecuteValidationRules(
 
 		// Execute rule
 		if err := rule.Validate(ctx); err != nil {
-			if validationErr, ok := err.(*ValidationError); ok {
-				errors = append(errors, validationErr)
-			} else {
-				// Wrap non-ValidationError errors
-				errors = append(errors, &ValidationError{
-					Rule:    rule.Name(),
-					Message: err.Error(),
-				})
+			if options.warnOnly(rule.Name()) {
+				ctx.Warnings = append(ctx.Warnings, flattenValidationError(rule, err)...)
+				continue
 			}
+			errors = append(errors, flattenValidationError(rule, err)...)
 
 			// Stop on first error if configured
 			if options.StopOnFirstError {
@@ -414,3 +866,177 @@ func ExecuteValidationRules(
 		return &MultiValidationError{Errors: errs}
 	}
 }
+
+// maxAutoComplexity caps the complexity computed by schemaQueryLimits, so large
+// schemas don't get a limit that no longer protects anything
+const maxAutoComplexity = 100000
+
+// schemaQueryLimits returns the depth and complexity of the largest legal query of
+// the schema: a query selecting every field once, without entering a type already on
+// its path. A field returning such a type counts as selecting one of its leaf fields.
+// Both values use the calculations of MaxDepthRule and MaxComplexityRule.
+func schemaQueryLimits(schema *graphql.Schema) QueryLimits {
+	var limits QueryLimits
+	depths := schemaTypeDepths(schema)
+	for _, root := range []*graphql.Object{schema.QueryType(), schema.MutationType(), schema.SubscriptionType()} {
+		if root == nil {
+			continue
+		}
+		if depth := depths.depth(root); depth > limits.MaxDepth {
+			limits.MaxDepth = depth
+		}
+		complexity := 0
+		schemaTypeComplexity(schema, root, 1, map[string]bool{root.Name(): true}, &complexity)
+		if complexity > limits.MaxComplexity {
+			limits.MaxComplexity = complexity
+		}
+	}
+	if limits.MaxComplexity > maxAutoComplexity {
+		limits.MaxComplexity = maxAutoComplexity
+	}
+	return limits
+}
+
+// schemaPossibleObjects returns the object types a selection on t can select fields of
+func schemaPossibleObjects(schema *graphql.Schema, t graphql.Type) []*graphql.Object {
+	switch named := t.(type) {
+	case *graphql.Object:
+		return []*graphql.Object{named}
+	case graphql.Abstract:
+		return schema.PossibleTypes(named)
+	}
+	return nil
+}
+
+// schemaCompositeFields returns the composite types selected by the fields of t and
+// whether t has leaf fields
+func schemaCompositeFields(schema *graphql.Schema, t graphql.Type) ([]graphql.Composite, bool) {
+	var composites []graphql.Composite
+	hasLeaf := false
+	for _, object := range schemaPossibleObjects(schema, t) {
+		for _, field := range object.Fields() {
+			if fieldType, ok := graphql.GetNamed(field.Type).(graphql.Composite); ok {
+				composites = append(composites, fieldType)
+			} else {
+				hasLeaf = true
+			}
+		}
+	}
+	return composites, hasLeaf
+}
+
+// schemaDepths computes the depth of the deepest legal selection on each composite
+// type. Finding the longest path that doesn't enter a type twice takes exponential
+// time, so types reachable from each other (the strongly connected components of the
+// schema) are grouped, and a selection is assumed to go through every type of a group
+// before leaving it. This is exact for schemas without cycles and never lower than
+// the deepest legal selection otherwise, in time linear in the size of the schema.
+type schemaDepths struct {
+	schema  *graphql.Schema
+	depths  map[string]int
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []graphql.Composite
+}
+
+// schemaTypeDepths returns an empty schemaDepths of schema
+func schemaTypeDepths(schema *graphql.Schema) *schemaDepths {
+	return &schemaDepths{
+		schema:  schema,
+		depths:  make(map[string]int),
+		index:   make(map[string]int),
+		lowLink: make(map[string]int),
+		onStack: make(map[string]bool),
+	}
+}
+
+// depth returns the depth of the deepest legal selection on t
+func (d *schemaDepths) depth(t graphql.Composite) int {
+	if _, visited := d.index[t.Name()]; !visited {
+		d.visit(t)
+	}
+	return d.depths[t.Name()]
+}
+
+// visit is Tarjan's algorithm: it computes the depth of every type of a group once
+// the groups reachable from it are done
+func (d *schemaDepths) visit(t graphql.Composite) {
+	name := t.Name()
+	d.index[name] = len(d.index)
+	d.lowLink[name] = d.index[name]
+	d.stack = append(d.stack, t)
+	d.onStack[name] = true
+
+	fields, _ := schemaCompositeFields(d.schema, t)
+	for _, fieldType := range fields {
+		if _, visited := d.index[fieldType.Name()]; !visited {
+			d.visit(fieldType)
+			d.lowLink[name] = min(d.lowLink[name], d.lowLink[fieldType.Name()])
+		} else if d.onStack[fieldType.Name()] {
+			d.lowLink[name] = min(d.lowLink[name], d.index[fieldType.Name()])
+		}
+	}
+	if d.lowLink[name] != d.index[name] {
+		return
+	}
+
+	// t is the first type of its group, which is on top of the stack
+	var group []graphql.Composite
+	inGroup := make(map[string]bool)
+	for {
+		member := d.stack[len(d.stack)-1]
+		d.stack = d.stack[:len(d.stack)-1]
+		d.onStack[member.Name()] = false
+		group = append(group, member)
+		inGroup[member.Name()] = true
+		if member.Name() == name {
+			break
+		}
+	}
+
+	// The deepest selection leaving the group through any of its types
+	exit := 0
+	for _, member := range group {
+		fields, hasLeaf := schemaCompositeFields(d.schema, member)
+		if hasLeaf {
+			exit = max(exit, 1)
+		}
+		for _, fieldType := range fields {
+			if inGroup[fieldType.Name()] {
+				// Entering a type already on the path selects one of its leaf fields
+				exit = max(exit, 2)
+			} else {
+				exit = max(exit, 1+d.depths[fieldType.Name()])
+			}
+		}
+	}
+	for _, member := range group {
+		d.depths[member.Name()] = len(group) - 1 + exit
+	}
+}
+
+// schemaTypeComplexity adds the complexity of selecting every legal field of t to
+// total, stopping once total exceeds maxAutoComplexity so cyclic schemas don't walk
+// every path
+func schemaTypeComplexity(schema *graphql.Schema, t graphql.Type, multiplier int, visiting map[string]bool, total *int) {
+	for _, object := range schemaPossibleObjects(schema, t) {
+		for _, field := range object.Fields() {
+			if *total > maxAutoComplexity {
+				return
+			}
+			*total += multiplier
+			fieldType, ok := graphql.GetNamed(field.Type).(graphql.Composite)
+			if !ok {
+				continue
+			}
+			if visiting[fieldType.Name()] {
+				*total += multiplier * 2
+				continue
+			}
+			visiting[fieldType.Name()] = true
+			schemaTypeComplexity(schema, fieldType, multiplier*2, visiting, total)
+			delete(visiting, fieldType.Name())
+		}
+	}
+}
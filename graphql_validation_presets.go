@@ -1,5 +1,7 @@
 package graph
 
+import "github.com/graphql-go/graphql"
+
 // Preset rule collections for common scenarios
 
 var (
@@ -8,12 +10,7 @@ var (
 	// - Max complexity: 200
 	// - Max aliases: 4
 	// - No introspection
-	SecurityRules = []ValidationRule{
-		NewMaxDepthRule(10),
-		NewMaxComplexityRule(200),
-		NewMaxAliasesRule(4),
-		NewNoIntrospectionRule(),
-	}
+	SecurityRules = NewSecurityRules(QueryLimits{})
 
 	// StrictSecurityRules provides strict security for production
 	// - Max depth: 8
@@ -38,6 +35,41 @@ var (
 	}
 )
 
+// NewSecurityRules returns the SecurityRules with the given limits. Unset limits keep
+// the SecurityRules defaults (depth 10, complexity 200, aliases 4).
+//
+// Example:
+//   rules := NewSecurityRules(QueryLimits{MaxDepth: 15})
+func NewSecurityRules(limits QueryLimits) []ValidationRule {
+	limits = limits.withDefaults()
+	return []ValidationRule{
+		NewMaxDepthRule(limits.MaxDepth),
+		NewMaxComplexityRule(limits.MaxComplexity),
+		NewMaxAliasesRule(limits.MaxAliases),
+		NewNoIntrospectionRule(),
+	}
+}
+
+// AutoLimitsFromSchema returns depth and complexity rules with limits derived from the
+// schema instead of fixed defaults. The depth limit is the depth of the deepest query
+// that doesn't enter a type already on its path, so legal queries pass and cyclic
+// nesting beyond it is rejected. When types reference each other, the limit assumes
+// such a query goes through all of them, which may allow a few more levels. The
+// complexity limit is the cost of selecting every such field once, capped at 100000.
+//
+// Example:
+//   rules := CombineRules(
+//       AutoLimitsFromSchema(&schema),
+//       []ValidationRule{NewMaxAliasesRule(4), NewNoIntrospectionRule()},
+//   )
+func AutoLimitsFromSchema(schema *graphql.Schema) []ValidationRule {
+	limits := schemaQueryLimits(schema).withDefaults()
+	return []ValidationRule{
+		NewMaxDepthRule(limits.MaxDepth),
+		NewMaxComplexityRule(limits.MaxComplexity),
+	}
+}
+
 // CombineRules combines multiple rule sets into one
 //
 // Example:
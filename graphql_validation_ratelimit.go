@@ -94,7 +94,10 @@ func (r *RateLimitRule) Validate(ctx *ValidationContext) error {
 	}
 
 	// Calculate query cost
-	complexity := calculateQueryComplexity(ctx.Document, 1)
+	complexity, err := ctx.EstimateComplexity()
+	if err != nil {
+		return r.NewErrorf("failed to estimate query cost: %v", err)
+	}
 	cost := complexity * r.costPerUnit
 
 	// Check if cost exceeds budget
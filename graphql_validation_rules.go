@@ -83,6 +83,44 @@ type ValidationContext struct {
 	// User details from UserDetailsFn (can be nil if not authenticated)
 	// Validation rules can type-assert this to whatever structure they need
 	UserDetails interface{}
+
+	// ComplexityEstimator replaces the built-in complexity calculation (can be nil)
+	ComplexityEstimator ComplexityEstimator
+
+	// Warnings collects the errors of rules listed in ValidationOptions.WarnOnly
+	Warnings []*ValidationError
+}
+
+// ComplexityEstimator computes the cost of a query for complexity-based rules such as
+// MaxComplexityRule and RateLimitRule. Set it on GraphContext to replace the built-in
+// estimate (one point per field, weighted by nesting depth) with domain-specific costs.
+//
+// Example:
+//
+//	ComplexityEstimator: func(doc *ast.Document, variables map[string]interface{}) (int, error) {
+//	    cost := 0
+//	    visitor.Visit(doc, &visitor.VisitorOptions{
+//	        Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+//	            if field, ok := p.Node.(*ast.Field); ok {
+//	                cost++
+//	                if field.Name.Value == "search" {
+//	                    cost += 50 // full-text search is expensive
+//	                }
+//	            }
+//	            return visitor.ActionNoChange, nil
+//	        },
+//	    }, nil)
+//	    return cost, nil
+//	}
+type ComplexityEstimator func(doc *ast.Document, variables map[string]interface{}) (int, error)
+
+// EstimateComplexity returns the query complexity using the configured
+// ComplexityEstimator, falling back to the built-in calculation.
+func (ctx *ValidationContext) EstimateComplexity() (int, error) {
+	if ctx.ComplexityEstimator != nil {
+		return ctx.ComplexityEstimator(ctx.Document, ctx.Variables)
+	}
+	return calculateQueryComplexity(ctx.Document, 1), nil
 }
 
 // ValidationError provides detailed error information
@@ -125,6 +163,27 @@ type ValidationOptions struct {
 
 	// SkipInDebug skips validation when DEBUG=true
 	SkipInDebug bool
+
+	// WarnOnly lists rule names whose errors are recorded as warnings instead of
+	// failing the request. The HTTP handler returns them in extensions.warnings,
+	// which helps roll out a new limit before enforcing it.
+	//
+	// Example:
+	//
+	//	ValidationOptions: &graph.ValidationOptions{
+	//	    WarnOnly: []string{"MaxComplexityRule"},
+	//	}
+	WarnOnly []string
+}
+
+// warnOnly reports whether errors of the named rule are recorded as warnings
+func (o *ValidationOptions) warnOnly(rule string) bool {
+	for _, name := range o.WarnOnly {
+		if name == rule {
+			return true
+		}
+	}
+	return false
 }
 
 // ASTVisitor allows traversing the AST with hooks
@@ -30,6 +30,8 @@ func (r *MaxDepthRule) Validate(ctx *ValidationContext) error {
 type MaxComplexityRule struct {
 	BaseRule
 	maxComplexity int
+	weighted      bool
+	costs         map[string]int
 }
 
 // NewMaxComplexityRule creates a new max complexity validation rule
@@ -40,8 +42,46 @@ func NewMaxComplexityRule(maxComplexity int) ValidationRule {
 	}
 }
 
+// NewMaxComplexityRuleWithCosts creates a max complexity validation rule that weighs
+// fields by cost. costs holds the weight of fields keyed by TypeName.fieldName, such
+// as "Query.users"; fields generated from structs are also weighted by their
+// `complexity` tag. Fields default to a weight of 1. The selections of a field cost
+// its weight times the list size requested with first, last, pageSize or limit.
+// The ComplexityEstimator of the request is not used by this rule.
+//
+// Example:
+//
+//	type Author struct {
+//	    Name  string `json:"name"`
+//	    Posts []Post `json:"posts" complexity:"10"` // loaded from the database
+//	}
+//
+//	graph.NewMaxComplexityRuleWithCosts(1000, map[string]int{
+//	    "Query.authors": 5,
+//	})
+//
+//	// { authors(first: 10) { name posts { title } } } costs
+//	// 5 + 5*10*(1 + (10 + 10*1*1)) = 1055
+func NewMaxComplexityRuleWithCosts(maxComplexity int, costs map[string]int) ValidationRule {
+	return &MaxComplexityRule{
+		BaseRule:      NewBaseRule("MaxComplexityRule"),
+		maxComplexity: maxComplexity,
+		weighted:      true,
+		costs:         costs,
+	}
+}
+
 func (r *MaxComplexityRule) Validate(ctx *ValidationContext) error {
-	complexity := calculateQueryComplexity(ctx.Document, 1)
+	var complexity int
+	if r.weighted {
+		complexity = calculateWeightedComplexity(ctx.Document, ctx.Schema, ctx.Variables, r.costs)
+	} else {
+		var err error
+		complexity, err = ctx.EstimateComplexity()
+		if err != nil {
+			return r.NewErrorf("failed to estimate query complexity: %v", err)
+		}
+	}
 	if complexity > r.maxComplexity {
 		return r.NewErrorf("query complexity %d exceeds maximum %d", complexity, r.maxComplexity)
 	}
@@ -73,22 +113,71 @@ func (r *MaxAliasesRule) Validate(ctx *ValidationContext) error {
 // NoIntrospectionRule blocks introspection queries
 type NoIntrospectionRule struct {
 	BaseRule
+	allowRoles []string
+	allowFn    func(userDetails interface{}) bool
+}
+
+// IntrospectionOption configures which users NoIntrospectionRule lets introspect
+type IntrospectionOption func(*NoIntrospectionRule)
+
+// WithAllowRoles allows introspection for users with one of the roles.
+// The user details must implement HasRolesInterface.
+func WithAllowRoles(roles ...string) IntrospectionOption {
+	return func(r *NoIntrospectionRule) {
+		r.allowRoles = roles
+	}
 }
 
-// NewNoIntrospectionRule creates a new no introspection validation rule
-func NewNoIntrospectionRule() ValidationRule {
-	return &NoIntrospectionRule{
+// WithAllowIntrospection allows introspection for users accepted by fn.
+// fn is only called for authenticated requests.
+func WithAllowIntrospection(fn func(userDetails interface{}) bool) IntrospectionOption {
+	return func(r *NoIntrospectionRule) {
+		r.allowFn = fn
+	}
+}
+
+// NewNoIntrospectionRule creates a new no introspection validation rule.
+// By default introspection is blocked for everyone; options allow it for some users.
+//
+// Example:
+//   NewNoIntrospectionRule(WithAllowRoles("admin"))
+func NewNoIntrospectionRule(opts ...IntrospectionOption) ValidationRule {
+	rule := &NoIntrospectionRule{
 		BaseRule: NewBaseRule("NoIntrospectionRule"),
 	}
+
+	for _, opt := range opts {
+		opt(rule)
+	}
+
+	return rule
 }
 
 func (r *NoIntrospectionRule) Validate(ctx *ValidationContext) error {
-	if hasIntrospection(ctx.Document) {
+	if hasIntrospection(ctx.Document) && !r.allowed(ctx.UserDetails) {
 		return r.NewError("GraphQL introspection is disabled")
 	}
 	return nil
 }
 
+// allowed reports whether the options allow introspection for the user
+func (r *NoIntrospectionRule) allowed(userDetails interface{}) bool {
+	if userDetails == nil {
+		return false
+	}
+	if r.allowFn != nil && r.allowFn(userDetails) {
+		return true
+	}
+	if userWithRoles, ok := userDetails.(HasRolesInterface); ok {
+		for _, role := range r.allowRoles {
+			if userWithRoles.HasRole(role) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // MaxTokensRule limits query size by token count
 type MaxTokensRule struct {
 	BaseRule
@@ -109,4 +198,61 @@ func (r *MaxTokensRule) Validate(ctx *ValidationContext) error {
 		return r.NewErrorf("query contains %d tokens, maximum %d allowed", tokens, r.maxTokens)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// MaxRootFieldsRule limits the number of root fields selected by an operation
+type MaxRootFieldsRule struct {
+	BaseRule
+	maxRootFields int
+}
+
+// NewMaxRootFieldsRule creates a new max root fields validation rule.
+// Aliased selections of the same field count separately.
+func NewMaxRootFieldsRule(maxRootFields int) ValidationRule {
+	return &MaxRootFieldsRule{
+		BaseRule:      NewBaseRule("MaxRootFieldsRule"),
+		maxRootFields: maxRootFields,
+	}
+}
+
+func (r *MaxRootFieldsRule) Validate(ctx *ValidationContext) error {
+	count := countRootFields(ctx.Document)
+	if count > r.maxRootFields {
+		return r.NewErrorf("query selects %d root fields, maximum %d allowed", count, r.maxRootFields)
+	}
+	return nil
+}
+
+// MaxListSizeRule caps the number of items a list argument such as pageSize, first or
+// last may request
+type MaxListSizeRule struct {
+	BaseRule
+	argName string
+	max     int
+}
+
+// NewMaxListSizeRule creates a new max list size validation rule. It rejects queries
+// passing a value above max to any argument named argName, also when the argument is
+// a field of an input object or is bound to a variable.
+//
+// Example:
+//
+//	ValidationRules: []graph.ValidationRule{
+//	    graph.NewMaxListSizeRule("pageSize", 100),
+//	    graph.NewMaxListSizeRule("first", 100),
+//	}
+func NewMaxListSizeRule(argName string, max int) ValidationRule {
+	return &MaxListSizeRule{
+		BaseRule: NewBaseRule("MaxListSizeRule"),
+		argName:  argName,
+		max:      max,
+	}
+}
+
+func (r *MaxListSizeRule) Validate(ctx *ValidationContext) error {
+	field, size := findMaxListSize(ctx.Document, r.argName, ctx.Variables)
+	if size > r.max {
+		return r.NewErrorf("%s of field %s requests %d items, maximum %d allowed", r.argName, field, size, r.max)
+	}
+	return nil
+}
@@ -1,12 +1,18 @@
 package graph
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/visitor"
 )
 
 // MockUser implements minimal interfaces for testing
@@ -222,6 +228,211 @@ func TestMaxAliasesRule(t *testing.T) {
 	}
 }
 
+// TestMaxRootFieldsRule tests the MaxRootFieldsRule validation
+func TestMaxRootFieldsRule(t *testing.T) {
+	schema := createTestSchema()
+
+	tests := []struct {
+		name          string
+		query         string
+		maxRootFields int
+		shouldError   bool
+	}{
+		{
+			name:          "Root fields under limit",
+			query:         `{ u1: user { id name email } u2: user { id } }`,
+			maxRootFields: 3,
+			shouldError:   false,
+		},
+		{
+			name:          "Root fields at limit",
+			query:         `{ u1: user { id } u2: user { id } u3: user { id } }`,
+			maxRootFields: 3,
+			shouldError:   false,
+		},
+		{
+			name:          "Aliased root fields over limit",
+			query:         `{ u1: user { id } u2: user { id } u3: user { id } u4: user { id } }`,
+			maxRootFields: 3,
+			shouldError:   true,
+		},
+		{
+			name:          "Fragment root fields over limit",
+			query:         `query { ...Users u3: user { id } } fragment Users on Query { u1: user { id } u2: user { id } }`,
+			maxRootFields: 2,
+			shouldError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []ValidationRule{NewMaxRootFieldsRule(tt.maxRootFields)}
+			err := ExecuteValidationRules(tt.query, schema, rules, nil, nil)
+
+			if tt.shouldError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestMaxComplexityRuleWithCosts tests the weighted MaxComplexityRule
+func TestMaxComplexityRuleWithCosts(t *testing.T) {
+	type ComplexityTestPost struct {
+		Title string `json:"title"`
+	}
+	type ComplexityTestAuthor struct {
+		Name  string               `json:"name"`
+		Posts []ComplexityTestPost `json:"posts" complexity:"10"`
+	}
+	type ComplexityTestAuthorsArgs struct {
+		First int `json:"first"`
+	}
+
+	authors := NewResolver[ComplexityTestAuthor]("complexityTestAuthors").
+		AsList().
+		WithArgsFromStruct(ComplexityTestAuthorsArgs{}).
+		WithResolver(func(p ResolveParams) (*ComplexityTestAuthor, error) {
+			return nil, nil
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{authors},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	complexity := func(query string, variables map[string]interface{}, costs map[string]int) int {
+		doc, err := parser.Parse(parser.ParseParams{Source: query})
+		if err != nil {
+			t.Fatalf("Failed to parse query: %v", err)
+		}
+		return calculateWeightedComplexity(doc, &schema, variables, costs)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		costs     map[string]int
+		want      int
+	}{
+		{name: "unweighted scalar", query: `{ complexityTestAuthors { name } }`, want: 1 + 1},
+		{name: "tag weight", query: `{ complexityTestAuthors { posts { title } } }`, want: 1 + (10 + 10*1)},
+		{name: "list size", query: `{ complexityTestAuthors(first: 10) { name } }`, want: 1 + 10*1},
+		{name: "list size variable", query: `query($n: Int) { complexityTestAuthors(first: $n) { name } }`, variables: map[string]interface{}{"n": float64(5)}, want: 1 + 5*1},
+		{
+			name:  "cost map",
+			query: `{ complexityTestAuthors(first: 10) { name posts { title } } }`,
+			costs: map[string]int{"Query.complexityTestAuthors": 5},
+			want:  5 + 5*10*(1+(10+10*1)),
+		},
+		{
+			name:  "cost map overrides tag",
+			query: `{ complexityTestAuthors { ...AuthorPosts } } fragment AuthorPosts on ComplexityTestAuthor { posts { title } }`,
+			costs: map[string]int{"ComplexityTestAuthor.posts": 2},
+			want:  1 + (2 + 2*1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := complexity(tt.query, tt.variables, tt.costs); got != tt.want {
+				t.Errorf("Expected complexity %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	rules := []ValidationRule{NewMaxComplexityRuleWithCosts(100, nil)}
+	if err := ExecuteValidationRules(`{ complexityTestAuthors(first: 4) { posts { title } } }`, &schema, rules, nil, nil); err != nil {
+		t.Errorf("Expected a cheap query to pass, got %v", err)
+	}
+	err = ExecuteValidationRules(`{ complexityTestAuthors(first: 50) { posts { title } } }`, &schema, rules, nil, nil)
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Rule != "MaxComplexityRule" {
+		t.Errorf("Expected a MaxComplexityRule error, got %v", err)
+	}
+}
+
+// TestMaxListSizeRule tests the MaxListSizeRule validation
+func TestMaxListSizeRule(t *testing.T) {
+	schema := createTestSchema()
+
+	tests := []struct {
+		name        string
+		query       string
+		variables   map[string]interface{}
+		shouldError bool
+	}{
+		{
+			name:        "Size under limit",
+			query:       `{ users(pageSize: 50) { id } }`,
+			shouldError: false,
+		},
+		{
+			name:        "Size over limit",
+			query:       `{ users(pageSize: 1000000) { id } }`,
+			shouldError: true,
+		},
+		{
+			name:        "Other arguments are ignored",
+			query:       `{ users(page: 1000000, pageSize: 10) { id } }`,
+			shouldError: false,
+		},
+		{
+			name:        "Nested input object field over limit",
+			query:       `{ users(filter: { pagination: { pageSize: 500 } }) { id } }`,
+			shouldError: true,
+		},
+		{
+			name:        "Variable over limit",
+			query:       `query Users($size: Int) { users(pageSize: $size) { id } }`,
+			variables:   map[string]interface{}{"size": float64(500)},
+			shouldError: true,
+		},
+		{
+			name:        "Input object variable over limit",
+			query:       `query Users($filter: Filter) { users(filter: $filter) { id } }`,
+			variables:   map[string]interface{}{"filter": map[string]interface{}{"pageSize": float64(500)}},
+			shouldError: true,
+		},
+		{
+			name:        "Variable default over limit",
+			query:       `query Users($size: Int = 500) { users(pageSize: $size) { id } }`,
+			shouldError: true,
+		},
+		{
+			name:        "Fragment field over limit",
+			query:       `query { ...Users } fragment Users on Query { users(pageSize: 500) { id } }`,
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []ValidationRule{NewMaxListSizeRule("pageSize", 100)}
+			err := executeValidationRules(&ValidationContext{
+				Query:     tt.query,
+				Schema:    schema,
+				Variables: tt.variables,
+			}, rules, nil)
+
+			if tt.shouldError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+			if validationErr, ok := err.(*ValidationError); tt.shouldError && (!ok || validationErr.Rule != "MaxListSizeRule") {
+				t.Errorf("Expected a MaxListSizeRule error, got %v", err)
+			}
+		})
+	}
+}
+
 // TestNoIntrospectionRule tests the NoIntrospectionRule validation
 func TestNoIntrospectionRule(t *testing.T) {
 	schema := createTestSchema()
@@ -263,6 +474,48 @@ func TestNoIntrospectionRule(t *testing.T) {
 	}
 }
 
+// TestNoIntrospectionRule_AllowRoles tests introspection allowed for some users
+func TestNoIntrospectionRule_AllowRoles(t *testing.T) {
+	schema := createTestSchema()
+	query := `{ __schema { queryType { name } } }`
+
+	admin := &MockUser{id: "1", roles: []string{"admin"}}
+	member := &MockUser{id: "2", roles: []string{"member"}}
+	service := map[string]interface{}{"id": "svc", "internal": true}
+
+	tests := []struct {
+		name        string
+		rule        ValidationRule
+		userDetails interface{}
+		shouldError bool
+	}{
+		{name: "admin allowed", rule: NewNoIntrospectionRule(WithAllowRoles("admin")), userDetails: admin},
+		{name: "member blocked", rule: NewNoIntrospectionRule(WithAllowRoles("admin")), userDetails: member, shouldError: true},
+		{name: "anonymous blocked", rule: NewNoIntrospectionRule(WithAllowRoles("admin")), shouldError: true},
+		{name: "no options blocks admin", rule: NewNoIntrospectionRule(), userDetails: admin, shouldError: true},
+		{
+			name: "predicate allowed",
+			rule: NewNoIntrospectionRule(WithAllowIntrospection(func(userDetails interface{}) bool {
+				details, _ := userDetails.(map[string]interface{})
+				return details["internal"] == true
+			})),
+			userDetails: service,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExecuteValidationRules(query, schema, []ValidationRule{tt.rule}, tt.userDetails, nil)
+			if tt.shouldError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
 // TestMaxTokensRule tests the MaxTokensRule validation
 func TestMaxTokensRule(t *testing.T) {
 	schema := createTestSchema()
@@ -302,6 +555,31 @@ func TestMaxTokensRule(t *testing.T) {
 	}
 }
 
+// queryCaptureRule records the query string it is validated with
+type queryCaptureRule struct {
+	BaseRule
+	query string
+}
+
+func (r *queryCaptureRule) Validate(ctx *ValidationContext) error {
+	r.query = ctx.Query
+	return nil
+}
+
+// TestExecuteValidationRules_PassesQuery tests that rules see the raw query string
+func TestExecuteValidationRules_PassesQuery(t *testing.T) {
+	schema := createTestSchema()
+	query := `{ user { id } }`
+
+	rule := &queryCaptureRule{BaseRule: NewBaseRule("QueryCaptureRule")}
+	if err := ExecuteValidationRules(query, schema, []ValidationRule{rule}, nil, nil); err != nil {
+		t.Fatalf("ExecuteValidationRules() error = %v", err)
+	}
+	if rule.query != query {
+		t.Errorf("Expected ValidationContext.Query %q, got %q", query, rule.query)
+	}
+}
+
 // TestRequireAuthRule tests the RequireAuthRule validation
 func TestRequireAuthRule(t *testing.T) {
 	schema := createTestSchema()
@@ -598,6 +876,121 @@ func TestStopOnFirstError(t *testing.T) {
 	}
 }
 
+// TestWarnOnly tests that warn-only rules report warnings without failing the request
+func TestWarnOnly(t *testing.T) {
+	schema := createTestSchema()
+	rules := []ValidationRule{
+		NewMaxDepthRule(1),
+		NewMaxAliasesRule(0),
+	}
+	query := `{ u1: user { id name } u2: user { email } }`
+
+	// Only the enforced rule fails validation
+	err := ExecuteValidationRules(query, schema, rules, nil, &ValidationOptions{WarnOnly: []string{"MaxDepthRule"}})
+	validationErr, ok := err.(*ValidationError)
+	if !ok || validationErr.Rule != "MaxAliasesRule" {
+		t.Fatalf("Expected single MaxAliasesRule error but got %v", err)
+	}
+
+	handler := NewHTTP(&GraphContext{
+		Schema:            schema,
+		ValidationRules:   rules,
+		ValidationOptions: &ValidationOptions{WarnOnly: []string{"MaxDepthRule", "MaxAliasesRule"}},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ u1: user { id name } u2: user { email } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 but got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Data       map[string]interface{} `json:"data"`
+		Errors     []interface{}          `json:"errors"`
+		Extensions struct {
+			Warnings []struct {
+				Message string `json:"message"`
+				Rule    string `json:"rule"`
+			} `json:"warnings"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Errors) > 0 || response.Data["u1"] == nil {
+		t.Errorf("Expected the query to execute, got %s", rec.Body.String())
+	}
+
+	warnings := response.Extensions.Warnings
+	if len(warnings) != 2 || warnings[0].Rule != "MaxDepthRule" || warnings[1].Rule != "MaxAliasesRule" {
+		t.Fatalf("Expected MaxDepthRule and MaxAliasesRule warnings, got %s", rec.Body.String())
+	}
+	if warnings[0].Message == "" {
+		t.Error("Expected warning message")
+	}
+}
+
+// multiErrorRule is a test rule that reports several errors at once
+type multiErrorRule struct {
+	BaseRule
+	messages []string
+}
+
+func (r *multiErrorRule) Validate(ctx *ValidationContext) error {
+	var errs []error
+	for _, msg := range r.messages {
+		errs = append(errs, r.NewError(msg))
+	}
+	return NewMultiValidationError(errs)
+}
+
+// TestExecuteValidationRules_DeterministicOrder tests that errors follow rule registration order
+func TestExecuteValidationRules_DeterministicOrder(t *testing.T) {
+	schema := createTestSchema()
+
+	rules := []ValidationRule{
+		NewMaxTokensRule(1),
+		NewMaxDepthRule(1),
+		&multiErrorRule{BaseRule: NewBaseRule("MultiErrorRule"), messages: []string{"first", "second"}},
+		NewMaxAliasesRule(0),
+		NewBlockedFieldsRule("email"),
+	}
+
+	query := `{ u1: user { id name } u2: user { email } }`
+	wantRules := []string{"MaxTokensRule", "MaxDepthRule", "MultiErrorRule", "MultiErrorRule", "MaxAliasesRule", "BlockedFieldsRule"}
+
+	var firstMessage string
+	for i := 0; i < 50; i++ {
+		err := ExecuteValidationRules(query, schema, rules, nil, nil)
+		multiErr, ok := err.(*MultiValidationError)
+		if !ok {
+			t.Fatalf("Expected MultiValidationError but got %T: %v", err, err)
+		}
+
+		if len(multiErr.Errors) != len(wantRules) {
+			t.Fatalf("Expected %d errors but got %d: %v", len(wantRules), len(multiErr.Errors), err)
+		}
+		for j, e := range multiErr.Errors {
+			if rule := e.(*ValidationError).Rule; rule != wantRules[j] {
+				t.Errorf("Error %d: expected rule %s but got %s", j, wantRules[j], rule)
+			}
+		}
+
+		if i == 0 {
+			firstMessage = err.Error()
+		} else if err.Error() != firstMessage {
+			t.Fatalf("Error output changed between runs:\n%s\nvs\n%s", firstMessage, err.Error())
+		}
+	}
+
+	if !strings.Contains(firstMessage, "first") || strings.Index(firstMessage, "first") > strings.Index(firstMessage, "second") {
+		t.Errorf("Expected errors of a multi-error rule to keep their order: %s", firstMessage)
+	}
+}
+
 // TestPresetRules tests the preset rule collections
 func TestPresetRules(t *testing.T) {
 	schema := createTestSchema()
@@ -743,6 +1136,78 @@ func TestHandlerIntegration(t *testing.T) {
 	}
 }
 
+// TestComplexityEstimator tests that a custom estimator replaces the built-in complexity
+func TestComplexityEstimator(t *testing.T) {
+	var seenVariables map[string]interface{}
+	estimator := func(doc *ast.Document, variables map[string]interface{}) (int, error) {
+		seenVariables = variables
+		cost := 0
+		visitor.Visit(doc, &visitor.VisitorOptions{
+			Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+				if field, ok := p.Node.(*ast.Field); ok {
+					cost++
+					// sensitiveData is expensive to compute
+					if field.Name.Value == "sensitiveData" {
+						cost += 100
+					}
+				}
+				return visitor.ActionNoChange, nil
+			},
+		}, nil)
+		return cost, nil
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "Cheap fields pass",
+			body:           `{"query":"{ user { id name email } }"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Over-weighted field is rejected",
+			body:           `{"query":"{ sensitiveData }"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Variables are passed to the estimator",
+			body:           `{"query":"query($id: String) { user { id } }","variables":{"id":"42"}}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTP(&GraphContext{
+				Schema:              createTestSchema(),
+				ValidationRules:     []ValidationRule{NewMaxComplexityRule(50)},
+				ComplexityEstimator: estimator,
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d but got %d. Body: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if seenVariables["id"] != "42" {
+		t.Errorf("Expected estimator to receive request variables, got %v", seenVariables)
+	}
+
+	// The built-in estimate rates the same query as cheap
+	if err := ExecuteValidationRules(`{ sensitiveData }`, createTestSchema(), []ValidationRule{NewMaxComplexityRule(50)}, nil, nil); err != nil {
+		t.Errorf("Expected built-in complexity to pass, got %v", err)
+	}
+}
+
 // TestDisableRule tests that disabled rules are skipped
 func TestDisableRule(t *testing.T) {
 	schema := createTestSchema()
@@ -764,4 +1229,105 @@ func TestDisableRule(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error from enabled rule but got none")
 	}
-}
\ No newline at end of file
+}
+
+func TestAutoLimitsFromSchema(t *testing.T) {
+	var user *graphql.Object
+	post := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AutoLimitsPost",
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			return graphql.Fields{
+				"title":  &graphql.Field{Type: graphql.String},
+				"author": &graphql.Field{Type: user},
+			}
+		}),
+	})
+	user = graphql.NewObject(graphql.ObjectConfig{
+		Name: "AutoLimitsUser",
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.ID},
+			"posts": &graphql.Field{Type: graphql.NewList(post)},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{Type: user},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	limits := schemaQueryLimits(&schema)
+	if limits.MaxDepth != 4 {
+		t.Errorf("Expected max depth 4, got %d", limits.MaxDepth)
+	}
+
+	rules := AutoLimitsFromSchema(&schema)
+	tests := []struct {
+		name      string
+		query     string
+		wantError bool
+	}{
+		{name: "deepest legal query", query: "{ user { id posts { title author { id } } } }"},
+		{name: "one level deeper", query: "{ user { posts { author { posts { title } } } } }", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExecuteValidationRules(tt.query, &schema, rules, nil, nil)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ExecuteValidationRules() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && err != nil && !strings.Contains(err.Error(), "MaxDepthRule") {
+				t.Errorf("Expected a depth error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAutoLimitsFromSchema_DenselyLinkedTypes(t *testing.T) {
+	// Every type links to every type, so the number of paths without a repeated type
+	// grows factorially with the number of types
+	const typeCount = 12
+	types := make([]*graphql.Object, typeCount)
+	for i := range types {
+		types[i] = graphql.NewObject(graphql.ObjectConfig{
+			Name: fmt.Sprintf("DenseType%d", i),
+			Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+				fields := graphql.Fields{"id": &graphql.Field{Type: graphql.ID}}
+				for j, linked := range types {
+					fields[fmt.Sprintf("link%d", j)] = &graphql.Field{Type: linked}
+				}
+				return fields
+			}),
+		})
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"dense": &graphql.Field{Type: types[0]},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	start := time.Now()
+	limits := schemaQueryLimits(&schema)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected limits of a densely linked schema to be computed quickly, took %v", elapsed)
+	}
+
+	// The deepest legal query goes through every type, then selects a leaf of a type on its path
+	if limits.MaxDepth != typeCount+2 {
+		t.Errorf("Expected max depth %d, got %d", typeCount+2, limits.MaxDepth)
+	}
+	if limits.MaxComplexity != maxAutoComplexity {
+		t.Errorf("Expected max complexity to be capped at %d, got %d", maxAutoComplexity, limits.MaxComplexity)
+	}
+}
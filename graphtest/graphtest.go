@@ -0,0 +1,78 @@
+// Package graphtest provides test helpers for schemas built with the graph package.
+// It is separate from graph so programs importing graph don't link the testing package.
+package graphtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/paulmanoni/go-graph"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertSchemaMatches write
+// the golden file instead of comparing against it
+const UpdateGoldenEnv = "GRAPH_UPDATE_GOLDEN"
+
+// AssertSchemaMatches fails the test when the SDL of the schema differs from the
+// golden file, guarding against accidental schema changes. Run the tests with
+// GRAPH_UPDATE_GOLDEN=1 to create or update the golden file after an intended change.
+//
+// Example:
+//
+//	func TestSchema(t *testing.T) {
+//	    schema, err := graph.NewSchemaBuilder(params).Build()
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    graphtest.AssertSchemaMatches(t, &schema, "testdata/schema.graphql")
+//	}
+func AssertSchemaMatches(t testing.TB, schema *graphql.Schema, goldenPath string) {
+	t.Helper()
+	sdl := graph.PrintSchema(schema)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("failed to create the golden file directory: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(sdl), 0o644); err != nil {
+			t.Fatalf("failed to update the golden file: %v", err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read the golden file %s (run with %s=1 to create it): %v", goldenPath, UpdateGoldenEnv, err)
+		return
+	}
+	if diff := schemaDiff(string(golden), sdl); diff != "" {
+		t.Errorf("schema does not match %s (run with %s=1 to update it):\n%s", goldenPath, UpdateGoldenEnv, diff)
+	}
+}
+
+// schemaDiff describes the first line where the SDL differs from the golden SDL, or
+// returns an empty string when they are equal
+func schemaDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, wantLine, gotLine)
+		}
+	}
+	return ""
+}
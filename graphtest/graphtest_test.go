@@ -0,0 +1,85 @@
+package graphtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/paulmanoni/go-graph"
+)
+
+type SnapshotTestUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// recordingTB records the failures of an assertion instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func snapshotTestSchema(t *testing.T, fields ...graph.QueryField) *graphql.Schema {
+	t.Helper()
+	schema, err := graph.NewSchemaBuilder(graph.SchemaBuilderParams{QueryFields: fields}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	return &schema
+}
+
+func TestAssertSchemaMatches(t *testing.T) {
+	user := graph.NewResolver[SnapshotTestUser]("snapshotTestUser").
+		WithResolver(func(p graph.ResolveParams) (*SnapshotTestUser, error) {
+			return nil, nil
+		}).
+		BuildQuery()
+	hello := graph.NewResolver[string]("hello").
+		WithResolver(func(p graph.ResolveParams) (*string, error) {
+			return nil, nil
+		}).
+		BuildQuery()
+	schema := snapshotTestSchema(t, user)
+	goldenPath := filepath.Join(t.TempDir(), "testdata", "schema.graphql")
+
+	t.Run("missing golden file", func(t *testing.T) {
+		recorder := &recordingTB{TB: t}
+		AssertSchemaMatches(recorder, schema, goldenPath)
+		if len(recorder.failures) != 1 || !strings.Contains(recorder.failures[0], UpdateGoldenEnv) {
+			t.Errorf("Expected a failure naming %s, got %v", UpdateGoldenEnv, recorder.failures)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Setenv(UpdateGoldenEnv, "1")
+		AssertSchemaMatches(t, schema, goldenPath)
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		AssertSchemaMatches(t, schema, goldenPath)
+	})
+
+	t.Run("changed schema", func(t *testing.T) {
+		changed := snapshotTestSchema(t, user, hello)
+		recorder := &recordingTB{TB: t}
+		AssertSchemaMatches(recorder, changed, goldenPath)
+		if len(recorder.failures) != 1 {
+			t.Fatalf("Expected the changed schema to fail, got %v", recorder.failures)
+		}
+		if !strings.Contains(recorder.failures[0], "+   hello: String") {
+			t.Errorf("Expected the diff to show the new field, got %s", recorder.failures[0])
+		}
+	})
+}
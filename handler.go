@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/handler"
 )
 
@@ -40,6 +48,71 @@ func ExtractBearerToken(r *http.Request) string {
 	return ""
 }
 
+// ExtractCookieToken returns a token extractor that reads the token from the cookie
+// with the given name, such as an httpOnly session cookie set for a browser app.
+//
+// Browsers send cookies with WebSocket upgrade requests too, including upgrades
+// started by other sites, and WebSocket connections are not subject to CORS. With
+// subscriptions enabled, set GraphContext.WebSocketCheckOrigin to accept only your
+// own origins when authenticating with cookies; the default accepts every origin,
+// which lets any site open subscriptions as the logged-in user.
+//
+// Example:
+//
+//	TokenExtractorFn: graph.ExtractCookieToken("auth_token")
+func ExtractCookieToken(name string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(cookie.Value)
+	}
+}
+
+// ExtractQueryToken returns a token extractor that reads the token from the URL query
+// parameter with the given name, e.g. for webhooks calling "/graphql?token=abc123xyz".
+// Query strings end up in access logs and browser history, so prefer headers or
+// cookies where the client can set them.
+//
+// Example:
+//
+//	TokenExtractorFn: graph.ExtractQueryToken("token")
+func ExtractQueryToken(name string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		if r.URL == nil {
+			return ""
+		}
+		return strings.TrimSpace(r.URL.Query().Get(name))
+	}
+}
+
+// ChainExtractors returns a token extractor that tries the extractors in order and
+// returns the first non-empty token, so the order of the arguments is their precedence.
+// Nil extractors are skipped.
+//
+// Example:
+//
+//	// Authorization header first, then the session cookie, then ?token=
+//	TokenExtractorFn: graph.ChainExtractors(
+//	    graph.ExtractBearerToken,
+//	    graph.ExtractCookieToken("auth_token"),
+//	    graph.ExtractQueryToken("token"),
+//	)
+func ChainExtractors(extractors ...func(*http.Request) string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		for _, extractor := range extractors {
+			if extractor == nil {
+				continue
+			}
+			if token := extractor(r); token != "" {
+				return token
+			}
+		}
+		return ""
+	}
+}
+
 // extractToken extracts token using custom extractor or falls back to Bearer token extraction
 func extractToken(r *http.Request, extractorFn func(*http.Request) string) string {
 	if extractorFn != nil {
@@ -104,7 +177,13 @@ func callUserDetailsFn(graphCtx *GraphContext, ctx context.Context, token string
 }
 
 // createWebSocketAuthFn creates an auth function for WebSocket connections
-// that reuses the HTTP authentication logic from GraphContext
+// that reuses the HTTP authentication logic from GraphContext.
+// Browsers can't set headers on WebSocket requests, so the token is read from, in order:
+//   - the upgrade request, with TokenExtractorFn (default: Bearer token), which can
+//     read cookies with ExtractCookieToken
+//   - the "token" query parameter of the upgrade URL (ExtractQueryToken)
+//   - the "authorization" (or "token") value of the connection_init payload, with or
+//     without a "Bearer " prefix
 func createWebSocketAuthFn(graphCtx *GraphContext) func(r *http.Request) (interface{}, error) {
 	if graphCtx.UserDetailsFn == nil {
 		return nil
@@ -117,7 +196,18 @@ func createWebSocketAuthFn(graphCtx *GraphContext) func(r *http.Request) (interf
 			tokenExtractor = ExtractBearerToken
 		}
 
-		token := tokenExtractor(r)
+		// The payload is checked last, so leave out a header copied from it
+		init, _ := r.Context().Value(connectionInitKey{}).(*connectionInit)
+		headerReq := r
+		if init != nil && init.headerFromPayload {
+			headerReq = r.Clone(r.Context())
+			headerReq.Header.Del("Authorization")
+		}
+
+		token := ChainExtractors(tokenExtractor, ExtractQueryToken("token"))(headerReq)
+		if token == "" && init != nil {
+			token = connectionInitToken(init.payload)
+		}
 		if token == "" {
 			return nil, nil // No token, no auth
 		}
@@ -127,6 +217,23 @@ func createWebSocketAuthFn(graphCtx *GraphContext) func(r *http.Request) (interf
 	}
 }
 
+// connectionInitToken returns the token of a connection_init payload, without its
+// "Bearer " prefix
+func connectionInitToken(payload map[string]interface{}) string {
+	for _, key := range []string{"authorization", "Authorization", "token"} {
+		value, ok := payload[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		const bearerPrefix = "Bearer "
+		if len(value) > len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(value[len(bearerPrefix):])
+		}
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
 // buildSchemaFromContext builds a GraphQL schema from the GraphContext
 // Priority: Schema > SchemaParams > Default hello world schema
 func buildSchemaFromContext(graphCtx *GraphContext) (*graphql.Schema, error) {
@@ -151,6 +258,11 @@ func buildSchemaFromContext(graphCtx *GraphContext) (*graphql.Schema, error) {
 		}
 	}
 
+	// Misconfigured fields fail the build in DEBUG mode
+	if graphCtx.DEBUG {
+		params.Debug = true
+	}
+
 	// Build schema
 	schema, err := NewSchemaBuilder(params).Build()
 	if err != nil {
@@ -195,13 +307,7 @@ func (w *responseWriterWrapper) sanitizeAndWrite() {
 			for _, errItem := range errors {
 				if errMap, ok := errItem.(map[string]interface{}); ok {
 					if message, ok := errMap["message"].(string); ok {
-						// Remove field suggestions using regex
-						re := regexp.MustCompile(`Did you mean "[^"]+"\?`)
-						sanitized := re.ReplaceAllString(message, "")
-						// Clean up extra spaces
-						sanitized = regexp.MustCompile(`\s+`).ReplaceAllString(sanitized, " ")
-						sanitized = strings.TrimSpace(sanitized)
-						errMap["message"] = sanitized
+						errMap["message"] = sanitizeMessage(message)
 					}
 				}
 			}
@@ -217,6 +323,52 @@ func (w *responseWriterWrapper) sanitizeAndWrite() {
 	_, _ = w.ResponseWriter.Write(body)
 }
 
+// sanitizeMessage removes field suggestions from an error message
+func sanitizeMessage(message string) string {
+	// Remove field suggestions using regex
+	re := regexp.MustCompile(`Did you mean "[^"]+"\?`)
+	sanitized := re.ReplaceAllString(message, "")
+	// Clean up extra spaces
+	sanitized = regexp.MustCompile(`\s+`).ReplaceAllString(sanitized, " ")
+	return strings.TrimSpace(sanitized)
+}
+
+// buildRootObjectFn creates the root object function shared by the HTTP handlers.
+// It adds the extracted token and, when UserDetailsFn is set, the user details to the root value.
+func buildRootObjectFn(graphCtx *GraphContext) handler.RootObjectFn {
+	return func(ctx context.Context, r *http.Request) map[string]interface{} {
+		if graphCtx.RootObjectFn != nil {
+			graphCtx.RootObjectFn(ctx, r)
+		}
+
+		// Create root value with token for GraphQL resolvers
+		rootValue := make(map[string]interface{})
+
+		// Use custom token extractor if provided, otherwise use default Bearer token extractor
+		tokenExtractor := graphCtx.TokenExtractorFn
+		if tokenExtractor == nil {
+			tokenExtractor = ExtractBearerToken
+		}
+
+		token := tokenExtractor(r)
+		if token != "" {
+			rootValue["token"] = token
+
+			// Use custom user details fetcher if provided
+			// Note: Context updates from UserDetailsFn are only accessible when using NewHTTP()
+			// The New() function cannot modify the request context
+			if graphCtx.UserDetailsFn != nil {
+				_, details, err := graphCtx.UserDetailsFn(ctx, token)
+				if err == nil {
+					rootValue["details"] = details
+				}
+			}
+		}
+
+		return rootValue
+	}
+}
+
 // New creates a GraphQL handler from the provided GraphContext.
 // It builds the schema and sets up authentication with token extraction and user details.
 //
@@ -235,52 +387,86 @@ func (w *responseWriterWrapper) sanitizeAndWrite() {
 //	    },
 //	    Playground: true,
 //	})
-func New(graphCtx GraphContext) (*handler.Handler, error) {
+func New(graphCtx GraphContext) (*Handler, error) {
 	// Build schema from context
 	schema, err := buildSchemaFromContext(&graphCtx)
 	if err != nil {
 		return nil, err
 	}
 
-	h := handler.New(&handler.Config{
-		Schema:     schema,
-		Pretty:     graphCtx.Pretty,
-		GraphiQL:   graphCtx.GraphiQL,
-		Playground: graphCtx.Playground,
-		RootObjectFn: func(ctx context.Context, r *http.Request) map[string]interface{} {
-			if graphCtx.RootObjectFn != nil {
-				graphCtx.RootObjectFn(ctx, r)
-			}
+	rootObjectFn := buildRootObjectFn(&graphCtx)
+	return &Handler{
+		Handler:  newHandler(&graphCtx, schema, rootObjectFn),
+		graphCtx: &graphCtx,
+		schema:   schema,
+	}, nil
+}
 
-			// Create root value with token for GraphQL resolvers
-			rootValue := make(map[string]interface{})
+// Handler is the GraphQL handler returned by New. It embeds the graphql-go handler
+// and runs the GraphContext hooks around each request it serves.
+type Handler struct {
+	*handler.Handler
+	graphCtx *GraphContext
+	schema   *graphql.Schema
+}
 
-			// Use custom token extractor if provided, otherwise use default Bearer token extractor
-			tokenExtractor := graphCtx.TokenExtractorFn
-			if tokenExtractor == nil {
-				tokenExtractor = ExtractBearerToken
-			}
+// ServeHTTP bounds the request by RequestTimeout, reads its operation once, passes it
+// to BeforeExecute and executes the request with the context the hook returned.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.graphCtx.RequestTimeout > 0 {
+		var finish func()
+		w, r, finish = withRequestTimeout(w, r, h.graphCtx.RequestTimeout)
+		defer finish()
+	}
+	if h.graphCtx.EchoCostHeader || h.graphCtx.BeforeExecute != nil {
+		payload := readRequestPayload(r)
+		if h.graphCtx.EchoCostHeader {
+			setCostHeader(w, h.graphCtx, h.schema, payload.Query, payload.Variables)
+		}
+		r = beforeExecute(h.graphCtx, r, &graphql.Params{
+			Schema:         *h.schema,
+			RequestString:  payload.Query,
+			VariableValues: payload.Variables,
+			OperationName:  payload.OperationName,
+		})
+	}
+	h.Handler.ServeHTTP(w, r)
+}
 
-			token := tokenExtractor(r)
-			if token != "" {
-				rootValue["token"] = token
-
-				// Use custom user details fetcher if provided
-				// Note: Context updates from UserDetailsFn are only accessible when using NewHTTP()
-				// The New() function cannot modify the request context
-				if graphCtx.UserDetailsFn != nil {
-					_, details, err := graphCtx.UserDetailsFn(ctx, token)
-					if err == nil {
-						rootValue["details"] = details
-					}
-				}
+// newHandler creates the graphql-go handler, calling AfterExecute with each result
+func newHandler(graphCtx *GraphContext, schema *graphql.Schema, rootObjectFn handler.RootObjectFn) *handler.Handler {
+	config := &handler.Config{
+		Schema:        schema,
+		Pretty:        graphCtx.Pretty,
+		GraphiQL:      graphCtx.GraphiQL,
+		Playground:    graphCtx.Playground,
+		RootObjectFn:  rootObjectFn,
+		FormatErrorFn: formatError,
+	}
+	if graphCtx.AfterExecute != nil || graphCtx.ErrorFormatter != nil {
+		config.ResultCallbackFn = func(ctx context.Context, params *graphql.Params, result *graphql.Result, responseBody []byte) {
+			if captured, ok := ctx.Value(executionResultKey{}).(*executionResult); ok {
+				captured.result = result
 			}
+			if graphCtx.AfterExecute != nil {
+				graphCtx.AfterExecute(ctx, result)
+			}
+		}
+	}
+	return handler.New(config)
+}
 
-			return rootValue
-		},
-	})
-
-	return h, nil
+// beforeExecute runs the BeforeExecute hook and returns the request carrying the
+// context it returned
+func beforeExecute(graphCtx *GraphContext, r *http.Request, params *graphql.Params) *http.Request {
+	if graphCtx.BeforeExecute == nil {
+		return r
+	}
+	params.Context = r.Context()
+	if ctx := graphCtx.BeforeExecute(r.Context(), params); ctx != nil && ctx != r.Context() {
+		r = r.WithContext(ctx)
+	}
+	return r
 }
 
 // NewHTTP creates a standard http.HandlerFunc with built-in validation and sanitization support.
@@ -357,16 +543,38 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 		graphCtx = &GraphContext{DEBUG: true, Playground: true}
 	}
 
-	// Build handler (panic if schema building fails)
-	h, err := New(*graphCtx)
+	// Build schema (panic if schema building fails)
+	schema, err := buildSchemaFromContext(graphCtx)
 	if err != nil {
 		panic("failed to build GraphQL schema: " + err.Error())
 	}
 
-	// Get the built schema for validation
-	schema, err := buildSchemaFromContext(graphCtx)
-	if err != nil {
-		panic("failed to build GraphQL schema: " + err.Error())
+	rootObjectFn := buildRootObjectFn(graphCtx)
+	h := newHandler(graphCtx, schema, rootObjectFn)
+
+	// Print the SDL once, the schema doesn't change after the handler is built
+	var sdl string
+	if graphCtx.SDLPath != "" {
+		sdl = PrintSchema(schema)
+	}
+	var jsonSchema []byte
+	if graphCtx.JSONSchemaPath != "" {
+		jsonSchema, _ = json.MarshalIndent(ExportJSONSchema(schema), "", "  ")
+	}
+
+	accessLogger := graphCtx.Logger
+	if accessLogger == nil {
+		accessLogger = slog.Default()
+	}
+
+	if graphCtx.CORS != nil && graphCtx.CORS.AllowCredentials && graphCtx.CORS.allowsAnyOrigin() {
+		accessLogger.Warn(`CORS allows any origin ("*") with credentials; credentials are only allowed for the other AllowedOrigins`)
+	}
+
+	// Open Playground on a subscription example when subscriptions are enabled
+	playgroundQuery := graphCtx.PlaygroundSubscriptionQuery
+	if playgroundQuery == "" && graphCtx.EnableSubscriptions {
+		playgroundQuery = defaultSubscriptionQuery(schema)
 	}
 
 	// Create WebSocket handler if subscriptions are enabled
@@ -379,6 +587,13 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 			AuthFn:  createWebSocketAuthFn(graphCtx),
 			CheckOrigin: graphCtx.WebSocketCheckOrigin,
 			RootObjectFn: graphCtx.RootObjectFn,
+			SubscriptionDefaults: SubscriptionDefaults{
+				BufferSize:     graphCtx.SubscriptionBufferSize,
+				OverflowPolicy: graphCtx.SubscriptionOverflowPolicy,
+				Metrics:        graphCtx.SubscriptionMetrics,
+			},
+			CoalesceSubscriptions: graphCtx.CoalesceSubscriptions,
+			MaxSubscriptionsPerConnection: graphCtx.MaxSubscriptionsPerConnection,
 		}
 		wsHandler = NewWebSocketHandler(wsParams)
 	}
@@ -386,6 +601,10 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Check if this is a WebSocket upgrade request
 		if graphCtx.EnableSubscriptions && r.Header.Get("Upgrade") == "websocket" {
+			if err := subscriptionsReady(r.Context(), graphCtx.PubSub); err != nil {
+				writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+				return
+			}
 			if wsHandler != nil {
 				wsHandler(w, r)
 			} else {
@@ -394,6 +613,35 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 			return
 		}
 
+		// Record an access log line once the request completes
+		var accessEntry *accessLogEntry
+		if graphCtx.AccessLog {
+			accessEntry = &accessLogEntry{method: r.Method, path: r.URL.Path, start: time.Now()}
+			accessWriter := &accessLogWriter{ResponseWriter: w}
+			w = accessWriter
+			defer func() {
+				logAccess(r.Context(), accessLogger, accessEntry, accessWriter)
+			}()
+		}
+
+		// Add CORS headers and answer preflight requests
+		if handleCORS(w, r, graphCtx.CORS) {
+			return
+		}
+
+		// Compress responses for clients accepting gzip or deflate
+		if graphCtx.EnableCompression {
+			if compressWriter := newCompressResponseWriter(w, r, graphCtx.CompressionThreshold); compressWriter != nil {
+				w = compressWriter
+				defer compressWriter.Close()
+			}
+		}
+
+		if graphCtx.Playground && graphCtx.EnableSubscriptions && wantsPlayground(r) {
+			renderSubscriptionPlayground(w, r, graphCtx, playgroundQuery)
+			return
+		}
+
 		// Call UserDetailsFn to potentially update context
 		// This allows UserDetailsFn to add values to context accessible via p.Context.Value()
 		token := extractToken(r, graphCtx.TokenExtractorFn)
@@ -403,9 +651,114 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 			r = r.WithContext(result.ctx)
 		}
 
+		// Install request-scoped loaders. Loads still scheduled when the response completes
+		// are flushed with the request context, then the loaders are discarded
+		loaders := NewLoaderRegistry()
+		defer func() {
+			loaders.Flush(context.WithoutCancel(r.Context()))
+			loaders.Clear()
+		}()
+		r = r.WithContext(WithLoaderRegistry(r.Context(), loaders))
+
+		// Values cached by WithRequestCachedField live as long as the request
+		r = r.WithContext(WithRequestCache(r.Context(), NewRequestCache()))
+
+		// Let nested fields observe contexts derived by ContextMiddleware
+		r = r.WithContext(WithFieldContexts(r.Context()))
+
+		// Attach stack traces to resolver errors while debugging
+		if graphCtx.DEBUG && graphCtx.IncludeStackTraceInDebug {
+			r = r.WithContext(context.WithValue(r.Context(), errorStacksKey{}, true))
+		}
+
+		// Let resolvers localize their output with LanguageFromContext
+		if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+			r = r.WithContext(WithAcceptLanguage(r.Context(), acceptLanguage))
+		}
+
+		// Sanitize the string arguments of every field without its own sanitization
+		if graphCtx.StringSanitization != nil {
+			r = r.WithContext(withStringSanitization(r.Context(), *graphCtx.StringSanitization))
+		}
+
+		// Bound the request, answering 408 when the deadline passes before it completes
+		if graphCtx.RequestTimeout > 0 {
+			var finish func()
+			w, r, finish = withRequestTimeout(w, r, graphCtx.RequestTimeout)
+			defer finish()
+		}
+
+		// Keep the raw variables so resolvers can tell explicit nulls from omitted fields
+		payload := readRequestPayload(r)
+		if payload.Variables != nil {
+			r = r.WithContext(WithRawVariables(r.Context(), payload.Variables))
+		}
+
+		// Subscriptions need a reachable PubSub; other operations are unaffected
+		if graphCtx.EnableSubscriptions && isSubscriptionOperation(payload) {
+			if err := subscriptionsReady(r.Context(), graphCtx.PubSub); err != nil {
+				writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+		}
+
+		if accessEntry != nil {
+			accessEntry.operationName = operationName(payload)
+			accessEntry.userID = userIDFromDetails(result.details)
+		}
+
+		// Serve the schema SDL if requested and introspection is allowed
+		if graphCtx.SDLPath != "" && r.URL.Path == graphCtx.SDLPath && r.Method == http.MethodGet {
+			if !introspectionAllowed(graphCtx, schema, result.details) {
+				writeJSONError(w, http.StatusForbidden, "GraphQL introspection is disabled")
+				return
+			}
+			w.Header().Set("Content-Type", "application/graphql; charset=utf-8")
+			_, _ = io.WriteString(w, sdl)
+			return
+		}
+
+		// Serve the argument constraints as JSON Schema, under the same policy as the SDL
+		if graphCtx.JSONSchemaPath != "" && r.URL.Path == graphCtx.JSONSchemaPath && r.Method == http.MethodGet {
+			if !introspectionAllowed(graphCtx, schema, result.details) {
+				writeJSONError(w, http.StatusForbidden, "GraphQL introspection is disabled")
+				return
+			}
+			w.Header().Set("Content-Type", "application/schema+json")
+			_, _ = w.Write(jsonSchema)
+			return
+		}
+
+		// Multipart requests carry file uploads and are executed directly
+		if isMultipartRequest(r) {
+			serveMultipart(w, r, graphCtx, schema, rootObjectFn, result.details)
+			return
+		}
+
+		// A JSON array body is a batch of operations, executed one by one
+		if operations, isBatch, err := readBatchPayload(r); isBatch {
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			serveBatch(w, r, graphCtx, schema, rootObjectFn, result.details, operations)
+			return
+		}
+
+		if graphCtx.EchoCostHeader {
+			setCostHeader(w, graphCtx, schema, payload.Query, payload.Variables)
+		}
+
+		executeParams := &graphql.Params{
+			Schema:         *schema,
+			RequestString:  payload.Query,
+			VariableValues: payload.Variables,
+			OperationName:  payload.OperationName,
+		}
+
 		// Skip validation and sanitization in DEBUG mode
 		if graphCtx.DEBUG {
-			h.ServeHTTP(w, r)
+			serveGraphQL(h, w, beforeExecute(graphCtx, r, executeParams), graphCtx, nil)
 			return
 		}
 
@@ -442,79 +795,605 @@ func NewHTTP(graphCtx *GraphContext) http.HandlerFunc {
 		}
 
 		// Validate query if enabled
-		if query != "" {
-			// Determine which validation rules to use
-			var rules []ValidationRule
-			if len(graphCtx.ValidationRules) > 0 {
-				// Use custom validation rules (takes precedence)
-				rules = graphCtx.ValidationRules
-			} else if graphCtx.EnableValidation {
-				// Fall back to default security rules for backward compatibility
-				rules = SecurityRules
+		warnings, ok := validateRequest(w, r, graphCtx, schema, query, result.details)
+		if !ok {
+			return
+		}
+
+		serveGraphQL(h, w, beforeExecute(graphCtx, r, executeParams), graphCtx, warnings)
+	}
+}
+
+// executionResult receives the result of the operation executed by the graphql-go
+// handler, so its errors can be formatted with the ErrorFormatter
+type executionResult struct {
+	result *graphql.Result
+}
+
+// executionResultKey stores the *executionResult of a request in its context
+type executionResultKey struct{}
+
+// serveGraphQL executes the request with the graphql-go handler. The response is
+// buffered when its errors must be formatted or sanitized, or warnings added.
+func serveGraphQL(h *handler.Handler, w http.ResponseWriter, r *http.Request, graphCtx *GraphContext, warnings []*ValidationError) {
+	sanitize := graphCtx.EnableSanitization && !graphCtx.DEBUG
+	if !sanitize && len(warnings) == 0 && graphCtx.ErrorFormatter == nil && !graphCtx.OmitNullFields {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	// Wrap response writer to rewrite the response
+	wrapper := newResponseWriterWrapper(w)
+	captured := &executionResult{}
+	if graphCtx.ErrorFormatter != nil {
+		r = r.WithContext(context.WithValue(r.Context(), executionResultKey{}, captured))
+	}
+	h.ServeHTTP(wrapper, r)
+	if captured.result != nil {
+		wrapper.formatErrors(graphCtx.ErrorFormatter, captured.result)
+	}
+	wrapper.addWarnings(warnings)
+	if graphCtx.OmitNullFields {
+		wrapper.omitNullFields(graphCtx.Pretty)
+	}
+	if sanitize {
+		wrapper.sanitizeAndWrite()
+	} else {
+		wrapper.writeBody()
+	}
+}
+
+// requestPayload is the GraphQL operation carried by a GET or POST request
+type requestPayload struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+// readRequestPayload extracts the operation from a GET, JSON POST or application/graphql
+// POST request. The request body is restored for the graphql-go handler.
+func readRequestPayload(r *http.Request) requestPayload {
+	var payload requestPayload
+	switch {
+	case r.Method == http.MethodGet:
+		params := r.URL.Query()
+		payload.Query = params.Get("query")
+		payload.OperationName = params.Get("operationName")
+		if v := params.Get("variables"); v != "" {
+			_ = json.Unmarshal([]byte(v), &payload.Variables)
+		}
+	case r.Method == http.MethodPost && r.Body != nil && !isMultipartRequest(r):
+		bodyBytes, err := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			return payload
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql") {
+			payload.Query = string(bodyBytes)
+			return payload
+		}
+		var requestBody struct {
+			Query         string                 `json:"query"`
+			OperationName string                 `json:"operationName"`
+			Variables     map[string]interface{} `json:"variables"`
+		}
+		if err := json.Unmarshal(bodyBytes, &requestBody); err == nil {
+			payload = requestPayload(requestBody)
+		}
+	}
+	return payload
+}
+
+// isSubscriptionOperation reports whether the operation selected by a request is a subscription
+func isSubscriptionOperation(payload requestPayload) bool {
+	if payload.Query == "" {
+		return false
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: payload.Query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if payload.OperationName == "" || (op.Name != nil && op.Name.Value == payload.OperationName) {
+			return op.Operation == ast.OperationTypeSubscription
+		}
+	}
+	return false
+}
+
+// pubsubPingTimeout bounds the PubSub health check of a subscription request
+const pubsubPingTimeout = 2 * time.Second
+
+// subscriptionsReady returns an error describing why subscriptions cannot be served:
+// no PubSub is configured, or its health check fails
+func subscriptionsReady(ctx context.Context, pubsub PubSub) error {
+	if pubsub == nil {
+		return fmt.Errorf("subscriptions are unavailable: no PubSub is configured")
+	}
+	checker, ok := pubsub.(PubSubHealthChecker)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, pubsubPingTimeout)
+	defer cancel()
+	if err := checker.Ping(ctx); err != nil {
+		return fmt.Errorf("subscriptions are unavailable: %w", err)
+	}
+	return nil
+}
+
+// activeValidationRules returns the validation rules configured on the GraphContext
+func activeValidationRules(graphCtx *GraphContext) []ValidationRule {
+	if len(graphCtx.ValidationRules) > 0 {
+		// Use custom validation rules (takes precedence)
+		return graphCtx.ValidationRules
+	}
+	if graphCtx.EnableValidation {
+		// Fall back to default security rules for backward compatibility
+		if graphCtx.MaxDepth > 0 || graphCtx.MaxAliases > 0 || graphCtx.MaxComplexity > 0 {
+			return NewSecurityRules(QueryLimits{
+				MaxDepth:      graphCtx.MaxDepth,
+				MaxAliases:    graphCtx.MaxAliases,
+				MaxComplexity: graphCtx.MaxComplexity,
+			})
+		}
+		return SecurityRules
+	}
+	return nil
+}
+
+// introspectionAllowed reports whether the configured introspection rules allow
+// introspection for the user. It is used to guard the SDL endpoint with the same
+// policy that applies to __schema and __type queries.
+func introspectionAllowed(graphCtx *GraphContext, schema *graphql.Schema, userDetails interface{}) bool {
+	if graphCtx.DEBUG {
+		return true
+	}
+
+	var rules []ValidationRule
+	for _, rule := range activeValidationRules(graphCtx) {
+		if _, ok := rule.(*NoIntrospectionRule); ok {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return true
+	}
+
+	return ExecuteValidationRules("{ __schema { queryType { name } } }", schema, rules, userDetails, graphCtx.ValidationOptions) == nil
+}
+
+// validateRequest runs the configured validation rules against the query.
+// It writes a 400 response with the validation errors and returns false when validation fails.
+// The errors of warn-only rules are returned as warnings.
+func validateRequest(w http.ResponseWriter, r *http.Request, graphCtx *GraphContext, schema *graphql.Schema, query string, userDetails interface{}) ([]*ValidationError, bool) {
+	warnings, err := validateQuery(r, graphCtx, schema, query, userDetails)
+	if err == nil {
+		return warnings, true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(validationErrorResponse(err, graphCtx.ErrorFormatter))
+	return nil, false
+}
+
+// validateQuery runs the configured validation rules against the query and returns
+// the warnings of warn-only rules, or the validation error
+func validateQuery(r *http.Request, graphCtx *GraphContext, schema *graphql.Schema, query string, userDetails interface{}) ([]*ValidationError, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	// Execute validation if rules are configured
+	rules := activeValidationRules(graphCtx)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	variables, _ := r.Context().Value(rawVariablesKey{}).(map[string]interface{})
+	validationCtx := &ValidationContext{
+		Query:               query,
+		Schema:              schema,
+		Variables:           variables,
+		Request:             r,
+		UserDetails:         userDetails,
+		ComplexityEstimator: graphCtx.ComplexityEstimator,
+	}
+	if err := executeValidationRules(validationCtx, rules, graphCtx.ValidationOptions); err != nil {
+		return nil, err
+	}
+	return validationCtx.Warnings, nil
+}
+
+// validationErrorResponse formats a validation error as a GraphQL error response,
+// with the ErrorFormatter when one is set
+func validationErrorResponse(err error, formatter func(err error) map[string]interface{}) map[string]interface{} {
+	if formatter != nil {
+		errs := []error{err}
+		if multiErr, ok := err.(*MultiValidationError); ok {
+			errs = multiErr.Errors
+		}
+		formatted := make([]map[string]interface{}, 0, len(errs))
+		for _, e := range errs {
+			if entry := formatter(e); entry != nil {
+				formatted = append(formatted, entry)
 			}
+		}
+		return map[string]interface{}{
+			"errors": formatted,
+		}
+	}
 
-			// Execute validation if rules are configured
-			if len(rules) > 0 {
-				// Use user details from earlier UserDetailsFn call
-				userDetails := result.details
-
-				// Execute validation rules
-				if err := ExecuteValidationRules(query, schema, rules, userDetails, graphCtx.ValidationOptions); err != nil {
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusBadRequest)
-
-					// Format error response based on error type
-					var errorResponse map[string]interface{}
-					if multiErr, ok := err.(*MultiValidationError); ok {
-						// Multiple validation errors
-						var errors []map[string]interface{}
-						for _, e := range multiErr.Errors {
-							if validationErr, ok := e.(*ValidationError); ok {
-								errors = append(errors, map[string]interface{}{
-									"message": validationErr.Error(),
-									"rule":    validationErr.Rule,
-								})
-							} else {
-								errors = append(errors, map[string]interface{}{
-									"message": e.Error(),
-								})
-							}
-						}
-						errorResponse = map[string]interface{}{
-							"errors": errors,
-						}
-					} else if validationErr, ok := err.(*ValidationError); ok {
-						// Single validation error
-						errorResponse = map[string]interface{}{
-							"errors": []map[string]interface{}{
-								{
-									"message": validationErr.Message,
-									"rule":    validationErr.Rule,
-								},
-							},
-						}
-					} else {
-						// Generic error
-						errorResponse = map[string]interface{}{
-							"errors": []map[string]interface{}{
-								{"message": err.Error()},
-							},
-						}
-					}
+	// Format error response based on error type
+	if multiErr, ok := err.(*MultiValidationError); ok {
+		// Multiple validation errors
+		var errors []map[string]interface{}
+		for _, e := range multiErr.Errors {
+			if validationErr, ok := e.(*ValidationError); ok {
+				errors = append(errors, map[string]interface{}{
+					"message": validationErr.Error(),
+					"rule":    validationErr.Rule,
+				})
+			} else {
+				errors = append(errors, map[string]interface{}{
+					"message": e.Error(),
+				})
+			}
+		}
+		return map[string]interface{}{
+			"errors": errors,
+		}
+	} else if validationErr, ok := err.(*ValidationError); ok {
+		// Single validation error
+		return map[string]interface{}{
+			"errors": []map[string]interface{}{
+				{
+					"message": validationErr.Message,
+					"rule":    validationErr.Rule,
+				},
+			},
+		}
+	}
 
-					_ = json.NewEncoder(w).Encode(errorResponse)
-					return
-				}
+	// Generic error
+	return map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{"message": err.Error()},
+		},
+	}
+}
+
+// formatWarnings formats validation warnings like validation errors
+func formatWarnings(warnings []*ValidationError) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, len(warnings))
+	for i, warning := range warnings {
+		formatted[i] = map[string]interface{}{
+			"message": warning.Message,
+			"rule":    warning.Rule,
+		}
+	}
+	return formatted
+}
+
+// addWarnings adds validation warnings to extensions.warnings of the buffered response
+func (w *responseWriterWrapper) addWarnings(warnings []*ValidationError) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(w.body.Bytes(), &data); err != nil {
+		return
+	}
+	extensions, _ := data["extensions"].(map[string]interface{})
+	if extensions == nil {
+		extensions = make(map[string]interface{})
+	}
+	extensions["warnings"] = formatWarnings(warnings)
+	data["extensions"] = extensions
+
+	if body, err := json.Marshal(data); err == nil {
+		w.body.Reset()
+		w.body.Write(body)
+	}
+}
+
+// formatErrors replaces the errors of the buffered response with the errors of
+// result formatted by formatter
+func (w *responseWriterWrapper) formatErrors(formatter func(err error) map[string]interface{}, result *graphql.Result) {
+	if len(result.Errors) == 0 {
+		return
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(w.body.Bytes(), &data); err != nil {
+		return
+	}
+	if formatted := formatErrorsWith(formatter, result.Errors); len(formatted) > 0 {
+		data["errors"] = formatted
+	} else {
+		delete(data, "errors")
+	}
+
+	if body, err := json.Marshal(data); err == nil {
+		w.body.Reset()
+		w.body.Write(body)
+	}
+}
+
+// omitNullFields removes the null fields from the data of the buffered response
+func (w *responseWriterWrapper) omitNullFields(pretty bool) {
+	decoder := json.NewDecoder(bytes.NewReader(w.body.Bytes()))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil || data["data"] == nil {
+		return
+	}
+	data["data"] = omitNulls(data["data"])
+
+	var body []byte
+	var err error
+	if pretty {
+		body, err = json.MarshalIndent(data, "", "\t")
+	} else {
+		body, err = json.Marshal(data)
+	}
+	if err == nil {
+		w.body.Reset()
+		w.body.Write(body)
+	}
+}
+
+// omitNulls removes the null fields of the objects in value, recursively. Nulls in
+// lists are kept, since removing them would shift the other items.
+func omitNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if field == nil {
+				delete(v, key)
+				continue
 			}
+			v[key] = omitNulls(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = omitNulls(item)
+		}
+	}
+	return value
+}
+
+// writeBody writes the buffered response to the original writer
+func (w *responseWriterWrapper) writeBody() {
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// serveMultipart executes a GraphQL multipart (file upload) request.
+// The graphql-go handler cannot decode multipart bodies, so the operation is parsed
+// here, validated like any other request and executed directly against the schema.
+func serveMultipart(w http.ResponseWriter, r *http.Request, graphCtx *GraphContext, schema *graphql.Schema, rootObjectFn handler.RootObjectFn, userDetails interface{}) {
+	if graphCtx.MaxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, graphCtx.MaxUploadSize)
+	}
+	opts, uploads, err := parseMultipartRequest(r, defaultMultipartMemory)
+	defer func() {
+		// Files are closed before their temporary copies are removed
+		closeUploads(uploads)
+		if r.MultipartForm != nil {
+			_ = r.MultipartForm.RemoveAll()
+		}
+	}()
+	if err != nil {
+		if uploadTooLarge(err) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, uploadTooLargeMessage(graphCtx.MaxUploadSize))
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r = r.WithContext(WithRawVariables(r.Context(), opts.Variables))
+
+	var warnings []*ValidationError
+	if !graphCtx.DEBUG {
+		var ok bool
+		if warnings, ok = validateRequest(w, r, graphCtx, schema, opts.Query, userDetails); !ok {
+			return
 		}
+	}
+
+	if graphCtx.EchoCostHeader {
+		setCostHeader(w, graphCtx, schema, opts.Query, opts.Variables)
+	}
+
+	params := &graphql.Params{
+		Schema:         *schema,
+		RequestString:  opts.Query,
+		VariableValues: opts.Variables,
+		OperationName:  opts.OperationName,
+	}
+	r = beforeExecute(graphCtx, r, params)
+	params.RootObject = rootObjectFn(r.Context(), r)
+	params.Context = r.Context()
 
-		// Wrap response writer for sanitization if enabled
-		if graphCtx.EnableSanitization {
-			wrapper := newResponseWriterWrapper(w)
-			h.ServeHTTP(wrapper, r)
+	result := graphql.Do(*params)
+	formatResultErrors(result.Errors)
+	if len(warnings) > 0 {
+		if result.Extensions == nil {
+			result.Extensions = make(map[string]interface{})
+		}
+		result.Extensions["warnings"] = formatWarnings(warnings)
+	}
+	if graphCtx.AfterExecute != nil {
+		graphCtx.AfterExecute(r.Context(), result)
+	}
+	if graphCtx.OmitNullFields {
+		result.Data = omitNulls(result.Data)
+	}
+
+	sanitize := !graphCtx.DEBUG && graphCtx.EnableSanitization
+	if sanitize || graphCtx.ErrorFormatter != nil {
+		wrapper := newResponseWriterWrapper(w)
+		writeResult(wrapper, graphCtx, result)
+		if graphCtx.ErrorFormatter != nil {
+			wrapper.formatErrors(graphCtx.ErrorFormatter, result)
+		}
+		if sanitize {
 			wrapper.sanitizeAndWrite()
 		} else {
-			h.ServeHTTP(w, r)
+			wrapper.writeBody()
+		}
+		return
+	}
+	writeResult(w, graphCtx, result)
+}
+
+// costHeader is the response header carrying the estimated query cost
+const costHeader = "X-GraphQL-Cost"
+
+// setCostHeader sets the X-GraphQL-Cost header to the estimated cost of the query.
+// Queries that cannot be parsed or estimated get no header.
+func setCostHeader(w http.ResponseWriter, graphCtx *GraphContext, schema *graphql.Schema, query string, variables map[string]interface{}) {
+	if cost, ok := queryCost(graphCtx, schema, query, variables); ok {
+		w.Header().Set(costHeader, strconv.Itoa(cost))
+	}
+}
+
+// queryCost returns the estimated cost of a query, or false when the query cannot
+// be parsed or estimated
+func queryCost(graphCtx *GraphContext, schema *graphql.Schema, query string, variables map[string]interface{}) (int, bool) {
+	if query == "" {
+		return 0, false
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return 0, false
+	}
+	ctx := &ValidationContext{
+		Query:               query,
+		Document:            doc,
+		Schema:              schema,
+		Variables:           variables,
+		ComplexityEstimator: graphCtx.ComplexityEstimator,
+	}
+	cost, err := ctx.EstimateComplexity()
+	if err != nil {
+		return 0, false
+	}
+	return cost, true
+}
+
+// writeResult writes a GraphQL result as JSON, honoring the Pretty setting
+func writeResult(w http.ResponseWriter, graphCtx *GraphContext, result *graphql.Result) {
+	var body []byte
+	if graphCtx.Pretty {
+		body, _ = json.MarshalIndent(result, "", "\t")
+	} else {
+		body, _ = json.Marshal(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// withRequestTimeout cancels the context of the request after timeout. When the
+// deadline passes before the request wrote its response, 408 Request Timeout is
+// written at once and whatever the request writes afterwards is discarded; a response
+// already under way is left alone. finish must be called once the request is served.
+func withRequestTimeout(w http.ResponseWriter, r *http.Request, timeout time.Duration) (http.ResponseWriter, *http.Request, func()) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	tw := &timeoutWriter{w: w, ctx: ctx, header: make(http.Header), message: requestTimeoutMessage(timeout)}
+	stop := context.AfterFunc(ctx, func() {
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		if !tw.done {
+			tw.checkTimeoutLocked()
 		}
+	})
+	finish := func() {
+		tw.mu.Lock()
+		tw.done = true
+		tw.mu.Unlock()
+		stop()
+		cancel()
+	}
+	return tw, r.WithContext(ctx), finish
+}
+
+// timeoutWriter passes the response of a request through to w, unless the request
+// timed out before it started writing.
+type timeoutWriter struct {
+	w       http.ResponseWriter
+	ctx     context.Context
+	header  http.Header
+	message string
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+	done        bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeaderLocked(statusCode)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.writeHeaderLocked(http.StatusOK); tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return tw.w.Write(b)
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(statusCode int) {
+	if tw.checkTimeoutLocked() || tw.wroteHeader {
+		return
 	}
+	tw.wroteHeader = true
+	for key, values := range tw.header {
+		tw.w.Header()[key] = values
+	}
+	tw.w.WriteHeader(statusCode)
+}
+
+// checkTimeoutLocked writes the timeout error once the deadline has passed, unless the
+// response was already started, and reports whether the request timed out
+func (tw *timeoutWriter) checkTimeoutLocked() bool {
+	if !tw.timedOut && !tw.wroteHeader && errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		writeJSONError(tw.w, http.StatusRequestTimeout, tw.message)
+	}
+	return tw.timedOut
+}
+
+// requestTimeoutMessage is the error returned when a request exceeds RequestTimeout
+func requestTimeoutMessage(timeout time.Duration) string {
+	return fmt.Sprintf("request timed out after %s", timeout)
+}
+
+// writeJSONError writes a GraphQL-formatted error response with the given status code
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{"message": message},
+		},
+	})
 }
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// decodedInputKey is the context key for the input decoded by DecodeInput
+type decodedInputKey struct{}
+
+// DecodeInput returns a ContextMiddleware that decodes the input object argument of a
+// mutation into I once and stores the result in the context. Middleware, validators,
+// the resolver and nested field resolvers read the same *I with InputFromContext, and
+// WithTypedResolver passes it to a resolver whose first parameter is I or *I instead
+// of decoding the argument again.
+//
+// argName is the name of the input argument and defaults to "input", matching
+// WithInputObject. Add the middleware before the middleware that read the input.
+//
+// Example:
+//
+//	NewResolver[User]("createUser").
+//	    WithInputObject(CreateUserInput{}).
+//	    WithContextMiddleware(graph.DecodeInput[CreateUserInput]()).
+//	    WithMiddleware(func(next graph.FieldResolveFn) graph.FieldResolveFn {
+//	        return func(p graph.ResolveParams) (interface{}, error) {
+//	            input, _ := graph.InputFromContext[CreateUserInput](p.Context)
+//	            if input.Email == "" {
+//	                return nil, errors.New("email is required")
+//	            }
+//	            return next(p)
+//	        }
+//	    }).
+//	    WithResolver(func(p graph.ResolveParams) (*User, error) {
+//	        input, _ := graph.InputFromContext[CreateUserInput](p.Context)
+//	        return users.Create(p.Context, input)
+//	    }).
+//	    BuildMutation()
+func DecodeInput[I any](argName ...string) ContextMiddleware {
+	name := "input"
+	if len(argName) > 0 && argName[0] != "" {
+		name = argName[0]
+	}
+	return func(p ResolveParams) (context.Context, error) {
+		inputData, exists := p.Args[name]
+		if !exists {
+			return nil, nil
+		}
+		input := new(I)
+		if err := mapstructure.Decode(inputData, input); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return context.WithValue(ctx, decodedInputKey{}, input), nil
+	}
+}
+
+// InputFromContext returns the input decoded by DecodeInput. It returns false when no
+// input was decoded or it was decoded into a different type.
+func InputFromContext[I any](ctx context.Context) (*I, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	input, ok := ctx.Value(decodedInputKey{}).(*I)
+	return input, ok
+}
+
+// decodedInputValue returns the input decoded by DecodeInput as a value of paramType,
+// either the decoded type or a pointer to it
+func decodedInputValue(ctx context.Context, paramType reflect.Type) (reflect.Value, bool) {
+	if ctx == nil {
+		return reflect.Value{}, false
+	}
+	input := ctx.Value(decodedInputKey{})
+	if input == nil {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(input)
+	switch paramType {
+	case v.Type():
+		return v, true
+	case v.Type().Elem():
+		return v.Elem(), true
+	}
+	return reflect.Value{}, false
+}
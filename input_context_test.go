@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type InputContextTestInput struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type InputContextTestUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestDecodeInput(t *testing.T) {
+	var validated, resolved, typed *InputContextTestInput
+
+	validate := func(next FieldResolveFn) FieldResolveFn {
+		return func(p ResolveParams) (interface{}, error) {
+			input, ok := InputFromContext[InputContextTestInput](p.Context)
+			if !ok {
+				return nil, errors.New("input not decoded")
+			}
+			if input.Email == "" {
+				return nil, errors.New("email is required")
+			}
+			validated = input
+			return next(p)
+		}
+	}
+
+	create := NewResolver[InputContextTestUser]("inputContextCreateUser").
+		WithInputObject(InputContextTestInput{}).
+		WithContextMiddleware(DecodeInput[InputContextTestInput]()).
+		WithMiddleware(validate).
+		WithResolver(func(p ResolveParams) (*InputContextTestUser, error) {
+			input, _ := InputFromContext[InputContextTestInput](p.Context)
+			resolved = input
+			return &InputContextTestUser{Name: input.Name, Email: input.Email}, nil
+		}).
+		BuildMutation()
+
+	register := NewResolver[InputContextTestUser]("inputContextRegisterUser").
+		WithInputObjectFieldName("user").
+		WithInputObject(InputContextTestInput{}).
+		WithContextMiddleware(DecodeInput[InputContextTestInput]("user")).
+		WithMiddleware(validate).
+		WithTypedResolver(func(input *InputContextTestInput) (*InputContextTestUser, error) {
+			typed = input
+			return &InputContextTestUser{Name: input.Name, Email: input.Email}, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{getDefaultHelloQuery()},
+		MutationFields: []MutationField{create, register},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	t.Run("middleware and resolver share the decoded input", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `mutation { inputContextCreateUser(input: {name: "Ada", email: "ada@example.com"}) { name email } }`,
+			Context:       WithFieldContexts(context.Background()),
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if validated == nil || validated != resolved {
+			t.Fatalf("Expected the validator and resolver to read the same instance, got %p and %p", validated, resolved)
+		}
+		if resolved.Name != "Ada" || resolved.Email != "ada@example.com" {
+			t.Errorf("Unexpected decoded input %+v", resolved)
+		}
+	})
+
+	t.Run("typed resolver receives the decoded input", func(t *testing.T) {
+		validated = nil
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `mutation { inputContextRegisterUser(user: {name: "Grace", email: "grace@example.com"}) { name } }`,
+			Context:       WithFieldContexts(context.Background()),
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if validated == nil || validated != typed {
+			t.Fatalf("Expected the validator and typed resolver to read the same instance, got %p and %p", validated, typed)
+		}
+	})
+
+	t.Run("validation failure skips the resolver", func(t *testing.T) {
+		resolved = nil
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `mutation { inputContextCreateUser(input: {name: "Ada"}) { name } }`,
+			Context:       WithFieldContexts(context.Background()),
+		})
+		if len(result.Errors) == 0 || result.Errors[0].Message != "email is required" {
+			t.Fatalf("Expected validation error, got %v", result.Errors)
+		}
+		if resolved != nil {
+			t.Error("Expected the resolver not to run")
+		}
+	})
+}
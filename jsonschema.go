@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// jsonSchemaDialect is the JSON Schema version of the documents built by ExportJSONSchema
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema builds the schema and returns the constraints of its arguments and input
+// types as a JSON Schema document. See ExportJSONSchema for the document layout.
+//
+// Example:
+//
+//	doc, err := graph.NewSchemaBuilder(params).JSONSchema()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("schema.json", doc, 0644)
+func (sb *SchemaBuilder) JSONSchema() ([]byte, error) {
+	schema, err := sb.Build()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(ExportJSONSchema(&schema), "", "  ")
+}
+
+// ExportJSONSchema describes the arguments of every root field and the input types they
+// use as a JSON Schema document, so client form generators can enforce the same rules
+// as the server: required arguments and input fields, types, enum values, defaults,
+// descriptions and the formats of the built-in scalars (Email, URL, UUID, DateTime).
+//
+// Root fields are grouped by operation type, and input objects and enums are listed
+// under $defs and referenced by name. Fields without arguments are omitted.
+//
+//	{
+//	  "$schema": "https://json-schema.org/draft/2020-12/schema",
+//	  "type": "object",
+//	  "properties": {
+//	    "Mutation": {
+//	      "type": "object",
+//	      "properties": {
+//	        "createUser": {
+//	          "type": "object",
+//	          "properties": {"input": {"$ref": "#/$defs/CreateUserInput"}},
+//	          "required": ["input"],
+//	          "additionalProperties": false
+//	        }
+//	      }
+//	    }
+//	  },
+//	  "$defs": {
+//	    "CreateUserInput": {...}
+//	  }
+//	}
+func ExportJSONSchema(schema *graphql.Schema) map[string]interface{} {
+	defs := make(map[string]interface{})
+	operations := make(map[string]interface{})
+	for _, root := range []*graphql.Object{schema.QueryType(), schema.MutationType(), schema.SubscriptionType()} {
+		if root == nil {
+			continue
+		}
+		fields := make(map[string]interface{})
+		for name, field := range root.Fields() {
+			if len(field.Args) == 0 {
+				continue
+			}
+			properties := make(map[string]interface{}, len(field.Args))
+			var required []string
+			for _, arg := range field.Args {
+				properties[arg.Name()] = jsonSchemaValue(arg.Type, arg.Description(), arg.DefaultValue, defs)
+				if _, nonNull := arg.Type.(*graphql.NonNull); nonNull && arg.DefaultValue == nil {
+					required = append(required, arg.Name())
+				}
+			}
+			fields[name] = jsonSchemaObject(field.Description, properties, required)
+		}
+		if len(fields) > 0 {
+			operations[root.Name()] = map[string]interface{}{
+				"type":       "object",
+				"properties": fields,
+			}
+		}
+	}
+
+	doc := map[string]interface{}{
+		"$schema":    jsonSchemaDialect,
+		"type":       "object",
+		"properties": operations,
+	}
+	if len(defs) > 0 {
+		doc["$defs"] = defs
+	}
+	return doc
+}
+
+// jsonSchemaObject returns the schema of an object with the given properties
+func jsonSchemaObject(description string, properties map[string]interface{}, required []string) map[string]interface{} {
+	object := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		object["required"] = required
+	}
+	if description != "" {
+		object["description"] = description
+	}
+	return object
+}
+
+// jsonSchemaValue returns the schema of an argument or input field
+func jsonSchemaValue(t graphql.Input, description string, defaultValue interface{}, defs map[string]interface{}) map[string]interface{} {
+	value := jsonSchemaType(t, defs)
+	if description != "" {
+		value["description"] = description
+	}
+	if defaultValue != nil {
+		value["default"] = jsonSchemaDefault(defaultValue, t)
+	}
+	return value
+}
+
+// jsonSchemaType returns the schema of an input type, adding the input objects and
+// enums it references to defs
+func jsonSchemaType(t graphql.Input, defs map[string]interface{}) map[string]interface{} {
+	switch typ := t.(type) {
+	case *graphql.NonNull:
+		return jsonSchemaType(typ.OfType.(graphql.Input), defs)
+
+	case *graphql.List:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": jsonSchemaType(typ.OfType.(graphql.Input), defs),
+		}
+
+	case *graphql.Enum:
+		if _, exists := defs[typ.Name()]; !exists {
+			names := make([]string, 0, len(typ.Values()))
+			for _, value := range typ.Values() {
+				names = append(names, value.Name)
+			}
+			sort.Strings(names)
+			def := map[string]interface{}{"type": "string", "enum": names}
+			if typ.Description() != "" {
+				def["description"] = typ.Description()
+			}
+			defs[typ.Name()] = def
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + typ.Name()}
+
+	case *graphql.InputObject:
+		if _, exists := defs[typ.Name()]; !exists {
+			// Reserve the name first so recursive input types terminate
+			defs[typ.Name()] = nil
+			fields := typ.Fields()
+			properties := make(map[string]interface{}, len(fields))
+			var required []string
+			for name, field := range fields {
+				properties[name] = jsonSchemaValue(field.Type, field.Description(), field.DefaultValue, defs)
+				if _, nonNull := field.Type.(*graphql.NonNull); nonNull && field.DefaultValue == nil {
+					required = append(required, name)
+				}
+			}
+			defs[typ.Name()] = jsonSchemaObject(typ.Description(), properties, required)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + typ.Name()}
+
+	case *graphql.Scalar:
+		return jsonSchemaScalar(typ)
+	}
+	return map[string]interface{}{}
+}
+
+// jsonSchemaScalar returns the schema of a built-in or custom scalar. Custom scalars
+// accept any JSON value and are identified by their title.
+func jsonSchemaScalar(scalar *graphql.Scalar) map[string]interface{} {
+	switch scalar.Name() {
+	case "Int", "Int64":
+		return map[string]interface{}{"type": "integer"}
+	case "Float":
+		return map[string]interface{}{"type": "number"}
+	case "Boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case "String":
+		return map[string]interface{}{"type": "string"}
+	case "ID":
+		return map[string]interface{}{"type": []string{"string", "integer"}}
+	case "Email":
+		return map[string]interface{}{"type": "string", "format": "email"}
+	case "URL":
+		return map[string]interface{}{"type": "string", "format": "uri"}
+	case "UUID":
+		return map[string]interface{}{"type": "string", "format": "uuid", "pattern": uuidPattern.String()}
+	case "DateTime":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	return map[string]interface{}{"title": scalar.Name()}
+}
+
+// jsonSchemaDefault converts a default value to its JSON form. Default values declared
+// through struct tags are strings, so they are converted like printValue does for SDL,
+// and enum values are given by name.
+func jsonSchemaDefault(value interface{}, t graphql.Input) interface{} {
+	literal := printValue(value, t)
+	named := t
+	if nonNull, ok := named.(*graphql.NonNull); ok {
+		named = nonNull.OfType.(graphql.Input)
+	}
+	if _, isEnum := named.(*graphql.Enum); isEnum {
+		return literal
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(literal), &decoded); err == nil {
+		return decoded
+	}
+	return value
+}
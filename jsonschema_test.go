@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type jsonSchemaTestRole string
+
+type JSONSchemaTestAddress struct {
+	City    string `json:"city" graphql:"city,required"`
+	Country string `json:"country" default:"TZ"`
+}
+
+type JSONSchemaTestCreateUser struct {
+	Name    string                `json:"name" graphql:"name,required" description:"Full name"`
+	Contact string                `json:"contact" graphql:"contact,required,email"`
+	Role    jsonSchemaTestRole    `json:"role"`
+	Address JSONSchemaTestAddress `json:"address"`
+	Tags    []string              `json:"tags"`
+}
+
+type JSONSchemaTestUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type JSONSchemaTestUserFilter struct {
+	Search string `json:"search"`
+	Limit  int    `json:"limit" default:"10"`
+	Active bool   `json:"active" graphql:"active,required"`
+}
+
+func jsonSchemaTestBuilder() *SchemaBuilder {
+	RegisterEnum("JSONSchemaTestRole", map[string]jsonSchemaTestRole{
+		"ADMIN":  "admin",
+		"MEMBER": "member",
+	})
+
+	users := NewResolver[JSONSchemaTestUser]("jsonSchemaTestUsers").
+		AsList().
+		WithArgsFromStruct(JSONSchemaTestUserFilter{}).
+		WithResolver(func(p ResolveParams) (*JSONSchemaTestUser, error) {
+			return nil, nil
+		}).
+		BuildQuery()
+	createUser := NewResolver[JSONSchemaTestUser]("jsonSchemaTestCreateUser").
+		WithInputObject(JSONSchemaTestCreateUser{}).
+		WithResolver(func(p ResolveParams) (*JSONSchemaTestUser, error) {
+			return nil, nil
+		}).
+		BuildMutation()
+
+	return NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{getDefaultHelloQuery(), users},
+		MutationFields: []MutationField{createUser},
+	})
+}
+
+// jsonPath returns the value at the given keys of a decoded JSON document
+func jsonPath(t *testing.T, doc interface{}, keys ...string) interface{} {
+	t.Helper()
+	for _, key := range keys {
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected an object at %q, got %v", key, doc)
+		}
+		doc = object[key]
+	}
+	return doc
+}
+
+func TestSchemaBuilder_JSONSchema(t *testing.T) {
+	data, err := jsonSchemaTestBuilder().JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %s", data)
+	}
+
+	if doc["$schema"] != jsonSchemaDialect {
+		t.Errorf("Unexpected $schema %v", doc["$schema"])
+	}
+	if hello := jsonPath(t, doc, "properties", "Query", "properties", "hello"); hello != nil {
+		t.Errorf("Expected fields without arguments to be omitted, got %v", hello)
+	}
+
+	users := jsonPath(t, doc, "properties", "Query", "properties", "jsonSchemaTestUsers")
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"query required", jsonPath(t, users, "required"), []interface{}{"active"}},
+		{"query default", jsonPath(t, users, "properties", "limit", "default"), float64(10)},
+		{"query int", jsonPath(t, users, "properties", "limit", "type"), "integer"},
+		{"query boolean", jsonPath(t, users, "properties", "active", "type"), "boolean"},
+		{"input ref", jsonPath(t, doc, "properties", "Mutation", "properties", "jsonSchemaTestCreateUser", "properties", "input", "$ref"), "#/$defs/JSONSchemaTestCreateUserInput"},
+		{"input required", jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput", "required"), []interface{}{"contact", "name"}},
+		{"input description", jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput", "properties", "name", "description"), "Full name"},
+		{"scalar format", jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput", "properties", "contact", "format"), "email"},
+		{"enum ref", jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput", "properties", "role", "$ref"), "#/$defs/JSONSchemaTestRole"},
+		{"enum values", jsonPath(t, doc, "$defs", "JSONSchemaTestRole", "enum"), []interface{}{"ADMIN", "MEMBER"}},
+		{"list items", jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput", "properties", "tags", "items", "type"), "string"},
+		{"nested required", jsonPath(t, doc, "$defs", "JSONSchemaTestAddress", "required"), []interface{}{"city"}},
+		{"nested default", jsonPath(t, doc, "$defs", "JSONSchemaTestAddress", "properties", "country", "default"), "TZ"},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestNewHTTP_JSONSchemaPath(t *testing.T) {
+	builder := jsonSchemaTestBuilder()
+	schema, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	serve := func(rules []ValidationRule) *httptest.ResponseRecorder {
+		handler := NewHTTP(&GraphContext{
+			Schema:          &schema,
+			JSONSchemaPath:  "/graphql/schema.json",
+			ValidationRules: rules,
+		})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql/schema.json", nil))
+		return w
+	}
+
+	w := serve(nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/schema+json" {
+		t.Errorf("Expected application/schema+json content type, got %s", ct)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a JSON document, got %s", w.Body.String())
+	}
+	if jsonPath(t, doc, "$defs", "JSONSchemaTestCreateUserInput") == nil {
+		t.Errorf("Expected the input type in $defs, got %s", w.Body.String())
+	}
+
+	if w := serve([]ValidationRule{NewNoIntrospectionRule()}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 when introspection is disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+// languagesKey stores the languages of the Accept-Language header in the request context
+type languagesKey struct{}
+
+// WithAcceptLanguage returns a context carrying the languages of an Accept-Language
+// header, ordered by preference. NewHTTP adds them for every request; use it when
+// executing queries with graphql.Do. Invalid headers add no languages.
+func WithAcceptLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return ctx
+	}
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		languages[i] = tag.String()
+	}
+	return context.WithValue(ctx, languagesKey{}, languages)
+}
+
+// LanguageFromContext returns the language the client prefers, such as "en-US", from
+// the Accept-Language header of the request. It returns false when the request has no
+// Accept-Language header.
+//
+// Example:
+//
+//	WithResolver(func(p graph.ResolveParams) (*Greeting, error) {
+//	    if lang, ok := graph.LanguageFromContext(p.Context); ok && strings.HasPrefix(lang, "fr") {
+//	        return &Greeting{Text: "Bonjour"}, nil
+//	    }
+//	    return &Greeting{Text: "Hello"}, nil
+//	})
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	languages := LanguagesFromContext(ctx)
+	if len(languages) == 0 {
+		return "", false
+	}
+	return languages[0], true
+}
+
+// LanguagesFromContext returns the languages of the Accept-Language header of the
+// request, most preferred first, for matching against the languages you support
+func LanguagesFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	languages, _ := ctx.Value(languagesKey{}).([]string)
+	return languages
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{name: "ordered by quality", header: "fr;q=0.8, en-us, de;q=0.9", want: []string{"en-US", "de", "fr"}},
+		{name: "single", header: "sw", want: []string{"sw"}},
+		{name: "invalid", header: "en;q=x;;", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithAcceptLanguage(context.Background(), tt.header)
+			if got := LanguagesFromContext(ctx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LanguagesFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := LanguageFromContext(context.Background()); ok {
+		t.Error("Expected no language without an Accept-Language header")
+	}
+}
+
+func TestNewHTTP_AcceptLanguage(t *testing.T) {
+	greeting := NewResolver[string]("languageTestGreeting").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			text := "Hello"
+			if lang, ok := LanguageFromContext(p.Context); ok && strings.HasPrefix(lang, "fr") {
+				text = "Bonjour"
+			}
+			return &text, nil
+		}).
+		BuildQuery()
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{greeting}},
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{acceptLanguage: "fr-CH, fr;q=0.9, en;q=0.8", want: "Bonjour"},
+		{acceptLanguage: "en-GB", want: "Hello"},
+		{acceptLanguage: "", want: "Hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"query": "{ languageTestGreeting }"})
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got := response.Data["languageTestGreeting"]; got != tt.want {
+				t.Errorf("Expected %q, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// BatchFn loads the values for a batch of keys.
+// It must return exactly one value per key, in the same order as keys.
+type BatchFn[K comparable, V any] func(ctx context.Context, keys []K) ([]V, error)
+
+// DataLoader batches and caches loads by key to avoid N+1 queries in nested resolvers.
+// Keys requested through LoadThunk are collected until the first thunk is evaluated,
+// then loaded together with a single call to the batch function. Results are cached,
+// so a DataLoader should live for a single request; see RegisterLoader and
+// LoaderFromContext for request-scoped loaders installed automatically by NewHTTP.
+//
+// Example:
+//
+//	loader := graph.NewDataLoader(func(ctx context.Context, ids []int) ([]*Author, error) {
+//	    return authorRepo.FindByIDs(ctx, ids) // one query for all ids
+//	})
+//
+//	// In a field resolver, return the thunk so graphql-go resolves sibling fields first
+//	thunk := loader.LoadThunk(p.Context, book.AuthorID)
+//	return func() (interface{}, error) { return thunk() }, nil
+type DataLoader[K comparable, V any] struct {
+	batchFn BatchFn[K, V]
+	wait    time.Duration
+
+	mu             sync.Mutex
+	cache          map[K]*loaderEntry[V]
+	pendingKeys    []K
+	pendingEntries []*loaderEntry[V]
+}
+
+// loaderEntry holds the result of loading a single key
+type loaderEntry[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+// NewDataLoader creates a DataLoader that loads values with batchFn.
+func NewDataLoader[K comparable, V any](batchFn BatchFn[K, V]) *DataLoader[K, V] {
+	return &DataLoader[K, V]{
+		batchFn: batchFn,
+		cache:   make(map[K]*loaderEntry[V]),
+	}
+}
+
+// WithWait sets a wait window for Load. The first key scheduled after a dispatch starts
+// the window, and keys loaded by concurrent callers before it ends are dispatched in the
+// same batch. Without a wait window Load dispatches immediately. Thunks returned by
+// LoadThunk still dispatch as soon as the first one is called.
+//
+// Example:
+//
+//	loader := graph.NewDataLoader(fetchAuthors).WithWait(2 * time.Millisecond)
+func (l *DataLoader[K, V]) WithWait(d time.Duration) *DataLoader[K, V] {
+	l.wait = d
+	return l
+}
+
+// LoadThunk schedules key for loading and returns a function that returns its value.
+// The batch is dispatched when the first returned function is called, so all keys
+// scheduled before that point are loaded together.
+func (l *DataLoader[K, V]) LoadThunk(ctx context.Context, key K) func() (V, error) {
+	entry := l.enqueue(ctx, key)
+	return func() (V, error) {
+		select {
+		case <-entry.done:
+		default:
+			l.Flush(ctx)
+			<-entry.done
+		}
+		return entry.value, entry.err
+	}
+}
+
+// Load loads the value for key, dispatching it together with any keys already scheduled.
+// With a wait window (see WithWait) it waits for the window to end instead, or for ctx
+// to be done.
+func (l *DataLoader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if l.wait <= 0 {
+		return l.LoadThunk(ctx, key)()
+	}
+
+	entry := l.enqueue(ctx, key)
+	select {
+	case <-entry.done:
+		return entry.value, entry.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Flush dispatches all scheduled keys to the batch function.
+func (l *DataLoader[K, V]) Flush(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pendingKeys
+	entries := l.pendingEntries
+	l.pendingKeys = nil
+	l.pendingEntries = nil
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.callBatchFn(ctx, keys)
+	if err == nil && len(values) != len(keys) {
+		err = fmt.Errorf("batch function returned %d values for %d keys", len(values), len(keys))
+	}
+
+	for i, entry := range entries {
+		if err != nil {
+			entry.err = err
+		} else {
+			entry.value = values[i]
+		}
+		close(entry.done)
+	}
+}
+
+// Clear removes all cached values. Keys that are still scheduled are flushed first
+// so no caller is left waiting.
+func (l *DataLoader[K, V]) Clear() {
+	l.Flush(context.Background())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache = make(map[K]*loaderEntry[V])
+}
+
+// enqueue returns the cache entry for key, scheduling it for the next batch if it's new.
+// Scheduling the first key of a batch starts the wait window, if any.
+func (l *DataLoader[K, V]) enqueue(ctx context.Context, key K) *loaderEntry[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, exists := l.cache[key]; exists {
+		return entry
+	}
+
+	entry := &loaderEntry[V]{done: make(chan struct{})}
+	l.cache[key] = entry
+	l.pendingKeys = append(l.pendingKeys, key)
+	l.pendingEntries = append(l.pendingEntries, entry)
+	if l.wait > 0 && len(l.pendingKeys) == 1 {
+		time.AfterFunc(l.wait, func() { l.Flush(context.WithoutCancel(ctx)) })
+	}
+	return entry
+}
+
+// loadAny schedules a key given as interface{} and returns a thunk for its value
+func (l *DataLoader[K, V]) loadAny(ctx context.Context, key interface{}) (func() (interface{}, error), error) {
+	typedKey, ok := key.(K)
+	if !ok {
+		var zero K
+		return nil, fmt.Errorf("loader key has type %T, not %T", key, zero)
+	}
+	thunk := l.LoadThunk(ctx, typedKey)
+	return func() (interface{}, error) {
+		return thunk()
+	}, nil
+}
+
+// callBatchFn calls the batch function, converting a panic into an error
+// so waiting resolvers are always released
+func (l *DataLoader[K, V]) callBatchFn(ctx context.Context, keys []K) (values []V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("batch function panicked: %v", r)
+		}
+	}()
+	return l.batchFn(ctx, keys)
+}
+
+// requestLoader is the type-erased interface of a DataLoader held by a LoaderRegistry
+type requestLoader interface {
+	Flush(ctx context.Context)
+	Clear()
+	loadAny(ctx context.Context, key interface{}) (func() (interface{}, error), error)
+}
+
+// loaderFactories holds the loaders registered with RegisterLoader
+var (
+	loaderFactories   = make(map[string]func() requestLoader)
+	loaderFactoriesMu sync.RWMutex
+)
+
+// RegisterLoader registers a named loader definition. A fresh DataLoader is created
+// from batchFn for every request that uses it, so cached values are never shared
+// between requests. Register loaders once at startup.
+//
+// Example:
+//
+//	graph.RegisterLoader("authorByID", func(ctx context.Context, ids []int) ([]*Author, error) {
+//	    return authorRepo.FindByIDs(ctx, ids)
+//	})
+//
+//	// In a resolver
+//	loader, err := graph.LoaderFromContext[int, *Author](p.Context, "authorByID")
+//	if err != nil {
+//	    return nil, err
+//	}
+//	thunk := loader.LoadThunk(p.Context, book.AuthorID)
+//	return func() (interface{}, error) { return thunk() }, nil
+func RegisterLoader[K comparable, V any](name string, batchFn BatchFn[K, V]) {
+	loaderFactoriesMu.Lock()
+	defer loaderFactoriesMu.Unlock()
+	loaderFactories[name] = func() requestLoader {
+		return NewDataLoader(batchFn)
+	}
+}
+
+// LoaderRegistry holds the loaders of a single request. Loaders are created lazily
+// from their registered definitions the first time they are requested.
+// NewHTTP installs a LoaderRegistry into every request context automatically.
+type LoaderRegistry struct {
+	mu      sync.Mutex
+	loaders map[string]requestLoader
+}
+
+// NewLoaderRegistry creates an empty request loader registry.
+func NewLoaderRegistry() *LoaderRegistry {
+	return &LoaderRegistry{
+		loaders: make(map[string]requestLoader),
+	}
+}
+
+// loader returns the request's loader for name, creating it on first use
+func (r *LoaderRegistry) loader(name string) (requestLoader, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, exists := r.loaders[name]; exists {
+		return l, nil
+	}
+
+	loaderFactoriesMu.RLock()
+	factory, exists := loaderFactories[name]
+	loaderFactoriesMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("loader '%s' is not registered", name)
+	}
+
+	l := factory()
+	r.loaders[name] = l
+	return l, nil
+}
+
+// Flush dispatches the scheduled keys of all loaders of the request, releasing callers
+// still waiting for a batch to fill. NewHTTP flushes with the request context when the
+// response completes; call it after graphql.Do when installing a registry yourself.
+func (r *LoaderRegistry) Flush(ctx context.Context) {
+	r.mu.Lock()
+	loaders := make([]requestLoader, 0, len(r.loaders))
+	for _, l := range r.loaders {
+		loaders = append(loaders, l)
+	}
+	r.mu.Unlock()
+
+	for _, l := range loaders {
+		l.Flush(ctx)
+	}
+}
+
+// Clear flushes and discards all loaders of the request.
+func (r *LoaderRegistry) Clear() {
+	r.mu.Lock()
+	loaders := r.loaders
+	r.loaders = make(map[string]requestLoader)
+	r.mu.Unlock()
+
+	for _, l := range loaders {
+		l.Clear()
+	}
+}
+
+// loaderRegistryKey is the context key for the request's LoaderRegistry
+type loaderRegistryKey struct{}
+
+// WithLoaderRegistry returns a copy of ctx that carries the loader registry.
+// NewHTTP does this for every request; use it directly when executing queries
+// with graphql.Do.
+func WithLoaderRegistry(ctx context.Context, registry *LoaderRegistry) context.Context {
+	return context.WithValue(ctx, loaderRegistryKey{}, registry)
+}
+
+// LoaderFromContext returns the request-scoped DataLoader registered under name.
+// It returns an error if the context has no loader registry, if no loader is
+// registered under name, or if the registered loader has different key or value types.
+func LoaderFromContext[K comparable, V any](ctx context.Context, name string) (*DataLoader[K, V], error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("no loader registry in context")
+	}
+	registry, ok := ctx.Value(loaderRegistryKey{}).(*LoaderRegistry)
+	if !ok || registry == nil {
+		return nil, fmt.Errorf("no loader registry in context")
+	}
+
+	l, err := registry.loader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	loader, ok := l.(*DataLoader[K, V])
+	if !ok {
+		return nil, fmt.Errorf("loader '%s' has type %T, not %T", name, l, loader)
+	}
+	return loader, nil
+}
+
+// WithBatchedField resolves fieldName through the request-scoped loader registered under
+// loaderName (see RegisterLoader), fixing N+1 queries for lists. keyFn receives the parent
+// row (p.Source) and returns its loader key, which must have the loader's key type.
+// Every row's key is scheduled before the first value is needed, so all rows of a list
+// are loaded with a single call to the batch function.
+//
+// Example:
+//
+//	graph.RegisterLoader("advertByID", func(ctx context.Context, ids []int) ([]*Advert, error) {
+//	    return advertRepo.FindByIDs(ctx, ids)
+//	})
+//
+//	NewResolver[[]Interview]("interviews").
+//	    AsList().
+//	    WithBatchedField("advert", func(source interface{}) interface{} {
+//	        return source.(Interview).AdvertID
+//	    }, "advertByID").
+//	    WithResolver(listInterviews).
+//	    BuildQuery()
+func (r *UnifiedResolver[T]) WithBatchedField(fieldName string, keyFn func(source interface{}) interface{}, loaderName string) *UnifiedResolver[T] {
+	r.fieldOverrides[fieldName] = func(p graphql.ResolveParams) (interface{}, error) {
+		registry, ok := p.Context.Value(loaderRegistryKey{}).(*LoaderRegistry)
+		if !ok || registry == nil {
+			return nil, fmt.Errorf("no loader registry in context")
+		}
+		l, err := registry.loader(loaderName)
+		if err != nil {
+			return nil, err
+		}
+		return l.loadAny(p.Context, keyFn(p.Source))
+	}
+	return r
+}
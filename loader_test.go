@@ -0,0 +1,352 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestDataLoader_BatchesScheduledKeys(t *testing.T) {
+	var batches [][]int
+	loader := NewDataLoader(func(ctx context.Context, keys []int) ([]string, error) {
+		batches = append(batches, keys)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = fmt.Sprintf("value-%d", key)
+		}
+		return values, nil
+	})
+
+	ctx := context.Background()
+	thunks := []func() (string, error){
+		loader.LoadThunk(ctx, 1),
+		loader.LoadThunk(ctx, 2),
+		loader.LoadThunk(ctx, 1),
+		loader.LoadThunk(ctx, 3),
+	}
+
+	for i, want := range []string{"value-1", "value-2", "value-1", "value-3"} {
+		got, err := thunks[i]()
+		if err != nil {
+			t.Fatalf("thunk %d error = %v", i, err)
+		}
+		if got != want {
+			t.Errorf("thunk %d = %s, want %s", i, got, want)
+		}
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("Expected a single batch of 3 unique keys, got %v", batches)
+	}
+
+	// Cached keys don't trigger another batch
+	if _, err := loader.Load(ctx, 2); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(batches) != 1 {
+		t.Errorf("Expected cached load to skip the batch function, got %d batches", len(batches))
+	}
+}
+
+func TestDataLoader_BatchErrors(t *testing.T) {
+	loader := NewDataLoader(func(ctx context.Context, keys []int) ([]string, error) {
+		return []string{"only one"}, nil
+	})
+
+	ctx := context.Background()
+	first := loader.LoadThunk(ctx, 1)
+	second := loader.LoadThunk(ctx, 2)
+
+	if _, err := first(); err == nil {
+		t.Error("Expected error when batch function returns too few values")
+	}
+	if _, err := second(); err == nil {
+		t.Error("Expected error for every key in the failed batch")
+	}
+}
+
+func TestLoaderFromContext_Errors(t *testing.T) {
+	RegisterLoader("loaderTestTyped", func(ctx context.Context, keys []int) ([]string, error) {
+		return make([]string, len(keys)), nil
+	})
+
+	if _, err := LoaderFromContext[int, string](context.Background(), "loaderTestTyped"); err == nil {
+		t.Error("Expected error without a loader registry in context")
+	}
+
+	ctx := WithLoaderRegistry(context.Background(), NewLoaderRegistry())
+	if _, err := LoaderFromContext[int, string](ctx, "loaderTestMissing"); err == nil {
+		t.Error("Expected error for an unregistered loader")
+	}
+	if _, err := LoaderFromContext[string, string](ctx, "loaderTestTyped"); err == nil {
+		t.Error("Expected error for mismatched loader types")
+	}
+	if _, err := LoaderFromContext[int, string](ctx, "loaderTestTyped"); err != nil {
+		t.Errorf("LoaderFromContext() error = %v", err)
+	}
+}
+
+func TestNewHTTP_RequestScopedLoaders(t *testing.T) {
+	type LoaderBook struct {
+		ID       int    `json:"id"`
+		AuthorID int    `json:"authorId"`
+		Title    string `json:"title"`
+	}
+
+	var mu sync.Mutex
+	var batches [][]int
+	RegisterLoader("loaderTestAuthorName", func(ctx context.Context, ids []int) ([]string, error) {
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+		names := make([]string, len(ids))
+		for i, id := range ids {
+			names[i] = fmt.Sprintf("author-%d", id)
+		}
+		return names, nil
+	})
+
+	books := NewResolver[[]LoaderBook]("loaderBooks").
+		AsList().
+		WithComputedField("authorName", graphql.String, func(p graphql.ResolveParams) (interface{}, error) {
+			loader, err := LoaderFromContext[int, string](p.Context, "loaderTestAuthorName")
+			if err != nil {
+				return nil, err
+			}
+			book := p.Source.(LoaderBook)
+			thunk := loader.LoadThunk(p.Context, book.AuthorID)
+			return func() (interface{}, error) { return thunk() }, nil
+		}).
+		WithResolver(func(p ResolveParams) (*[]LoaderBook, error) {
+			books := []LoaderBook{
+				{ID: 1, AuthorID: 10, Title: "A"},
+				{ID: 2, AuthorID: 20, Title: "B"},
+				{ID: 3, AuthorID: 10, Title: "C"},
+			}
+			return &books, nil
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{books}},
+		DEBUG:        true,
+	})
+
+	execute := func() []interface{} {
+		body, _ := json.Marshal(map[string]string{"query": "{ loaderBooks { id authorName } }"})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response["errors"] != nil {
+			t.Fatalf("Unexpected errors: %v", response["errors"])
+		}
+		return response["data"].(map[string]interface{})["loaderBooks"].([]interface{})
+	}
+
+	result := execute()
+	for i, want := range []string{"author-10", "author-20", "author-10"} {
+		if got := result[i].(map[string]interface{})["authorName"]; got != want {
+			t.Errorf("book %d authorName = %v, want %s", i, got, want)
+		}
+	}
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected one batch with 2 unique keys, got %v", batches)
+	}
+
+	// A second request gets its own loader, so nothing is served from the first request's cache
+	execute()
+	if len(batches) != 2 {
+		t.Errorf("Expected the second request to run its own batch, got %d batches", len(batches))
+	}
+}
+
+func TestNewHTTP_FlushesPendingLoadsOnRequestEnd(t *testing.T) {
+	var batchLanguage string
+	RegisterLoader("loaderTestPendingGreeting", func(ctx context.Context, ids []int) ([]string, error) {
+		batchLanguage, _ = LanguageFromContext(ctx)
+		greetings := make([]string, len(ids))
+		for i, id := range ids {
+			greetings[i] = fmt.Sprintf("greeting-%d", id)
+		}
+		return greetings, nil
+	})
+
+	loaded := make(chan string, 1)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"scheduleGreeting": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					loader, err := LoaderFromContext[int, string](p.Context, "loaderTestPendingGreeting")
+					if err != nil {
+						return nil, err
+					}
+					// A wait window that outlives the request; only the request-end flush
+					// dispatches the single scheduled key
+					loader.WithWait(time.Hour)
+					go func() {
+						greeting, err := loader.Load(p.Context, 7)
+						if err != nil {
+							greeting = err.Error()
+						}
+						loaded <- greeting
+					}()
+					for {
+						loader.mu.Lock()
+						scheduled := len(loader.pendingKeys)
+						loader.mu.Unlock()
+						if scheduled == 1 {
+							return "scheduled", nil
+						}
+						time.Sleep(time.Millisecond)
+					}
+				},
+			},
+		}}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	handler := NewHTTP(&GraphContext{Schema: &schema})
+	body, _ := json.Marshal(map[string]string{"query": "{ scheduleGreeting }"})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "fr")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	select {
+	case greeting := <-loaded:
+		if greeting != "greeting-7" {
+			t.Errorf("Expected greeting-7, got %s", greeting)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the pending load to be flushed when the request completed")
+	}
+	if batchLanguage != "fr" {
+		t.Errorf("Expected the batch to run with the request context, got language %q", batchLanguage)
+	}
+}
+
+func TestDataLoader_WithWaitCoalescesConcurrentLoads(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	loader := NewDataLoader(func(ctx context.Context, keys []int) ([]int, error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+		values := make([]int, len(keys))
+		for i, key := range keys {
+			values[i] = key * 10
+		}
+		return values, nil
+	}).WithWait(20 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for key := 1; key <= 5; key++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			value, err := loader.Load(context.Background(), key)
+			if err != nil {
+				t.Errorf("Load(%d) error = %v", key, err)
+			}
+			if value != key*10 {
+				t.Errorf("Load(%d) = %d, want %d", key, value, key*10)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	if len(batches) != 1 || len(batches[0]) != 5 {
+		t.Errorf("Expected a single batch of 5 keys, got %v", batches)
+	}
+}
+
+func TestWithBatchedField(t *testing.T) {
+	type BatchedAdvert struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type BatchedInterview struct {
+		ID       int            `json:"id"`
+		AdvertID int            `json:"advertId"`
+		Advert   *BatchedAdvert `json:"advert"`
+	}
+
+	var mu sync.Mutex
+	var batches [][]int
+	RegisterLoader("loaderTestBatchedAdvert", func(ctx context.Context, ids []int) ([]*BatchedAdvert, error) {
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+		adverts := make([]*BatchedAdvert, len(ids))
+		for i, id := range ids {
+			adverts[i] = &BatchedAdvert{ID: id, Name: fmt.Sprintf("advert-%d", id)}
+		}
+		return adverts, nil
+	})
+
+	const rows = 10
+	interviews := NewResolver[[]BatchedInterview]("batchedInterviews").
+		AsList().
+		WithBatchedField("advert", func(source interface{}) interface{} {
+			return source.(BatchedInterview).AdvertID
+		}, "loaderTestBatchedAdvert").
+		WithResolver(func(p ResolveParams) (*[]BatchedInterview, error) {
+			interviews := make([]BatchedInterview, rows)
+			for i := range interviews {
+				interviews[i] = BatchedInterview{ID: i, AdvertID: 100 + i%4}
+			}
+			return &interviews, nil
+		}).
+		BuildQuery()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{QueryFields: []QueryField{interviews}},
+		DEBUG:        true,
+	})
+
+	body, _ := json.Marshal(map[string]string{"query": "{ batchedInterviews { id advert { id name } } }"})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["errors"] != nil {
+		t.Fatalf("Unexpected errors: %v", response["errors"])
+	}
+
+	result := response["data"].(map[string]interface{})["batchedInterviews"].([]interface{})
+	if len(result) != rows {
+		t.Fatalf("Expected %d interviews, got %d", rows, len(result))
+	}
+	for i, row := range result {
+		advert := row.(map[string]interface{})["advert"].(map[string]interface{})
+		if want := fmt.Sprintf("advert-%d", 100+i%4); advert["name"] != want {
+			t.Errorf("interview %d advert name = %v, want %s", i, advert["name"], want)
+		}
+	}
+	if len(batches) != 1 || len(batches[0]) != 4 {
+		t.Errorf("Expected a single batch of 4 unique keys for %d rows, got %v", rows, batches)
+	}
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// GraphQLValuer is implemented by types that control their own GraphQL output.
+// Struct fields of such a type are exposed as the JSON scalar and serialized as the
+// value returned by GraphQLValue instead of being reflected into.
+//
+// Example:
+//
+//	type Money struct {
+//	    Cents    int64
+//	    Currency string
+//	}
+//
+//	func (m Money) GraphQLValue() interface{} {
+//	    return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+//	}
+//
+//	type Product struct {
+//	    Price Money `json:"price"` // "12.50 EUR"
+//	}
+type GraphQLValuer interface {
+	GraphQLValue() interface{}
+}
+
+var (
+	graphQLValuerType = reflect.TypeOf((*GraphQLValuer)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// isCustomValueType reports whether values of t serialize themselves through
+// GraphQLValuer or json.Marshaler. time.Time and JSONTime keep the DateTime scalar.
+// Pointer types are false: generators check their element type.
+func isCustomValueType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(JSONTime{}) {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	return t.Implements(graphQLValuerType) || ptr.Implements(graphQLValuerType) ||
+		t.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType)
+}
+
+// customValue returns the GraphQL output of a value implementing GraphQLValuer or
+// json.Marshaler, also when only its pointer type has the method. Marshaled JSON is
+// decoded into maps, slices and scalars so it is embedded in the response as is.
+func customValue(value interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, false
+	}
+	if v.Kind() != reflect.Ptr {
+		// The method set of the pointer also has the methods with a pointer receiver
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+
+	switch custom := v.Interface().(type) {
+	case GraphQLValuer:
+		return custom.GraphQLValue(), true
+	case json.Marshaler:
+		data, err := custom.MarshalJSON()
+		if err != nil {
+			return nil, false
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, false
+		}
+		return decoded, true
+	}
+	return nil, false
+}
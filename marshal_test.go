@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type marshalTestMoney struct {
+	Cents    int64
+	Currency string
+}
+
+func (m marshalTestMoney) GraphQLValue() interface{} {
+	return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+}
+
+type marshalTestPoint struct {
+	X, Y int
+}
+
+func (p *marshalTestPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func TestCustomFieldMarshaling(t *testing.T) {
+	type MarshalTestProduct struct {
+		Name     string             `json:"name"`
+		Price    marshalTestMoney   `json:"price"`
+		Discount *marshalTestMoney  `json:"discount"`
+		History  []marshalTestMoney `json:"history"`
+		Location marshalTestPoint   `json:"location"`
+	}
+
+	field := NewResolver[MarshalTestProduct]("marshalTestProduct").
+		WithResolver(func(p ResolveParams) (*MarshalTestProduct, error) {
+			return &MarshalTestProduct{
+				Name:     "lamp",
+				Price:    marshalTestMoney{Cents: 1250, Currency: "EUR"},
+				History:  []marshalTestMoney{{Cents: 1500, Currency: "EUR"}, {Cents: 999, Currency: "EUR"}},
+				Location: marshalTestPoint{X: 3, Y: 4},
+			}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	productType := schema.Type("MarshalTestProduct").(*graphql.Object)
+	for _, name := range []string{"price", "discount", "location"} {
+		if got := productType.Fields()[name].Type; got != JSON {
+			t.Errorf("Expected %s to be JSON, got %v", name, got)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ marshalTestProduct { name price discount history location } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	got, _ := json.Marshal(result.Data)
+	want := `{"marshalTestProduct":{"discount":null,"history":["15.00 EUR","9.99 EUR"],"location":[3,4],"name":"lamp","price":"12.50 EUR"}}`
+	if string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
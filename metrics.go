@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"sync"
+	"time"
+)
+
+// SubscriptionMetrics receives subscription lifecycle events.
+// Implement this interface to export subscription metrics to Prometheus, StatsD,
+// OpenTelemetry or any other backend. Use NewMetricsRegistry for a simple
+// in-memory implementation suitable for tests and debugging endpoints.
+//
+// All methods receive the subscription field name so metrics can be labeled per subscription.
+// Implementations must be safe for concurrent use.
+//
+// Example:
+//
+//	metrics := graph.NewMetricsRegistry()
+//
+//	sub := graph.NewSubscription[MessageEvent]("messageAdded").
+//	    WithResolver(...).
+//	    WithMetrics(metrics).
+//	    BuildSubscription()
+//
+//	// Later
+//	active := metrics.ActiveSubscriptions("messageAdded")
+type SubscriptionMetrics interface {
+	// SubscriptionStarted is called when a client subscribes successfully
+	SubscriptionStarted(name string)
+
+	// SubscriptionEnded is called when a subscription ends, with its total duration
+	SubscriptionEnded(name string, duration time.Duration)
+
+	// EventDelivered is called for every event forwarded to the client
+	EventDelivered(name string)
+
+	// EventDropped is called for every event that could not be delivered
+	EventDropped(name string)
+}
+
+// MetricsRegistry is an in-memory implementation of SubscriptionMetrics and OperationMetrics.
+// It keeps a gauge of active subscriptions and counters for delivered and dropped
+// events, plus the total and count of subscription durations, and counts executed
+// operations and their errors.
+type MetricsRegistry struct {
+	mu              sync.RWMutex
+	active          map[string]int64
+	delivered       map[string]int64
+	dropped         map[string]int64
+	durationSum     map[string]time.Duration
+	durationCount   map[string]int64
+	operations      map[string]int64
+	operationErrors map[string]int64
+}
+
+// NewMetricsRegistry creates an empty in-memory metrics registry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		active:          make(map[string]int64),
+		delivered:       make(map[string]int64),
+		dropped:         make(map[string]int64),
+		durationSum:     make(map[string]time.Duration),
+		durationCount:   make(map[string]int64),
+		operations:      make(map[string]int64),
+		operationErrors: make(map[string]int64),
+	}
+}
+
+// SubscriptionStarted increments the active subscriptions gauge.
+func (m *MetricsRegistry) SubscriptionStarted(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active[name]++
+}
+
+// SubscriptionEnded decrements the active subscriptions gauge and records the duration.
+func (m *MetricsRegistry) SubscriptionEnded(name string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active[name]--
+	m.durationSum[name] += duration
+	m.durationCount[name]++
+}
+
+// EventDelivered increments the delivered events counter.
+func (m *MetricsRegistry) EventDelivered(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delivered[name]++
+}
+
+// EventDropped increments the dropped events counter.
+func (m *MetricsRegistry) EventDropped(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped[name]++
+}
+
+// ActiveSubscriptions returns the number of currently active subscriptions for name.
+func (m *MetricsRegistry) ActiveSubscriptions(name string) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.active[name]
+}
+
+// EventsDelivered returns the number of events delivered for name.
+func (m *MetricsRegistry) EventsDelivered(name string) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.delivered[name]
+}
+
+// EventsDropped returns the number of events dropped for name.
+func (m *MetricsRegistry) EventsDropped(name string) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dropped[name]
+}
+
+// SubscriptionDurations returns the number of ended subscriptions for name
+// and the sum of their durations.
+func (m *MetricsRegistry) SubscriptionDurations(name string) (count int64, total time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.durationCount[name], m.durationSum[name]
+}
+
+// OperationCompleted counts an executed operation and the errors in its result.
+func (m *MetricsRegistry) OperationCompleted(operationName string, duration time.Duration, errorCount int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[operationName]++
+	m.operationErrors[operationName] += int64(errorCount)
+}
+
+// Operations returns the number of executed operations named operationName and
+// the total number of errors they returned.
+func (m *MetricsRegistry) Operations(operationName string) (count int64, errors int64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.operations[operationName], m.operationErrors[operationName]
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// NullabilityMode controls how the nullability of generated output fields is inferred.
+type NullabilityMode int32
+
+const (
+	// NullabilityTags makes a field non-null only when tagged `graphql:"required"` (default)
+	NullabilityTags NullabilityMode = iota
+
+	// NullabilityOmitEmpty follows JSON semantics: fields tagged `json:",omitempty"` and
+	// fields that can encode as null (pointers, slices, maps, interfaces) are nullable,
+	// all other fields are non-null.
+	// Fields tagged `graphql:"required"` are always non-null and fields tagged
+	// `graphql:"nullable"` are always nullable.
+	NullabilityOmitEmpty
+)
+
+var nullabilityMode atomic.Int32
+
+// SetNullabilityMode selects how output field nullability is inferred for types
+// generated afterwards. Types already generated keep their nullability, so call it
+// before building resolvers and schemas.
+//
+// Example:
+//
+//	graph.SetNullabilityMode(graph.NullabilityOmitEmpty)
+//
+//	type User struct {
+//	    ID       int     `json:"id"`                 // Int!
+//	    Nickname string  `json:"nickname,omitempty"` // String
+//	    Manager  *User   `json:"manager"`            // User
+//	}
+func SetNullabilityMode(mode NullabilityMode) {
+	nullabilityMode.Store(int32(mode))
+}
+
+// hasGraphQLOption reports whether the graphql tag of a field contains option
+func hasGraphQLOption(field reflect.StructField, option string) bool {
+	for _, part := range strings.Split(field.Tag.Get("graphql"), ",") {
+		if strings.TrimSpace(part) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isNonNullInputField reports whether an argument or input field is non-null.
+// Inputs are non-null only when tagged `graphql:"required"`; `graphql:"nullable"`
+// takes precedence and keeps the input nullable.
+func isNonNullInputField(field reflect.StructField) bool {
+	if hasGraphQLOption(field, "nullable") {
+		return false
+	}
+	return hasGraphQLOption(field, "required")
+}
+
+// isNonNullField reports whether an output field is non-null under the current mode.
+// `graphql:"nullable"` and `graphql:"required"` override the inferred nullability,
+// with nullable taking precedence. With strict nullability (SchemaBuilderParams.
+// StrictNullability) pointers are always nullable.
+func isNonNullField(field reflect.StructField, strict bool) bool {
+	if hasGraphQLOption(field, "nullable") {
+		return false
+	}
+	if strict && field.Type.Kind() == reflect.Ptr {
+		return false
+	}
+	if hasGraphQLOption(field, "required") {
+		return true
+	}
+	if NullabilityMode(nullabilityMode.Load()) != NullabilityOmitEmpty {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return false
+	}
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, option := range strings.Split(options, ",") {
+		if strings.TrimSpace(option) == "omitempty" {
+			return false
+		}
+	}
+	return true
+}
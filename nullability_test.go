@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestSetNullabilityMode_OmitEmpty(t *testing.T) {
+	type NullabilityProfile struct {
+		ID       int      `json:"id"`
+		Nickname string   `json:"nickname,omitempty"`
+		Age      *int     `json:"age"`
+		Tags     []string `json:"tags"`
+		Email    *string  `json:"email" graphql:"email,required"`
+		Bio      string   `json:"bio,omitempty" graphql:"bio,required"`
+	}
+
+	SetNullabilityMode(NullabilityOmitEmpty)
+	defer SetNullabilityMode(NullabilityTags)
+
+	fields := GenerateGraphQLFields[NullabilityProfile]()
+
+	tests := []struct {
+		field   string
+		nonNull bool
+	}{
+		{"id", true},
+		{"nickname", false},
+		{"age", false},
+		{"tags", false},
+		{"email", true},
+		{"bio", true},
+	}
+	for _, tt := range tests {
+		_, nonNull := fields[tt.field].Type.(*graphql.NonNull)
+		if nonNull != tt.nonNull {
+			t.Errorf("field %s: expected non-null=%v, got type %v", tt.field, tt.nonNull, fields[tt.field].Type)
+		}
+	}
+}
+
+func TestSetNullabilityMode_DefaultIgnoresOmitEmpty(t *testing.T) {
+	type NullabilityDefault struct {
+		ID       int    `json:"id"`
+		Nickname string `json:"nickname,omitempty"`
+		Name     string `json:"name" graphql:"name,required"`
+	}
+
+	fields := GenerateGraphQLFields[NullabilityDefault]()
+
+	if _, ok := fields["id"].Type.(*graphql.NonNull); ok {
+		t.Error("Expected id to be nullable without the omitempty mode")
+	}
+	if _, ok := fields["nickname"].Type.(*graphql.NonNull); ok {
+		t.Error("Expected nickname to be nullable")
+	}
+	if _, ok := fields["name"].Type.(*graphql.NonNull); !ok {
+		t.Error("Expected required name to be non-null")
+	}
+}
+
+func TestGenerateArgsFromType_NullabilityTags(t *testing.T) {
+	type NullabilityArgs struct {
+		Plain           string  `json:"plain"`
+		Required        string  `json:"required" graphql:"required"`
+		Nullable        string  `json:"nullable" graphql:"nullable"`
+		Both            string  `json:"both" graphql:"required,nullable"`
+		Pointer         *string `json:"pointer"`
+		PointerRequired *string `json:"pointerRequired" graphql:"pointerRequired,required"`
+		PointerNullable *string `json:"pointerNullable" graphql:"nullable"`
+	}
+
+	args := generateArgsFromType(reflect.TypeOf(NullabilityArgs{}))
+
+	tests := []struct {
+		arg     string
+		nonNull bool
+	}{
+		{"plain", false},
+		{"required", true},
+		{"nullable", false},
+		{"both", false},
+		{"pointer", false},
+		{"pointerRequired", true},
+		{"pointerNullable", false},
+	}
+	for _, tt := range tests {
+		arg, exists := args[tt.arg]
+		if !exists {
+			t.Errorf("Expected argument %s to be generated, got %v", tt.arg, args)
+			continue
+		}
+		_, nonNull := arg.Type.(*graphql.NonNull)
+		if nonNull != tt.nonNull {
+			t.Errorf("arg %s: expected non-null=%v, got type %v", tt.arg, tt.nonNull, arg.Type)
+		}
+	}
+}
+
+func TestSetNullabilityMode_NullableTagOverridesInference(t *testing.T) {
+	type NullabilityOverride struct {
+		ID    int    `json:"id"`
+		Count int    `json:"count" graphql:"nullable"`
+		Note  string `json:"note,omitempty" graphql:"required"`
+	}
+
+	SetNullabilityMode(NullabilityOmitEmpty)
+	defer SetNullabilityMode(NullabilityTags)
+
+	fields := GenerateGraphQLFields[NullabilityOverride]()
+
+	if _, ok := fields["id"].Type.(*graphql.NonNull); !ok {
+		t.Error("Expected id to be non-null")
+	}
+	if _, ok := fields["count"].Type.(*graphql.NonNull); ok {
+		t.Error("Expected nullable count to be nullable")
+	}
+	if _, ok := fields["note"].Type.(*graphql.NonNull); !ok {
+		t.Error("Expected required note to be non-null")
+	}
+}
+
+type PointerNullabilityApproval struct {
+	ID             int     `json:"id" graphql:"id,required"`
+	ApprovalStatus *bool   `json:"approvalStatus" graphql:"approvalStatus,required"`
+	Active         bool    `json:"active" graphql:"active,required"`
+	Note           *string `json:"note"`
+	Title          string  `json:"title"`
+}
+
+func TestSchemaBuilder_StrictNullability(t *testing.T) {
+	type PointerNullabilityHolder struct {
+		Approval PointerNullabilityApproval `json:"approval"`
+	}
+	approval := NewResolver[PointerNullabilityApproval]("approval").
+		WithResolver(func(p ResolveParams) (*PointerNullabilityApproval, error) {
+			return &PointerNullabilityApproval{}, nil
+		}).
+		BuildQuery()
+	holder := NewResolver[PointerNullabilityHolder]("holder").
+		WithResolver(func(p ResolveParams) (*PointerNullabilityHolder, error) {
+			return &PointerNullabilityHolder{}, nil
+		}).
+		BuildQuery()
+
+	// The same resolvers build a lenient and a strict schema side by side
+	build := func(strict bool) graphql.Schema {
+		schema, err := NewSchemaBuilder(SchemaBuilderParams{
+			QueryFields:       []QueryField{approval, holder},
+			StrictNullability: strict,
+		}).Build()
+		if err != nil {
+			t.Fatalf("Failed to build schema: %v", err)
+		}
+		return schema
+	}
+	lenient := build(false)
+	strict := build(true)
+	lenientAfter := build(false)
+
+	tests := []struct {
+		field   string
+		strict  string
+		lenient string
+	}{
+		{"id", "Int!", "Int!"},
+		{"approvalStatus", "Boolean", "Boolean!"},
+		{"active", "Boolean!", "Boolean!"},
+		{"note", "String", "String"},
+		{"title", "String", "String"},
+	}
+	for _, tt := range tests {
+		for _, schema := range []struct {
+			name   string
+			schema graphql.Schema
+			want   string
+		}{
+			{"strict", strict, tt.strict},
+			{"lenient", lenient, tt.lenient},
+			{"lenient after strict", lenientAfter, tt.lenient},
+		} {
+			fields := schema.schema.Type("PointerNullabilityApproval").(*graphql.Object).Fields()
+			if got := fields[tt.field].Type.String(); got != schema.want {
+				t.Errorf("%s schema, field %s: expected %s, got %s", schema.name, tt.field, schema.want, got)
+			}
+		}
+	}
+
+	// Nested object types are generated for the strict schema as well
+	nested := strict.Type("PointerNullabilityHolder").(*graphql.Object).Fields()["approval"].Type
+	if nested != strict.Type("PointerNullabilityApproval") {
+		t.Errorf("Expected the nested type to be the strict schema's own type")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        strict,
+		RequestString: `{ approval { id approvalStatus active } holder { approval { approvalStatus } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestSetNullabilityMode_TagsKeepRequiredPointers(t *testing.T) {
+	type RequiredPointerApproval struct {
+		ApprovalStatus *bool `json:"approvalStatus" graphql:"approvalStatus,required"`
+	}
+
+	fields := GenerateGraphQLFields[RequiredPointerApproval]()
+	if got := fields["approvalStatus"].Type.String(); got != "Boolean!" {
+		t.Errorf("Expected required pointer to stay Boolean! by default, got %s", got)
+	}
+}
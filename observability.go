@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Span is a unit of work started by a Tracer
+type Span interface {
+	// RecordError records an error that occurred during the span
+	RecordError(err error)
+
+	// End completes the span
+	End()
+}
+
+// Tracer starts spans for GraphQL operations.
+// Implement it with a thin adapter over OpenTelemetry, Datadog or any other tracer.
+//
+// Example:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, graph.Span) {
+//	    ctx, span := t.tracer.Start(ctx, name)
+//	    return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// OperationMetrics receives the outcome of every executed GraphQL operation.
+// MetricsRegistry implements it. Implementations must be safe for concurrent use.
+type OperationMetrics interface {
+	// OperationCompleted is called with the operation name ("" for anonymous
+	// operations), its duration and the number of errors in the result
+	OperationCompleted(operationName string, duration time.Duration, errorCount int)
+}
+
+// ObservabilityConfig configures WithObservability
+type ObservabilityConfig struct {
+	// Tracer: Starts a span per operation (optional)
+	Tracer Tracer
+
+	// Meter: Records per-operation metrics (optional)
+	// When it also implements SubscriptionMetrics and GraphContext.SubscriptionMetrics
+	// is unset, it records subscription metrics too.
+	Meter OperationMetrics
+
+	// Logger: Logs one entry per operation (default: GraphContext.Logger, then slog.Default())
+	Logger *slog.Logger
+}
+
+type observationKey struct{}
+
+// operationObservation tracks an operation between BeforeExecute and AfterExecute
+type operationObservation struct {
+	name  string
+	start time.Time
+	span  Span
+}
+
+// WithObservability wires tracing, metrics and logging for every operation in one call.
+// It chains onto any BeforeExecute and AfterExecute hooks already set, so call it after
+// setting them. Spans started by the Tracer are visible to resolvers through p.Context
+// when the handler is created with NewHTTP.
+//
+// Example:
+//
+//	graphCtx := (&graph.GraphContext{
+//	    SchemaParams: &graph.SchemaBuilderParams{...},
+//	}).WithObservability(graph.ObservabilityConfig{
+//	    Tracer: tracer,
+//	    Meter:  graph.NewMetricsRegistry(),
+//	    Logger: slog.Default(),
+//	})
+//
+//	http.Handle("/graphql", graph.NewHTTP(graphCtx))
+func (g *GraphContext) WithObservability(cfg ObservabilityConfig) *GraphContext {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = g.Logger
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if g.Logger == nil {
+		g.Logger = logger
+	}
+	if subMetrics, ok := cfg.Meter.(SubscriptionMetrics); ok && g.SubscriptionMetrics == nil {
+		g.SubscriptionMetrics = subMetrics
+	}
+
+	before, after := g.BeforeExecute, g.AfterExecute
+
+	g.BeforeExecute = func(ctx context.Context, params *graphql.Params) context.Context {
+		if before != nil {
+			if updated := before(ctx, params); updated != nil {
+				ctx = updated
+			}
+		}
+
+		observation := &operationObservation{
+			name:  operationName(requestPayload{Query: params.RequestString, OperationName: params.OperationName}),
+			start: time.Now(),
+		}
+		if cfg.Tracer != nil {
+			spanName := "graphql.operation"
+			if observation.name != "" {
+				spanName += " " + observation.name
+			}
+			ctx, observation.span = cfg.Tracer.Start(ctx, spanName)
+		}
+		return context.WithValue(ctx, observationKey{}, observation)
+	}
+
+	g.AfterExecute = func(ctx context.Context, result *graphql.Result) {
+		if observation, ok := ctx.Value(observationKey{}).(*operationObservation); ok {
+			duration := time.Since(observation.start)
+
+			if observation.span != nil {
+				for _, err := range result.Errors {
+					observation.span.RecordError(err)
+				}
+				observation.span.End()
+			}
+			if cfg.Meter != nil {
+				cfg.Meter.OperationCompleted(observation.name, duration, len(result.Errors))
+			}
+
+			level := slog.LevelInfo
+			if result.HasErrors() {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(ctx, level, "graphql operation",
+				slog.String("operation", observation.name),
+				slog.Duration("duration", duration),
+				slog.Int("errors", len(result.Errors)),
+			)
+		}
+
+		if after != nil {
+			after(ctx, result)
+		}
+	}
+
+	return g
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type recordingSpan struct {
+	name   string
+	errors []error
+	ended  bool
+}
+
+func (s *recordingSpan) RecordError(err error) { s.errors = append(s.errors, err) }
+func (s *recordingSpan) End()                  { s.ended = true }
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{name: spanName}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestGraphContext_WithObservability(t *testing.T) {
+	tracer := &recordingTracer{}
+	metrics := NewMetricsRegistry()
+	var logs bytes.Buffer
+
+	var resolverSawSpan bool
+	field := NewResolver[string]("observedField").
+		WithResolver(func(p ResolveParams) (*string, error) {
+			_, resolverSawSpan = p.Context.Value(spanKey{}).(*recordingSpan)
+			result := "ok"
+			return &result, nil
+		}).
+		BuildQuery()
+
+	graphCtx := (&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields: []QueryField{field},
+		},
+		DEBUG: true,
+	}).WithObservability(ObservabilityConfig{
+		Tracer: tracer,
+		Meter:  metrics,
+		Logger: slog.New(slog.NewJSONHandler(&logs, nil)),
+	})
+	handler := NewHTTP(graphCtx)
+
+	body := bytes.NewBufferString(`{"query":"query Observed { observedField }"}`)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Tracing
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "graphql.operation Observed" || !span.ended {
+		t.Errorf("Expected an ended span named 'graphql.operation Observed', got %+v", span)
+	}
+	if !resolverSawSpan {
+		t.Error("Expected the span context to reach the resolver")
+	}
+
+	// Metrics
+	if count, errs := metrics.Operations("Observed"); count != 1 || errs != 0 {
+		t.Errorf("Expected 1 operation without errors, got count=%d errors=%d", count, errs)
+	}
+	if graphCtx.SubscriptionMetrics != metrics {
+		t.Error("Expected the meter to be used for subscription metrics")
+	}
+
+	// Logging
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["msg"] != "graphql operation" || entry["operation"] != "Observed" || entry["errors"] != float64(0) {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+}
@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// playgroundTab is a tab opened by default in GraphQL Playground
+type playgroundTab struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Query    string `json:"query"`
+}
+
+type playgroundData struct {
+	Endpoint             string
+	SubscriptionEndpoint string
+	Tabs                 []playgroundTab
+}
+
+// playgroundTemplate mirrors the Playground page of graphql-go/handler and adds
+// default tabs, which the handler package cannot configure
+var playgroundTemplate = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html>
+
+<head>
+  <meta charset=utf-8/>
+  <meta name="viewport" content="user-scalable=no, initial-scale=1.0, minimum-scale=1.0, maximum-scale=1.0, minimal-ui">
+  <title>GraphQL Playground</title>
+  <link rel="stylesheet" href="//cdn.jsdelivr.net/npm/graphql-playground-react/build/static/css/index.css" />
+  <link rel="shortcut icon" href="//cdn.jsdelivr.net/npm/graphql-playground-react/build/favicon.png" />
+  <script src="//cdn.jsdelivr.net/npm/graphql-playground-react/build/static/js/middleware.js"></script>
+</head>
+
+<body>
+  <div id="root"></div>
+  <script>window.addEventListener('load', function (event) {
+      GraphQLPlayground.init(document.getElementById('root'), {
+        endpoint: {{ .Endpoint }},
+        subscriptionEndpoint: {{ .SubscriptionEndpoint }},
+        {{ if .Tabs }}tabs: {{ .Tabs }},{{ end }}
+        setTitle: true
+      })
+    })</script>
+</body>
+
+</html>
+`))
+
+// wantsPlayground reports whether the request comes from a browser asking for the
+// Playground page, using the same rules as graphql-go/handler
+func wantsPlayground(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	accept := r.Header.Get("Accept")
+	_, raw := r.URL.Query()["raw"]
+	return !raw && !strings.Contains(accept, "application/json") && strings.Contains(accept, "text/html")
+}
+
+// subscriptionEndpointURL returns the WebSocket URL subscriptions are served on
+func subscriptionEndpointURL(r *http.Request, graphCtx *GraphContext) string {
+	scheme := "ws"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "wss"
+	}
+	path := graphCtx.WebSocketPath
+	if path == "" {
+		path = r.URL.Path
+	}
+	return scheme + "://" + r.Host + path
+}
+
+// defaultSubscriptionQuery builds an example operation for the first subscription
+// field of the schema, selecting the leaf fields of its result type
+func defaultSubscriptionQuery(schema *graphql.Schema) string {
+	subscriptionType := schema.SubscriptionType()
+	if subscriptionType == nil {
+		return ""
+	}
+
+	fields := subscriptionType.Fields()
+	if len(fields) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	field := fields[names[0]]
+
+	var sb strings.Builder
+	sb.WriteString("subscription {\n  ")
+	sb.WriteString(field.Name)
+
+	if object, ok := unwrapType(field.Type).(*graphql.Object); ok {
+		var leaves []string
+		for name, f := range object.Fields() {
+			switch unwrapType(f.Type).(type) {
+			case *graphql.Scalar, *graphql.Enum:
+				leaves = append(leaves, name)
+			}
+		}
+		sort.Strings(leaves)
+		if len(leaves) > 0 {
+			sb.WriteString(" {\n")
+			for _, leaf := range leaves {
+				sb.WriteString("    " + leaf + "\n")
+			}
+			sb.WriteString("  }")
+		}
+	}
+
+	sb.WriteString("\n}\n")
+	return sb.String()
+}
+
+// unwrapType strips List and NonNull wrappers from a GraphQL type
+func unwrapType(t graphql.Type) graphql.Type {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// renderSubscriptionPlayground serves GraphQL Playground configured for subscriptions:
+// the WebSocket endpoint is set and a subscription tab is opened by default
+func renderSubscriptionPlayground(w http.ResponseWriter, r *http.Request, graphCtx *GraphContext, defaultQuery string) {
+	data := playgroundData{
+		Endpoint:             r.URL.Path,
+		SubscriptionEndpoint: subscriptionEndpointURL(r, graphCtx),
+	}
+	if defaultQuery != "" {
+		data.Tabs = []playgroundTab{{
+			Name:     "Subscription",
+			Endpoint: r.URL.Path,
+			Query:    defaultQuery,
+		}}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := playgroundTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
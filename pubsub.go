@@ -54,6 +54,14 @@ type PubSub interface {
 	Close() error
 }
 
+// PubSubHealthChecker is implemented by PubSub backends that can report whether they
+// are reachable. NewHTTP uses it to answer subscription requests with 503 Service
+// Unavailable while the backend is down.
+type PubSubHealthChecker interface {
+	// Ping returns an error when the backend cannot deliver events
+	Ping(ctx context.Context) error
+}
+
 // Message represents a published message with its topic and data payload.
 type Message struct {
 	// Topic is the channel/topic name where this message was published
@@ -71,6 +79,7 @@ type InMemoryPubSub struct {
 	subscriptions map[string]map[string]chan *Message // topic -> subscriptionID -> channel
 	nextSubID     int
 	closed        bool
+	done          chan struct{} // closed by Close to stop subscription cleanup goroutines
 }
 
 // NewInMemoryPubSub creates a new in-memory PubSub implementation.
@@ -93,6 +102,7 @@ type InMemoryPubSub struct {
 func NewInMemoryPubSub() *InMemoryPubSub {
 	return &InMemoryPubSub{
 		subscriptions: make(map[string]map[string]chan *Message),
+		done:          make(chan struct{}),
 	}
 }
 
@@ -139,9 +149,17 @@ func (p *InMemoryPubSub) Publish(ctx context.Context, topic string, data interfa
 
 // Subscribe creates a subscription to a topic.
 // The subscription is automatically cleaned up when the context is canceled.
+// Subscribing after Close returns an already closed channel.
 func (p *InMemoryPubSub) Subscribe(ctx context.Context, topic string) <-chan *Message {
 	p.mu.Lock()
 
+	if p.closed {
+		p.mu.Unlock()
+		ch := make(chan *Message)
+		close(ch)
+		return ch
+	}
+
 	// Generate unique subscription ID
 	p.nextSubID++
 	subID := string(rune(p.nextSubID))
@@ -160,7 +178,12 @@ func (p *InMemoryPubSub) Subscribe(ctx context.Context, topic string) <-chan *Me
 
 	// Clean up subscription when context is done
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-p.done:
+			// Close already closed the channel
+			return
+		}
 		p.mu.Lock()
 		defer p.mu.Unlock()
 
@@ -181,6 +204,13 @@ func (p *InMemoryPubSub) Subscribe(ctx context.Context, topic string) <-chan *Me
 	return ch
 }
 
+// SubscriberCount returns the number of active subscriptions to a topic.
+func (p *InMemoryPubSub) SubscriberCount(topic string) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.subscriptions[topic])
+}
+
 // Unsubscribe removes a subscription by ID (not commonly used with context-based cleanup).
 func (p *InMemoryPubSub) Unsubscribe(ctx context.Context, subscriptionID string) error {
 	p.mu.Lock()
@@ -207,8 +237,25 @@ func (p *InMemoryPubSub) Unsubscribe(ctx context.Context, subscriptionID string)
 	return ErrSubscriptionNotFound
 }
 
+// Ping reports ErrPubSubClosed once the PubSub is closed
+func (p *InMemoryPubSub) Ping(ctx context.Context) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPubSubClosed
+	}
+	return nil
+}
+
 // Close shuts down the PubSub and closes all active subscriptions.
+//
+// Close drains before closing: it waits for in-flight Publish calls to finish
+// (later calls return ErrPubSubClosed), then closes every subscriber channel.
+// Events already buffered in a subscriber channel are not dropped; subscribers
+// receive them before observing the closed channel.
 func (p *InMemoryPubSub) Close() error {
+	// Publish holds the read lock while sending, so acquiring the write lock
+	// waits for in-flight publishers and no send can race with close(ch)
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -217,6 +264,7 @@ func (p *InMemoryPubSub) Close() error {
 	}
 
 	p.closed = true
+	close(p.done)
 
 	// Close all subscription channels
 	for _, subs := range p.subscriptions {
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestInMemoryPubSub_CloseWhilePublishing(t *testing.T) {
+	pubsub := NewInMemoryPubSub()
+	ctx := context.Background()
+
+	sub := pubsub.Subscribe(ctx, "events")
+
+	// Buffer events before any concurrent activity
+	for i := 0; i < 5; i++ {
+		if err := pubsub.Publish(ctx, "events", i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := pubsub.Publish(ctx, "events", j); err != nil && err != ErrPubSubClosed {
+					t.Errorf("Publish() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	if err := pubsub.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	wg.Wait()
+
+	// Every event buffered before Close is delivered before the channel reports closed
+	received := 0
+	for range sub {
+		received++
+	}
+	if received < 5 {
+		t.Errorf("Expected at least 5 buffered events, got %d", received)
+	}
+
+	if err := pubsub.Publish(ctx, "events", "late"); err != ErrPubSubClosed {
+		t.Errorf("Publish() after Close error = %v, want ErrPubSubClosed", err)
+	}
+	if err := pubsub.Close(); err != ErrPubSubClosed {
+		t.Errorf("second Close() error = %v, want ErrPubSubClosed", err)
+	}
+
+	// Subscribing after Close yields a closed channel instead of leaking
+	if _, ok := <-pubsub.Subscribe(ctx, "events"); ok {
+		t.Error("Expected closed channel when subscribing after Close")
+	}
+}
+
+func TestInMemoryPubSub_CancelAfterClose(t *testing.T) {
+	pubsub := NewInMemoryPubSub()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := pubsub.Subscribe(ctx, "events")
+	if err := pubsub.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Canceling after Close must not close the channel a second time
+	cancel()
+
+	if _, ok := <-sub; ok {
+		t.Error("Expected subscription channel to be closed")
+	}
+}
@@ -0,0 +1,280 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPubSub is a PubSub backed by Redis pub/sub channels, so events published by
+// any instance reach subscribers on every instance behind a load balancer.
+// Payloads are JSON-marshaled exactly like InMemoryPubSub, so
+// UnmarshalSubscriptionMessage works unchanged.
+//
+// Lost connections are re-established and topics resubscribed automatically;
+// events published while a subscriber is disconnected are not delivered to it.
+type RedisPubSub struct {
+	client           *redis.Client
+	channelPrefix    string
+	bufferSize       int
+	reconnectDelay   time.Duration
+	subscribeTimeout time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc // subscriptionID -> cancel
+	nextSubID     int
+	closed        bool
+	wg            sync.WaitGroup
+}
+
+// RedisPubSubOption configures a RedisPubSub
+type RedisPubSubOption func(*RedisPubSub)
+
+// WithRedisChannelPrefix prefixes every topic with prefix to form the Redis channel
+// name, e.g. "graphql:" to keep subscription traffic apart from other channels
+func WithRedisChannelPrefix(prefix string) RedisPubSubOption {
+	return func(p *RedisPubSub) {
+		p.channelPrefix = prefix
+	}
+}
+
+// WithRedisBufferSize sets the buffer size of subscription channels (default: 100).
+// Like InMemoryPubSub, messages for a subscriber whose buffer is full are skipped.
+func WithRedisBufferSize(size int) RedisPubSubOption {
+	return func(p *RedisPubSub) {
+		if size > 0 {
+			p.bufferSize = size
+		}
+	}
+}
+
+// WithRedisReconnectDelay sets how long a subscription waits before reconnecting
+// after the connection to Redis is lost (default: 1s)
+func WithRedisReconnectDelay(d time.Duration) RedisPubSubOption {
+	return func(p *RedisPubSub) {
+		if d > 0 {
+			p.reconnectDelay = d
+		}
+	}
+}
+
+// WithRedisSubscribeTimeout sets how long Subscribe waits for Redis to confirm a
+// subscription before giving up and returning a closed channel (default: 5s)
+func WithRedisSubscribeTimeout(d time.Duration) RedisPubSubOption {
+	return func(p *RedisPubSub) {
+		if d > 0 {
+			p.subscribeTimeout = d
+		}
+	}
+}
+
+// NewRedisPubSub creates a PubSub that publishes and subscribes through Redis.
+// The client is owned by the caller: Close ends all subscriptions but does not close it.
+//
+// Example:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	pubsub := graph.NewRedisPubSub(client, graph.WithRedisChannelPrefix("graphql:"))
+//	defer pubsub.Close()
+//
+//	handler := graph.NewHTTP(&graph.GraphContext{
+//	    SchemaParams:        params,
+//	    PubSub:              pubsub,
+//	    EnableSubscriptions: true,
+//	})
+func NewRedisPubSub(client *redis.Client, opts ...RedisPubSubOption) *RedisPubSub {
+	p := &RedisPubSub{
+		client:           client,
+		bufferSize:       100,
+		reconnectDelay:   time.Second,
+		subscribeTimeout: 5 * time.Second,
+		subscriptions:    make(map[string]context.CancelFunc),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Publish JSON-marshals data and publishes it to the topic's Redis channel.
+func (p *RedisPubSub) Publish(ctx context.Context, topic string, data interface{}) error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrPubSubClosed
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return p.client.Publish(ctx, p.channelPrefix+topic, jsonData).Err()
+}
+
+// Subscribe subscribes to the topic's Redis channel. The returned channel is closed
+// when ctx is canceled, when the subscription is removed with Unsubscribe, or on Close.
+// Subscribing after Close, or when Redis does not confirm the subscription within the
+// subscribe timeout, returns an already closed channel.
+func (p *RedisPubSub) Subscribe(ctx context.Context, topic string) <-chan *Message {
+	ch := make(chan *Message, p.bufferSize)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		close(ch)
+		return ch
+	}
+
+	p.nextSubID++
+	subID := strconv.Itoa(p.nextSubID)
+	subCtx, cancel := context.WithCancel(ctx)
+	p.subscriptions[subID] = cancel
+	p.wg.Add(1)
+	p.mu.Unlock()
+
+	// Wait for the subscription to be confirmed, so events published after
+	// Subscribe returns are received
+	sub, err := p.subscribe(subCtx, p.channelPrefix+topic)
+	if err != nil {
+		p.mu.Lock()
+		delete(p.subscriptions, subID)
+		p.mu.Unlock()
+		cancel()
+		p.wg.Done()
+		close(ch)
+		return ch
+	}
+
+	// ReceiveMessage does not return when its context is canceled, so closing the
+	// Redis subscription is what unblocks the receive loop
+	go func() {
+		<-subCtx.Done()
+		_ = sub.Close()
+	}()
+
+	go func() {
+		defer p.wg.Done()
+		defer close(ch)
+		defer func() {
+			p.mu.Lock()
+			delete(p.subscriptions, subID)
+			p.mu.Unlock()
+			cancel()
+		}()
+
+		for {
+			msg, err := sub.ReceiveMessage(subCtx)
+			if err != nil {
+				if subCtx.Err() != nil {
+					return
+				}
+				// Connection lost: go-redis reconnects and resubscribes on the next receive
+				select {
+				case <-time.After(p.reconnectDelay):
+					continue
+				case <-subCtx.Done():
+					return
+				}
+			}
+
+			select {
+			case ch <- &Message{Topic: topic, Data: []byte(msg.Payload)}:
+			default:
+				// Skip slow consumers (non-blocking)
+			}
+		}
+	}()
+
+	return ch
+}
+
+// subscribe subscribes to channel and waits for Redis to confirm it, for at most the
+// subscribe timeout. Connecting and receiving block on the client's own timeouts, so
+// they run in a goroutine, and a subscription confirmed too late is closed.
+func (p *RedisPubSub) subscribe(ctx context.Context, channel string) (*redis.PubSub, error) {
+	type confirmation struct {
+		sub *redis.PubSub
+		err error
+	}
+	confirmed := make(chan confirmation, 1)
+	go func() {
+		sub := p.client.Subscribe(ctx, channel)
+		_, err := sub.Receive(ctx)
+		confirmed <- confirmation{sub: sub, err: err}
+	}()
+
+	timer := time.NewTimer(p.subscribeTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case c := <-confirmed:
+		if c.err == nil {
+			return c.sub, nil
+		}
+		_ = c.sub.Close()
+		return nil, c.err
+	case <-timer.C:
+		err = context.DeadlineExceeded
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	go func() {
+		c := <-confirmed
+		_ = c.sub.Close()
+	}()
+	return nil, err
+}
+
+// Unsubscribe ends a subscription by ID. Its message channel is closed.
+func (p *RedisPubSub) Unsubscribe(ctx context.Context, subscriptionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPubSubClosed
+	}
+
+	cancel, exists := p.subscriptions[subscriptionID]
+	if !exists {
+		return ErrSubscriptionNotFound
+	}
+	delete(p.subscriptions, subscriptionID)
+	cancel()
+	return nil
+}
+
+// Ping checks that the PubSub is open and the Redis server is reachable
+func (p *RedisPubSub) Ping(ctx context.Context) error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrPubSubClosed
+	}
+	return p.client.Ping(ctx).Err()
+}
+
+// Close ends all subscriptions and waits for their channels to be closed.
+// The Redis client is not closed.
+func (p *RedisPubSub) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPubSubClosed
+	}
+	p.closed = true
+	for _, cancel := range p.subscriptions {
+		cancel()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return nil
+}
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisPubSub(t *testing.T, opts ...RedisPubSubOption) (*RedisPubSub, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisPubSub(client, opts...), server
+}
+
+func receiveMessage(t *testing.T, ch <-chan *Message) *Message {
+	t.Helper()
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			t.Fatal("Expected message, channel was closed")
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for message")
+	}
+	return nil
+}
+
+func expectClosed(t *testing.T, ch <-chan *Message) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("Timed out waiting for channel to close")
+		}
+	}
+}
+
+func TestRedisPubSub_PublishSubscribe(t *testing.T) {
+	pubsub, _ := newTestRedisPubSub(t, WithRedisChannelPrefix("graphql:"))
+	defer pubsub.Close()
+
+	type Event struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	}
+
+	ctx := context.Background()
+	sub := pubsub.Subscribe(ctx, "messages")
+	if err := pubsub.Publish(ctx, "messages", Event{ID: "1", Text: "hello"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	msg := receiveMessage(t, sub)
+	if msg.Topic != "messages" {
+		t.Errorf("Expected topic messages, got %s", msg.Topic)
+	}
+	event, err := UnmarshalSubscriptionMessage[Event](msg)
+	if err != nil {
+		t.Fatalf("UnmarshalSubscriptionMessage() error = %v", err)
+	}
+	if event.ID != "1" || event.Text != "hello" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+}
+
+func TestRedisPubSub_ContextCancellationAndClose(t *testing.T) {
+	pubsub, _ := newTestRedisPubSub(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := pubsub.Subscribe(ctx, "events")
+	open := pubsub.Subscribe(context.Background(), "events")
+
+	cancel()
+	expectClosed(t, canceled)
+
+	if err := pubsub.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	expectClosed(t, open)
+
+	if err := pubsub.Publish(context.Background(), "events", "late"); err != ErrPubSubClosed {
+		t.Errorf("Expected ErrPubSubClosed after Close, got %v", err)
+	}
+	expectClosed(t, pubsub.Subscribe(context.Background(), "events"))
+}
+
+func TestRedisPubSub_Reconnect(t *testing.T) {
+	pubsub, server := newTestRedisPubSub(t, WithRedisReconnectDelay(10*time.Millisecond))
+	defer pubsub.Close()
+
+	ctx := context.Background()
+	sub := pubsub.Subscribe(ctx, "events")
+
+	server.Close()
+	time.Sleep(50 * time.Millisecond)
+	if err := server.Restart(); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+
+	// Publish until the subscription has reconnected and resubscribed
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		_ = pubsub.Publish(ctx, "events", "after restart")
+		select {
+		case msg, ok := <-sub:
+			if !ok {
+				t.Fatal("Expected subscription to survive reconnection, channel was closed")
+			}
+			if string(msg.Data) != `"after restart"` {
+				t.Errorf("Unexpected payload %s", msg.Data)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	t.Fatal("Timed out waiting for message after reconnection")
+}
+
+func TestRedisPubSub_SubscribeUnreachable(t *testing.T) {
+	pubsub, server := newTestRedisPubSub(t)
+	defer pubsub.Close()
+	server.Close()
+
+	start := time.Now()
+	sub := pubsub.Subscribe(context.Background(), "events")
+	expectClosed(t, sub)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Subscribe to fail fast, took %v", elapsed)
+	}
+
+	pubsub.mu.Lock()
+	remaining := len(pubsub.subscriptions)
+	pubsub.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("Expected the failed subscription to be removed, %d remain", remaining)
+	}
+}
+
+func TestRedisPubSub_SubscribeTimeout(t *testing.T) {
+	// A server that accepts connections but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String()})
+	defer client.Close()
+	pubsub := NewRedisPubSub(client, WithRedisSubscribeTimeout(50*time.Millisecond))
+	defer pubsub.Close()
+
+	start := time.Now()
+	sub := pubsub.Subscribe(context.Background(), "events")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Subscribe to give up after the subscribe timeout, took %v", elapsed)
+	}
+	expectClosed(t, sub)
+}
+
+func TestRedisPubSub_Ping(t *testing.T) {
+	pubsub, server := newTestRedisPubSub(t)
+
+	if err := pubsub.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	server.Close()
+	if err := pubsub.Ping(context.Background()); err == nil {
+		t.Error("Expected Ping to fail while Redis is down")
+	}
+
+	_ = pubsub.Close()
+	if err := pubsub.Ping(context.Background()); err != ErrPubSubClosed {
+		t.Errorf("Expected ErrPubSubClosed after Close, got %v", err)
+	}
+}
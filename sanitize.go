@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"golang.org/x/text/unicode/norm"
+)
+
+// StringSanitization configures how string arguments are cleaned before resolvers see
+// them. Strings nested in input objects and lists are sanitized too.
+//
+// Configure it for all fields served by NewHTTP with GraphContext.StringSanitization,
+// or for one field with WithStringSanitization, which takes precedence.
+//
+// Example:
+//
+//	graph.StringSanitization{Trim: true, Normalize: true, EscapeHTML: true}
+type StringSanitization struct {
+	// Trim: Remove leading and trailing white space
+	Trim bool
+
+	// Normalize: Convert to Unicode normalization form NFC, so equivalent strings
+	// are stored with the same bytes
+	Normalize bool
+
+	// EscapeHTML: Escape <, >, &, ' and " to reduce the risk of stored XSS
+	EscapeHTML bool
+}
+
+// SanitizeString applies the sanitization to one string
+func (s StringSanitization) SanitizeString(value string) string {
+	if s.Trim {
+		value = strings.TrimSpace(value)
+	}
+	if s.Normalize {
+		value = norm.NFC.String(value)
+	}
+	if s.EscapeHTML {
+		value = html.EscapeString(value)
+	}
+	return value
+}
+
+// sanitizeValue sanitizes the strings of an argument value, copying maps and lists
+func (s StringSanitization) sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return s.SanitizeString(v)
+	case map[string]interface{}:
+		sanitized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			sanitized[key] = s.sanitizeValue(item)
+		}
+		return sanitized
+	case []interface{}:
+		sanitized := make([]interface{}, len(v))
+		for i, item := range v {
+			sanitized[i] = s.sanitizeValue(item)
+		}
+		return sanitized
+	}
+	return value
+}
+
+// stringSanitizationKey stores the StringSanitization of GraphContext in the request context
+type stringSanitizationKey struct{}
+
+// withStringSanitization returns a context applying the sanitization to the string
+// arguments of every resolver without its own sanitization
+func withStringSanitization(ctx context.Context, sanitization StringSanitization) context.Context {
+	return context.WithValue(ctx, stringSanitizationKey{}, sanitization)
+}
+
+// WithStringSanitization sanitizes the string arguments of the field before any
+// preprocessor, middleware or the resolver sees them. It replaces the sanitization
+// configured with GraphContext.StringSanitization for this field; pass the zero
+// value to leave the field's strings untouched.
+//
+// Example:
+//
+//	NewResolver[Comment]("addComment").
+//		WithArgsFromStruct(CommentArgs{}).
+//		WithStringSanitization(graph.StringSanitization{Trim: true, EscapeHTML: true}).
+//		WithResolver(...)
+func (r *UnifiedResolver[T]) WithStringSanitization(sanitization StringSanitization) *UnifiedResolver[T] {
+	r.stringSanitization = &sanitization
+	return r
+}
+
+// WithStringSanitization sanitizes the string arguments before they are decoded into A.
+// See UnifiedResolver.WithStringSanitization.
+func (r *TypedArgsResolver[T, A]) WithStringSanitization(sanitization StringSanitization) *TypedArgsResolver[T, A] {
+	r.base.WithStringSanitization(sanitization)
+	return r
+}
+
+// sanitizeArgs sanitizes the string arguments before calling resolver, with the field
+// sanitization when set and the sanitization of the request context otherwise
+func sanitizeArgs(fieldSanitization *StringSanitization, resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		sanitization := fieldSanitization
+		if sanitization == nil && p.Context != nil {
+			if fromContext, ok := p.Context.Value(stringSanitizationKey{}).(StringSanitization); ok {
+				sanitization = &fromContext
+			}
+		}
+		if sanitization == nil || *sanitization == (StringSanitization{}) || len(p.Args) == 0 {
+			return resolver(p)
+		}
+
+		args := make(map[string]interface{}, len(p.Args))
+		for key, value := range p.Args {
+			args[key] = sanitization.sanitizeValue(value)
+		}
+		p.Args = args
+		return resolver(p)
+	}
+}
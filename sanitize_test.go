@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type SanitizeTestComment struct {
+	Body   string `json:"body"`
+	Author string `json:"author"`
+}
+
+type SanitizeTestCommentArgs struct {
+	Body   string `json:"body"`
+	Author string `json:"author"`
+}
+
+func TestStringSanitization_SanitizeString(t *testing.T) {
+	tests := []struct {
+		name         string
+		sanitization StringSanitization
+		input        string
+		want         string
+	}{
+		{name: "trim", sanitization: StringSanitization{Trim: true}, input: "  hello \n", want: "hello"},
+		{name: "normalize", sanitization: StringSanitization{Normalize: true}, input: "Cafe\u0301", want: "Caf\u00e9"},
+		{name: "escape", sanitization: StringSanitization{EscapeHTML: true}, input: `<b>"hi"</b>`, want: "&lt;b&gt;&#34;hi&#34;&lt;/b&gt;"},
+		{name: "all", sanitization: StringSanitization{Trim: true, Normalize: true, EscapeHTML: true}, input: " <i>Cafe\u0301</i> ", want: "&lt;i&gt;Caf\u00e9&lt;/i&gt;"},
+		{name: "none", input: " <b> ", want: " <b> "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sanitization.SanitizeString(tt.input); got != tt.want {
+				t.Errorf("SanitizeString(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringSanitization_Resolvers(t *testing.T) {
+	var seen []SanitizeTestCommentArgs
+	resolve := func(p ResolveParams) (*SanitizeTestComment, error) {
+		var args SanitizeTestCommentArgs
+		if err := mapArgsToStruct(p.Args, &args); err != nil {
+			return nil, err
+		}
+		seen = append(seen, args)
+		return &SanitizeTestComment{Body: args.Body, Author: args.Author}, nil
+	}
+
+	globalComment := NewResolver[SanitizeTestComment]("sanitizeTestGlobalComment").
+		WithArgsFromStruct(SanitizeTestCommentArgs{}).
+		WithResolver(resolve).
+		BuildMutation()
+	fieldComment := NewResolver[SanitizeTestComment]("sanitizeTestFieldComment").
+		WithArgsFromStruct(SanitizeTestCommentArgs{}).
+		WithStringSanitization(StringSanitization{Trim: true}).
+		WithResolver(resolve).
+		BuildMutation()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields:    []QueryField{getDefaultHelloQuery()},
+			MutationFields: []MutationField{globalComment, fieldComment},
+		},
+		StringSanitization: &StringSanitization{Trim: true, EscapeHTML: true},
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"query": `mutation($author: String) {
+			global: sanitizeTestGlobalComment(body: "  <script>alert(1)</script> ", author: $author) { body }
+			field: sanitizeTestFieldComment(body: "  <b>kept</b> ", author: $author) { body }
+		}`,
+		"variables": map[string]interface{}{"author": "  ada  "},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(seen) != 2 {
+		t.Fatalf("Expected both resolvers to run, got %v: %s", seen, w.Body.String())
+	}
+	want := []SanitizeTestCommentArgs{
+		{Body: "&lt;script&gt;alert(1)&lt;/script&gt;", Author: "ada"},
+		{Body: "<b>kept</b>", Author: "ada"},
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("Resolver %d saw %+v, want %+v", i, seen[i], want[i])
+		}
+	}
+}
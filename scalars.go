@@ -0,0 +1,355 @@
+package graph
+
+import (
+	"encoding"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// normalizeEmail validates an email address and lowercases its domain.
+// Display names ("Ada <ada@example.com>") are rejected.
+func normalizeEmail(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Name != "" || addr.Address != value {
+		return "", false
+	}
+	at := strings.LastIndex(value, "@")
+	return value[:at] + "@" + strings.ToLower(value[at+1:]), true
+}
+
+// normalizeURL validates an absolute URL and lowercases its scheme and host
+func normalizeURL(value string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), true
+}
+
+// stringScalar builds a string scalar that validates and normalizes its values.
+// Invalid input values are rejected; stored values that fail validation are
+// serialized unchanged.
+func stringScalar(name, description string, normalize func(string) (string, bool)) *graphql.Scalar {
+	// Accept string-kinded values, including named types such as `type Email string`,
+	// and text marshalers such as uuid.UUID
+	toString := func(value interface{}) (string, bool) {
+		if marshaler, ok := value.(encoding.TextMarshaler); ok {
+			if v := reflect.ValueOf(value); v.Kind() != reflect.Ptr || !v.IsNil() {
+				text, err := marshaler.MarshalText()
+				return string(text), err == nil
+			}
+		}
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return "", false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.String {
+			return "", false
+		}
+		return v.String(), true
+	}
+	parse := func(value interface{}) interface{} {
+		if s, ok := toString(value); ok {
+			if normalized, ok := normalize(s); ok {
+				return normalized
+			}
+		}
+		return nil
+	}
+
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: description,
+		Serialize: func(value interface{}) interface{} {
+			if normalized := parse(value); normalized != nil {
+				return normalized
+			}
+			if s, ok := toString(value); ok {
+				return s
+			}
+			return nil
+		},
+		ParseValue: parse,
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if v, ok := valueAST.(*ast.StringValue); ok {
+				return parse(v.Value)
+			}
+			return nil
+		},
+	})
+}
+
+// Email is a GraphQL scalar type for email addresses.
+// Input values must be plain addresses (no display name); the domain is lowercased
+// on input and output.
+//
+// Usage in struct fields:
+//
+//	type Contact struct {
+//	    Name         string `json:"name"`
+//	    ContactEmail string `json:"contactEmail" graphql:"contactEmail,email"` // Will use Email scalar
+//	}
+var Email = stringScalar("Email",
+	"The `Email` scalar type represents an email address such as user@example.com",
+	normalizeEmail)
+
+// URL is a GraphQL scalar type for absolute URLs.
+// Input values must have a scheme and host; both are lowercased on input and output.
+//
+// Usage in struct fields:
+//
+//	type Profile struct {
+//	    Website string `json:"website" graphql:"website,url"` // Will use URL scalar
+//	}
+var URL = stringScalar("URL",
+	"The `URL` scalar type represents an absolute URL such as https://example.com/path",
+	normalizeURL)
+
+// uuidPattern matches the canonical 8-4-4-4-12 hexadecimal UUID format
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// normalizeUUID validates a UUID in 8-4-4-4-12 format and lowercases it
+func normalizeUUID(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !uuidPattern.MatchString(value) {
+		return "", false
+	}
+	return strings.ToLower(value), true
+}
+
+// UUID is a GraphQL scalar type for UUIDs in the 8-4-4-4-12 format.
+// Malformed input values are rejected when arguments are parsed; values are lowercased.
+// Fields of type uuid.UUID (github.com/google/uuid) use it automatically and input
+// values decode into them. Other fields select it with a uuid tag option after the
+// field name; `graphql:"uuid"` alone only names the field.
+//
+// Usage in struct fields:
+//
+//	type Interview struct {
+//	    UID       string    `json:"uid" graphql:"uid,uuid"` // Will use UUID scalar
+//	    AdvertUID uuid.UUID `json:"advertUid"`              // Will use UUID scalar
+//	}
+var UUID = stringScalar("UUID",
+	"The `UUID` scalar type represents a UUID in 8-4-4-4-12 format such as 123e4567-e89b-12d3-a456-426614174000",
+	normalizeUUID)
+
+// coerceInt64 converts integral numbers and base-10 numeric strings to int64.
+// Unsigned values above math.MaxInt64 are returned as uint64.
+func coerceInt64(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return v.Uint()
+		}
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f)
+		}
+	case reflect.String:
+		// Also covers json.Number
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// Int64 is a GraphQL scalar type for 64-bit integers. The generators use it for
+// int64 and uint64 fields and arguments, which overflow the 32-bit Int type.
+// Values are serialized as JSON numbers. Input values can be integer literals or
+// numeric strings; clients that parse JSON numbers as doubles (such as JavaScript)
+// should send values above 2^53 as strings to keep them exact.
+//
+// Usage in struct fields:
+//
+//	type Advert struct {
+//	    ID            int64 `json:"id"`            // Will use Int64 scalar
+//	    TotalElements int64 `json:"totalElements"` // Will use Int64 scalar
+//	}
+var Int64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Int64",
+	Description: "The `Int64` scalar type represents a signed 64-bit integer, accepted as a number or a numeric string",
+	Serialize:   coerceInt64,
+	ParseValue:  coerceInt64,
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch v := valueAST.(type) {
+		case *ast.IntValue:
+			return coerceInt64(v.Value)
+		case *ast.StringValue:
+			return coerceInt64(v.Value)
+		}
+		return nil
+	},
+})
+
+// jsonLiteral converts a GraphQL literal to the equivalent JSON value
+func jsonLiteral(valueAST ast.Value) interface{} {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n
+		}
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.ListValue:
+		list := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			list[i] = jsonLiteral(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = jsonLiteral(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+// JSON is a GraphQL scalar type for arbitrary JSON values. The generators use it for
+// map and interface{} fields, and for fields whose type implements GraphQLValuer or
+// json.Marshaler, which are serialized through those methods. As an input it accepts
+// objects, lists and scalars.
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "The `JSON` scalar type represents an arbitrary JSON value",
+	Serialize: func(value interface{}) interface{} {
+		if custom, ok := customValue(value); ok {
+			return custom
+		}
+		return value
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return value
+	},
+	ParseLiteral: jsonLiteral,
+})
+
+// Global scalar registry used by the type generators
+var (
+	scalarsByType  = map[reflect.Type]*graphql.Scalar{reflect.TypeOf(uuid.UUID{}): UUID, reflect.TypeOf(Upload{}): UploadScalar}
+	scalarsByName  = map[string]*graphql.Scalar{"email": Email, "url": URL, "uuid": UUID}
+	scalarRegistry sync.RWMutex
+)
+
+// RegisterScalar maps the Go type T to a custom scalar. Struct fields and arguments
+// of type T (or *T, []T) use the scalar instead of the default mapping.
+// The scalar can also be selected per field with a graphql tag option naming it,
+// e.g. `graphql:"contact,email"` or `json:"contact" graphql:"scalar=Email"`.
+//
+// Example:
+//
+//	type EmailAddress string
+//
+//	graph.RegisterScalar[EmailAddress](graph.Email)
+//
+//	type User struct {
+//	    Email EmailAddress `json:"email"` // Email scalar
+//	}
+func RegisterScalar[T any](scalar *graphql.Scalar) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	scalarRegistry.Lock()
+	defer scalarRegistry.Unlock()
+	scalarsByType[t] = scalar
+	scalarsByName[strings.ToLower(scalar.Name())] = scalar
+}
+
+// lookupScalarByType returns the scalar mapped to a Go type in scope or globally
+func lookupScalarByType(scope *typeScope, t reflect.Type) *graphql.Scalar {
+	if scalar, ok := scope.scalarByType(t); ok {
+		return scalar
+	}
+	scalarRegistry.RLock()
+	defer scalarRegistry.RUnlock()
+	return scalarsByType[t]
+}
+
+// lookupScalarByTag returns the scalar selected by the graphql tag of a field, among
+// the scalars of scope and the registered scalars. A tag option naming a scalar
+// selects it (`graphql:"contact,email"` or `graphql:"scalar=Email"`); the part naming
+// the field is never an option, so `graphql:"email"` only names the field "email".
+func lookupScalarByTag(scope *typeScope, field reflect.StructField) *graphql.Scalar {
+	tag := field.Tag.Get("graphql")
+	if tag == "" {
+		return nil
+	}
+
+	byName := func(name string) *graphql.Scalar {
+		if scalar, ok := scope.scalarByName(name); ok {
+			return scalar
+		}
+		scalarRegistry.RLock()
+		defer scalarRegistry.RUnlock()
+		return scalarsByName[strings.ToLower(name)]
+	}
+	for _, option := range graphqlTagOptions(tag) {
+		option = strings.TrimSpace(option)
+		if name, ok := strings.CutPrefix(option, "scalar="); ok {
+			return byName(name)
+		}
+		if isGraphQLTagOption(option) {
+			continue
+		}
+		if scalar := byName(option); scalar != nil {
+			return scalar
+		}
+	}
+	return nil
+}
+
+// scalarForField returns the scalar selected in scope by the tag of a field of type t,
+// wrapped in a list for slice fields, or nil when the tag selects no scalar
+func scalarForField(scope *typeScope, t reflect.Type, field reflect.StructField) graphql.Type {
+	scalar := lookupScalarByTag(scope, field)
+	if scalar == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		return graphql.NewList(scalar)
+	}
+	return scalar
+}
@@ -0,0 +1,546 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+type scalarTestAddress string
+
+type scalarTestContact struct {
+	Name    string            `json:"name"`
+	Contact string            `json:"contact" graphql:"contact,email"`
+	Website string            `json:"website" graphql:"website,required,url"`
+	Backup  scalarTestAddress `json:"backup"`
+}
+
+func TestEmailAndURLScalars(t *testing.T) {
+	RegisterScalar[scalarTestAddress](Email)
+
+	create := NewResolver[scalarTestContact]("createScalarContact").
+		WithArgsFromStruct(scalarTestContact{}).
+		WithResolver(func(p ResolveParams) (*scalarTestContact, error) {
+			var contact scalarTestContact
+			if err := mapArgsToStruct(p.Args, &contact); err != nil {
+				return nil, err
+			}
+			return &contact, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{getDefaultHelloQuery()},
+		MutationFields: []MutationField{create},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{"contact: Email", "website: URL!", "backup: Email"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected schema to contain %q, got:\n%s", want, sdl)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { createScalarContact(name: "Ada", contact: "Ada@Example.COM", website: "HTTPS://Example.com/Path", backup: "ada@BACKUP.io") { contact website backup } }`,
+		Context:       context.Background(),
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	contact := result.Data.(map[string]interface{})["createScalarContact"].(map[string]interface{})
+	if contact["contact"] != "Ada@example.com" {
+		t.Errorf("Expected normalized email 'Ada@example.com', got %v", contact["contact"])
+	}
+	if contact["website"] != "https://example.com/Path" {
+		t.Errorf("Expected normalized URL 'https://example.com/Path', got %v", contact["website"])
+	}
+	if contact["backup"] != "ada@backup.io" {
+		t.Errorf("Expected normalized backup email 'ada@backup.io', got %v", contact["backup"])
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{
+			name:  "malformed email literal",
+			query: `mutation { createScalarContact(contact: "not-an-email", website: "https://example.com") { contact } }`,
+		},
+		{
+			name:  "email with display name",
+			query: `mutation { createScalarContact(contact: "Ada <ada@example.com>", website: "https://example.com") { contact } }`,
+		},
+		{
+			name:  "relative URL literal",
+			query: `mutation { createScalarContact(website: "/just/a/path") { website } }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tt.query})
+			if len(result.Errors) == 0 {
+				t.Error("Expected validation error for malformed input")
+			}
+		})
+	}
+
+	// Variables are validated through ParseValue
+	result = graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `mutation($email: Email) { createScalarContact(contact: $email, website: "https://example.com") { contact } }`,
+		VariableValues: map[string]interface{}{"email": "missing-at.example.com"},
+	})
+	if len(result.Errors) == 0 {
+		t.Error("Expected error for malformed email variable")
+	}
+}
+
+type SchemaDecimal string
+
+func TestScalarTags_NameOnlyTagKeepsType(t *testing.T) {
+	type scalarTagProfile struct {
+		Handle  string `json:"handle" graphql:"email"`
+		Website string `graphql:"url"`
+		Backup  string `json:"backup" graphql:"required,email"`
+		Contact string `json:"contact" graphql:",email"`
+	}
+
+	fields := GenerateGraphQLFields[scalarTagProfile]()
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"handle", "String"},
+		{"url", "String"},
+		{"backup", "String!"},
+		{"contact", "Email"},
+	}
+	for _, tt := range tests {
+		if got := fields[tt.field].Type.String(); got != tt.want {
+			t.Errorf("field %s: expected %s, got %s", tt.field, tt.want, got)
+		}
+	}
+
+	args := GenerateArgsFromStruct[scalarTagProfile]()
+	if got := args["handle"].Type.String(); got != "String" {
+		t.Errorf("Expected the handle argument to stay String, got %s", got)
+	}
+}
+
+func TestSchemaBuilderParams_Scalars(t *testing.T) {
+	decimal := graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "SchemaDecimal",
+		Description: "A decimal number encoded as a string",
+		Serialize: func(value interface{}) interface{} {
+			return fmt.Sprint(value)
+		},
+		ParseValue:   func(value interface{}) interface{} { return value },
+		ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
+	})
+	unused := graphql.NewScalar(graphql.ScalarConfig{
+		Name:      "SchemaUnused",
+		Serialize: func(value interface{}) interface{} { return value },
+	})
+
+	type SchemaScalarProduct struct {
+		Price    SchemaDecimal `json:"price"`
+		Discount string        `json:"discount" graphql:"scalar=SchemaDecimal"`
+	}
+	type SchemaScalarArgs struct {
+		MaxPrice SchemaDecimal `json:"maxPrice"`
+	}
+
+	var maxPrice interface{}
+	field := NewResolver[SchemaScalarProduct]("schemaScalarProduct").
+		WithArgsFromStruct(SchemaScalarArgs{}).
+		WithResolver(func(p ResolveParams) (*SchemaScalarProduct, error) {
+			maxPrice = p.Args["maxPrice"]
+			return &SchemaScalarProduct{Price: "9.99", Discount: "0.10"}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+		Scalars:     []*graphql.Scalar{unused},
+		ScalarTypes: map[reflect.Type]*graphql.Scalar{reflect.TypeOf(SchemaDecimal("")): decimal},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	fields := schema.Type("SchemaScalarProduct").(*graphql.Object).Fields()
+	for _, name := range []string{"price", "discount"} {
+		if fields[name].Type != decimal {
+			t.Errorf("Expected field %s to use SchemaDecimal, got %v", name, fields[name].Type)
+		}
+	}
+	if args := schema.QueryType().Fields()["schemaScalarProduct"].Args; len(args) != 1 || args[0].Type != decimal {
+		t.Errorf("Expected the maxPrice argument to use SchemaDecimal, got %v", args)
+	}
+	if schema.Type("SchemaUnused") == nil {
+		t.Error("Expected unused scalar to be part of the schema")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ schemaScalarProduct(maxPrice: "20.00") { price discount } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	product := result.Data.(map[string]interface{})["schemaScalarProduct"].(map[string]interface{})
+	if product["price"] != "9.99" || product["discount"] != "0.10" {
+		t.Errorf("Unexpected product: %v", product)
+	}
+	if maxPrice != "20.00" {
+		t.Errorf("Expected the maxPrice argument to be parsed by the scalar, got %v", maxPrice)
+	}
+
+	// The scalars don't leak into schemas built without them
+	other, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{field}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	if other.Type("SchemaDecimal") != nil {
+		t.Error("Expected SchemaDecimal to stay out of a schema built without it")
+	}
+	otherFields := other.Type("SchemaScalarProduct").(*graphql.Object).Fields()
+	if otherFields["price"].Type != graphql.String || otherFields["discount"].Type != graphql.String {
+		t.Errorf("Expected String fields without the schema scalars, got %v and %v", otherFields["price"].Type, otherFields["discount"].Type)
+	}
+}
+
+func TestSchemaBuilderParams_ScalarsMatchGoTypes(t *testing.T) {
+	decimal := graphql.NewScalar(graphql.ScalarConfig{
+		Name:      "SchemaDecimal",
+		Serialize: func(value interface{}) interface{} { return fmt.Sprint(value) },
+	})
+
+	// Same name as the mapped Go type, but a different type
+	type SchemaDecimal string
+	type SchemaLookalikeProduct struct {
+		Price SchemaDecimal `json:"price"`
+	}
+
+	field := NewResolver[SchemaLookalikeProduct]("schemaLookalikeProduct").
+		WithResolver(func(p ResolveParams) (*SchemaLookalikeProduct, error) {
+			return &SchemaLookalikeProduct{Price: "1"}, nil
+		}).
+		BuildQuery()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{field},
+		Scalars:     []*graphql.Scalar{decimal},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	fields := schema.Type("SchemaLookalikeProduct").(*graphql.Object).Fields()
+	if fields["price"].Type != graphql.String {
+		t.Errorf("Expected a Go type named like the scalar to stay String, got %v", fields["price"].Type)
+	}
+}
+
+type scalarTestInterview struct {
+	UID       string    `json:"uid" graphql:"uid,uuid"`
+	AdvertUID uuid.UUID `json:"advertUid"`
+}
+
+type scalarTestInterviewInput struct {
+	AdvertUID uuid.UUID `json:"advertUid"`
+	Note      string    `json:"note"`
+}
+
+func TestUUIDScalar(t *testing.T) {
+	create := NewResolver[scalarTestInterview]("createScalarInterview").
+		WithArgsFromStruct(scalarTestInterview{}).
+		WithResolver(func(p ResolveParams) (*scalarTestInterview, error) {
+			var interview scalarTestInterview
+			if err := mapArgsToStruct(p.Args, &interview); err != nil {
+				return nil, err
+			}
+			return &interview, nil
+		}).
+		BuildMutation()
+
+	schedule := NewResolver[scalarTestInterview]("scheduleScalarInterview").
+		WithInputObject(scalarTestInterviewInput{}).
+		WithResolver(func(p ResolveParams) (*scalarTestInterview, error) {
+			var input scalarTestInterviewInput
+			if err := GetArg(p, "input", &input); err != nil {
+				return nil, err
+			}
+			return &scalarTestInterview{UID: input.AdvertUID.String(), AdvertUID: input.AdvertUID}, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{getDefaultHelloQuery()},
+		MutationFields: []MutationField{create, schedule},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{"uid: UUID", "advertUid: UUID"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected schema to contain %q, got:\n%s", want, sdl)
+		}
+	}
+	inputFields := schema.Type("scalarTestInterviewInputInput").(*graphql.InputObject).Fields()
+	if inputFields["advertUid"].Type != UUID {
+		t.Errorf("Expected input field advertUid to use UUID, got %v", inputFields["advertUid"].Type)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { createScalarInterview(uid: "123E4567-E89B-12D3-A456-426614174000", advertUid: "9f0c1e2a-3b4c-4d5e-8f60-718293a4b5c6") { uid advertUid } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	interview := result.Data.(map[string]interface{})["createScalarInterview"].(map[string]interface{})
+	if interview["uid"] != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Expected normalized uid, got %v", interview["uid"])
+	}
+	if interview["advertUid"] != "9f0c1e2a-3b4c-4d5e-8f60-718293a4b5c6" {
+		t.Errorf("Expected advertUid to round-trip through uuid.UUID, got %v", interview["advertUid"])
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `mutation($input: scalarTestInterviewInputInput!) { scheduleScalarInterview(input: $input) { advertUid } }`,
+		VariableValues: map[string]interface{}{"input": map[string]interface{}{"advertUid": "9f0c1e2a-3b4c-4d5e-8f60-718293a4b5c6"}},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	for _, query := range []string{
+		`mutation { createScalarInterview(uid: "not-a-uuid") { uid } }`,
+		`mutation { createScalarInterview(advertUid: "123e4567e89b12d3a456426614174000") { uid } }`,
+		`mutation { scheduleScalarInterview(input: {advertUid: "123"}) { uid } }`,
+	} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		if len(result.Errors) == 0 {
+			t.Errorf("Expected an error for malformed UUID in %s", query)
+		} else if !strings.Contains(result.Errors[0].Message, "UUID") {
+			t.Errorf("Expected the error to mention UUID, got %q", result.Errors[0].Message)
+		}
+	}
+}
+
+type scalarTestAdvert struct {
+	ID            int64  `json:"id"`
+	TotalElements uint64 `json:"totalElements"`
+	Pages         int    `json:"pages"`
+}
+
+type scalarTestAdvertPatch struct {
+	ID            int64  `json:"id"`
+	TotalElements uint64 `json:"totalElements"`
+}
+
+func TestUUIDScalar_NameOnlyTag(t *testing.T) {
+	type scalarTestUUIDNames struct {
+		UUID    string `json:"uuid" graphql:"uuid"`
+		Session string `json:"session" graphql:",uuid"`
+	}
+
+	fields := GenerateGraphQLFields[scalarTestUUIDNames]()
+	if got := fields["uuid"].Type.String(); got != "String" {
+		t.Errorf("Expected a field named uuid to stay String, got %s", got)
+	}
+	if got := fields["session"].Type.String(); got != "UUID" {
+		t.Errorf("Expected the uuid option to select UUID, got %s", got)
+	}
+
+	input := GenerateInputObject[scalarTestUUIDNames]("ScalarTestUUIDNamesInput").Fields()
+	if got := input["uuid"].Type.String(); got != "String" {
+		t.Errorf("Expected an input field named uuid to stay String, got %s", got)
+	}
+	if got := input["session"].Type.String(); got != "UUID" {
+		t.Errorf("Expected the uuid option to select UUID for inputs, got %s", got)
+	}
+}
+
+func TestInt64Scalar(t *testing.T) {
+	const large = int64(1) << 40
+
+	advert := NewResolver[scalarTestAdvert]("scalarAdvert").
+		WithArgsFromStruct(scalarTestAdvertPatch{}).
+		WithResolver(func(p ResolveParams) (*scalarTestAdvert, error) {
+			var args scalarTestAdvertPatch
+			if err := mapArgsToStruct(p.Args, &args); err != nil {
+				return nil, err
+			}
+			return &scalarTestAdvert{ID: args.ID, TotalElements: args.TotalElements, Pages: 3}, nil
+		}).
+		BuildQuery()
+
+	update := NewResolver[scalarTestAdvert]("updateScalarAdvert").
+		WithInputObject(scalarTestAdvertPatch{}).
+		WithResolver(func(p ResolveParams) (*scalarTestAdvert, error) {
+			var input scalarTestAdvertPatch
+			if err := GetArg(p, "input", &input); err != nil {
+				return nil, err
+			}
+			return &scalarTestAdvert{ID: input.ID, TotalElements: input.TotalElements}, nil
+		}).
+		BuildMutation()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:    []QueryField{advert},
+		MutationFields: []MutationField{update},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{"id: Int64", "totalElements: Int64", "pages: Int"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected schema to contain %q, got:\n%s", want, sdl)
+		}
+	}
+	inputFields := schema.Type("scalarTestAdvertPatchInput").(*graphql.InputObject).Fields()
+	if inputFields["id"].Type != Int64 {
+		t.Errorf("Expected input field id to use Int64, got %v", inputFields["id"].Type)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		field     string
+		wantError bool
+	}{
+		{
+			name:  "argument literal",
+			query: fmt.Sprintf(`{ scalarAdvert(id: %d, totalElements: "%d") { id totalElements } }`, large, large),
+			field: "scalarAdvert",
+		},
+		{
+			name:      "argument variable as string",
+			query:     `query($id: Int64) { scalarAdvert(id: $id, totalElements: 1099511627776) { id totalElements } }`,
+			variables: map[string]interface{}{"id": strconv.FormatInt(large, 10)},
+			field:     "scalarAdvert",
+		},
+		{
+			name:  "input object",
+			query: fmt.Sprintf(`mutation { updateScalarAdvert(input: {id: %d, totalElements: %d}) { id totalElements } }`, large, large),
+			field: "updateScalarAdvert",
+		},
+		{
+			name:      "invalid value",
+			query:     `{ scalarAdvert(id: "12ab") { id } }`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  tt.query,
+				VariableValues: tt.variables,
+			})
+
+			if tt.wantError {
+				if len(result.Errors) == 0 {
+					t.Errorf("Expected error, got %v", result.Data)
+				}
+				return
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			got := result.Data.(map[string]interface{})[tt.field].(map[string]interface{})
+			if got["id"] != large {
+				t.Errorf("Expected id %d, got %v (%T)", large, got["id"], got["id"])
+			}
+			if got["totalElements"] != large {
+				t.Errorf("Expected totalElements %d, got %v (%T)", large, got["totalElements"], got["totalElements"])
+			}
+		})
+	}
+}
+
+type scalarTestEvent struct {
+	ID       int                    `json:"id"`
+	Metadata map[string]interface{} `json:"metadata"`
+	Payload  interface{}            `json:"payload"`
+}
+
+type scalarTestAudit struct {
+	Details interface{}       `json:"details"`
+	Labels  map[string]string `json:"labels"`
+}
+
+type scalarTestEventInput struct {
+	Metadata map[string]interface{} `json:"metadata"`
+	Payload  interface{}            `json:"payload"`
+}
+
+func TestJSONScalar_MapAndInterfaceFields(t *testing.T) {
+	event := NewResolver[scalarTestEvent]("scalarEvent").
+		WithInputObject(scalarTestEventInput{}).
+		WithResolver(func(p ResolveParams) (*scalarTestEvent, error) {
+			var input scalarTestEventInput
+			if err := GetArg(p, "input", &input); err != nil {
+				return nil, err
+			}
+			return &scalarTestEvent{ID: 1, Metadata: input.Metadata, Payload: input.Payload}, nil
+		}).
+		BuildQuery()
+	audit := NewResolver[scalarTestAudit]("scalarAudit").
+		WithResolver(func(p ResolveParams) (*scalarTestAudit, error) {
+			return &scalarTestAudit{Details: []interface{}{"a", 1}, Labels: map[string]string{"env": "prod"}}, nil
+		}).
+		BuildQuery()
+	settings := NewResolver[map[string]map[string]string]("scalarSettings").
+		WithResolver(func(p ResolveParams) (*map[string]map[string]string, error) {
+			settings := map[string]map[string]string{"theme": {"mode": "dark"}}
+			return &settings, nil
+		}).
+		BuildQuery()
+
+	// Map and interface{} fields and map entry values in one schema share the JSON scalar
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{event, audit, settings}}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{"metadata: JSON", "payload: JSON", "details: JSON", "labels: JSON", "scalarSettings: [JSONEntry]"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected schema to contain %q, got:\n%s", want, sdl)
+		}
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ scalarEvent(input: {metadata: {source: "web", tags: ["a", "b"]}, payload: 42}) { metadata payload } scalarAudit { details labels } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	data := result.Data.(map[string]interface{})
+	got := fmt.Sprint(data["scalarEvent"], data["scalarAudit"])
+	want := "map[metadata:map[source:web tags:[a b]] payload:42] map[details:[a 1] labels:map[env:prod]]"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
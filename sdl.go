@@ -0,0 +1,385 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// SDL builds the schema and returns it in GraphQL Schema Definition Language.
+// This is useful for client code generation and for checking schema changes into source control.
+//
+// Example:
+//
+//	sdl, err := graph.NewSchemaBuilder(params).SDL()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("schema.graphql", []byte(sdl), 0644)
+func (sb *SchemaBuilder) SDL() (string, error) {
+	schema, err := sb.Build()
+	if err != nil {
+		return "", err
+	}
+	return PrintSchema(&schema), nil
+}
+
+// PrintSchema returns the SDL representation of a schema.
+// Built-in scalars, built-in directives and introspection types are omitted,
+// and types are printed in alphabetical order so the output is stable.
+func PrintSchema(schema *graphql.Schema) string {
+	var blocks []string
+
+	if def := printSchemaDefinition(schema); def != "" {
+		blocks = append(blocks, def)
+	}
+
+	for _, directive := range schema.Directives() {
+		if isBuiltInDirective(directive.Name) {
+			continue
+		}
+		blocks = append(blocks, printDirectiveDefinition(directive))
+	}
+
+	typeMap := schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || isBuiltInScalar(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if block := printType(typeMap[name]); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// isBuiltInScalar reports whether name is one of the scalars defined by the GraphQL spec
+func isBuiltInScalar(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+// isBuiltInDirective reports whether name is one of the directives defined by the GraphQL spec
+func isBuiltInDirective(name string) bool {
+	switch name {
+	case "include", "skip", "deprecated", "specifiedBy":
+		return true
+	}
+	return false
+}
+
+// printSchemaDefinition prints the schema block, which is only needed when
+// the root types don't use the conventional Query/Mutation/Subscription names
+func printSchemaDefinition(schema *graphql.Schema) string {
+	query := schema.QueryType()
+	mutation := schema.MutationType()
+	subscription := schema.SubscriptionType()
+
+	if (query == nil || query.Name() == "Query") &&
+		(mutation == nil || mutation.Name() == "Mutation") &&
+		(subscription == nil || subscription.Name() == "Subscription") {
+		return ""
+	}
+
+	var lines []string
+	if query != nil {
+		lines = append(lines, "  query: "+query.Name())
+	}
+	if mutation != nil {
+		lines = append(lines, "  mutation: "+mutation.Name())
+	}
+	if subscription != nil {
+		lines = append(lines, "  subscription: "+subscription.Name())
+	}
+	return "schema {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+// printType prints a single named type definition
+func printType(t graphql.Type) string {
+	switch typ := t.(type) {
+	case *graphql.Scalar:
+		return printDescription(typ.Description(), "") + "scalar " + typ.Name()
+
+	case *graphql.Object:
+		header := "type " + typ.Name()
+		if interfaces := typ.Interfaces(); len(interfaces) > 0 {
+			names := make([]string, len(interfaces))
+			for i, iface := range interfaces {
+				names[i] = iface.Name()
+			}
+			header += " implements " + strings.Join(names, " & ")
+		}
+		return printDescription(typ.Description(), "") + header + printFields(typ.Fields(), typeFieldDirectives(typ))
+
+	case *graphql.Interface:
+		return printDescription(typ.Description(), "") + "interface " + typ.Name() + printFields(typ.Fields(), nil)
+
+	case *graphql.Union:
+		members := typ.Types()
+		names := make([]string, len(members))
+		for i, member := range members {
+			names[i] = member.Name()
+		}
+		return printDescription(typ.Description(), "") + "union " + typ.Name() + " = " + strings.Join(names, " | ")
+
+	case *graphql.Enum:
+		var lines []string
+		for _, value := range typ.Values() {
+			lines = append(lines, printDescription(value.Description, "  ")+"  "+value.Name+printDeprecated(value.DeprecationReason))
+		}
+		return printDescription(typ.Description(), "") + "enum " + typ.Name() + " {\n" + strings.Join(lines, "\n") + "\n}"
+
+	case *graphql.InputObject:
+		fieldMap := typ.Fields()
+		names := make([]string, 0, len(fieldMap))
+		for name := range fieldMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		directives := typeFieldDirectives(typ)
+		var lines []string
+		for _, name := range names {
+			field := fieldMap[name]
+			line := "  " + name + ": " + field.Type.String()
+			if field.DefaultValue != nil {
+				line += " = " + printValue(field.DefaultValue, field.Type)
+			}
+			line += printFieldDirectives(directives[name], false)
+			lines = append(lines, printDescription(field.Description(), "  ")+line)
+		}
+		return printDescription(typ.Description(), "") + "input " + typ.Name() + " {\n" + strings.Join(lines, "\n") + "\n}"
+	}
+
+	return ""
+}
+
+// printFields prints the field block of an object or interface type,
+// including any directives declared through struct tags
+func printFields(fieldMap graphql.FieldDefinitionMap, directives map[string][]FieldDirective) string {
+	names := make([]string, 0, len(fieldMap))
+	for name := range fieldMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		field := fieldMap[name]
+		line := "  " + name + printArgs(field.Args, "  ") + ": " + field.Type.String() + printDeprecated(field.DeprecationReason) +
+			printFieldDirectives(directives[name], field.DeprecationReason != "")
+		lines = append(lines, printDescription(field.Description, "  ")+line)
+	}
+	return " {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+// printArgs prints a field or directive argument list
+func printArgs(args []*graphql.Argument, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	sorted := make([]*graphql.Argument, len(args))
+	copy(sorted, args)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+
+	parts := make([]string, len(sorted))
+	hasDescription := false
+	for i, arg := range sorted {
+		part := arg.Name() + ": " + arg.Type.String()
+		if arg.DefaultValue != nil {
+			part += " = " + printValue(arg.DefaultValue, arg.Type)
+		}
+		if arg.Description() != "" {
+			hasDescription = true
+		}
+		parts[i] = part
+	}
+
+	if !hasDescription {
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	// Arguments with descriptions are printed one per line
+	var lines []string
+	for i, arg := range sorted {
+		lines = append(lines, printDescription(arg.Description(), indent+"  ")+indent+"  "+parts[i])
+	}
+	return "(\n" + strings.Join(lines, "\n") + "\n" + indent + ")"
+}
+
+// printDirectiveDefinition prints a custom directive definition
+func printDirectiveDefinition(directive *graphql.Directive) string {
+	return printDescription(directive.Description, "") +
+		"directive @" + directive.Name + printArgs(directive.Args, "") +
+		" on " + strings.Join(directive.Locations, " | ")
+}
+
+// printDeprecated prints the @deprecated directive for a deprecation reason
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == graphql.DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + printString(reason) + ")"
+}
+
+// printDescription prints a description as a block string on the lines before a definition
+func printDescription(description string, indent string) string {
+	if description == "" {
+		return ""
+	}
+	escaped := strings.ReplaceAll(description, `"""`, `\"""`)
+	if !strings.Contains(escaped, "\n") {
+		return indent + `"""` + escaped + `"""` + "\n"
+	}
+	lines := strings.Split(escaped, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return indent + `"""` + "\n" + strings.Join(lines, "\n") + "\n" + indent + `"""` + "\n"
+}
+
+// printValue prints a Go value as a GraphQL literal of the given input type.
+// Default values declared through struct tags are strings, so they are
+// converted to the literal form of scalar types such as Int and Boolean.
+func printValue(value interface{}, t graphql.Input) string {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType.(graphql.Input)
+	}
+
+	if value == nil {
+		return "null"
+	}
+
+	switch typ := t.(type) {
+	case *graphql.Enum:
+		for _, enumValue := range typ.Values() {
+			if reflect.DeepEqual(enumValue.Value, value) {
+				return enumValue.Name
+			}
+		}
+		if s, ok := value.(string); ok {
+			return s
+		}
+
+	case *graphql.List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return printValue(value, typ.OfType.(graphql.Input))
+		}
+		items := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items[i] = printValue(rv.Index(i).Interface(), typ.OfType.(graphql.Input))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+
+	case *graphql.InputObject:
+		if m, ok := value.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(m))
+			for key := range m {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			fields := typ.Fields()
+			parts := make([]string, 0, len(keys))
+			for _, key := range keys {
+				var fieldType graphql.Input = graphql.String
+				if field, exists := fields[key]; exists {
+					fieldType = field.Type
+				}
+				parts = append(parts, key+": "+printValue(m[key], fieldType))
+			}
+			return "{" + strings.Join(parts, ", ") + "}"
+		}
+
+	case *graphql.Scalar:
+		if s, ok := value.(string); ok {
+			switch typ.Name() {
+			case "Int", "Int64":
+				if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+					return s
+				}
+			case "Float":
+				if _, err := strconv.ParseFloat(s, 64); err == nil {
+					return s
+				}
+			case "Boolean":
+				if b, err := strconv.ParseBool(s); err == nil {
+					return strconv.FormatBool(b)
+				}
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return printString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32, float64:
+		return fmt.Sprintf("%v", v)
+	}
+
+	// Fall back to the JSON representation for anything else
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return printString(fmt.Sprintf("%v", value))
+}
+
+// printString prints a GraphQL string literal. Go escapes such as \a, \v or \x00
+// are not valid GraphQL, so quotes, backslashes and control characters are escaped
+// with the escape sequences of the GraphQL spec.
+func printString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
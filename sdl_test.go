@@ -0,0 +1,280 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestSchemaBuilder_SDL(t *testing.T) {
+	type SDLBook struct {
+		ID    int    `json:"id" graphql:"id,required"`
+		Title string `json:"title" description:"Book title"`
+	}
+	type SDLBookFilter struct {
+		Title string `json:"title"`
+		Limit int    `json:"limit" default:"10"`
+	}
+
+	books := NewResolver[[]SDLBook]("sdlBooks").
+		AsList().
+		WithArgsFromStruct(SDLBookFilter{}).
+		WithResolver(func(p ResolveParams) (*[]SDLBook, error) {
+			return &[]SDLBook{}, nil
+		}).
+		BuildQuery()
+
+	sdl, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{books},
+	}).SDL()
+	if err != nil {
+		t.Fatalf("SDL() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"type Query {",
+		"sdlBooks(limit: Int = 10, title: String): [SDLBook]",
+		"type SDLBook {",
+		"id: Int!",
+		`"""Book title"""`,
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+
+	if _, err := parser.Parse(parser.ParseParams{Source: sdl}); err != nil {
+		t.Errorf("SDL does not parse: %v\n%s", err, sdl)
+	}
+}
+
+func TestPrintSchema_EnumsAndDeprecation(t *testing.T) {
+	status := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SDLStatus",
+		Values: graphql.EnumValueConfigMap{
+			"ACTIVE":   &graphql.EnumValueConfig{Value: "active"},
+			"ARCHIVED": &graphql.EnumValueConfig{Value: "archived", DeprecationReason: "Use ACTIVE"},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"status": &graphql.Field{
+					Type: status,
+					Args: graphql.FieldConfigArgument{
+						"default": &graphql.ArgumentConfig{Type: status, DefaultValue: "active"},
+					},
+				},
+				"legacy": &graphql.Field{Type: graphql.String, DeprecationReason: "Use status"},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{
+		"enum SDLStatus {",
+		`ARCHIVED @deprecated(reason: "Use ACTIVE")`,
+		`legacy: String @deprecated(reason: "Use status")`,
+		"status(default: SDLStatus = ACTIVE): SDLStatus",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+}
+
+func TestPrintSchema_StringEscapes(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"search": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"separator": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "a\x01b\tc\\d"},
+					},
+				},
+				"legacy": &graphql.Field{Type: graphql.String, DeprecationReason: "Use \"search\"\vinstead"},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{
+		`search(separator: String = "a\u0001b\tc\\d"): String`,
+		`legacy: String @deprecated(reason: "Use \"search\"\u000Binstead")`,
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		t.Fatalf("SDL does not parse: %v\n%s", err, sdl)
+	}
+	for _, definition := range document.Definitions {
+		object, ok := definition.(*ast.ObjectDefinition)
+		if !ok || object.Name.Value != "Query" {
+			continue
+		}
+		for _, field := range object.Fields {
+			if field.Name.Value != "search" {
+				continue
+			}
+			if got := field.Arguments[0].DefaultValue.GetValue(); got != "a\x01b\tc\\d" {
+				t.Errorf("Expected the default value to parse back unchanged, got %q", got)
+			}
+		}
+	}
+}
+
+func TestNewHTTP_SDLPath(t *testing.T) {
+	handler := NewHTTP(&GraphContext{SDLPath: "/graphql/schema.graphql"})
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql/schema.graphql", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/graphql") {
+		t.Errorf("Expected application/graphql content type, got %s", ct)
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: w.Body.String()})
+	if err != nil {
+		t.Fatalf("SDL does not parse: %v\n%s", err, w.Body.String())
+	}
+
+	types := map[string]bool{}
+	for _, def := range doc.Definitions {
+		if obj, ok := def.(*ast.ObjectDefinition); ok {
+			types[obj.Name.Value] = true
+		}
+	}
+	if !types["Query"] || !types["Mutation"] {
+		t.Errorf("Expected Query and Mutation types in SDL, got %v", types)
+	}
+}
+
+func TestNewHTTP_SDLPath_IntrospectionDisabled(t *testing.T) {
+	handler := NewHTTP(&GraphContext{
+		SDLPath:         "/graphql/schema.graphql",
+		ValidationRules: []ValidationRule{NewNoIntrospectionRule()},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql/schema.graphql", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 when introspection is disabled, got %d", w.Code)
+	}
+}
+
+func TestSDL_FieldDirectivesFromTags(t *testing.T) {
+	type DirectiveProduct struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price" directive:"@auth(requires: ADMIN) @cacheControl(maxAge: 60)"`
+		SKU   string  `json:"sku" directive:"@deprecated(reason: \"Use code\")"`
+		Code  string  `json:"code" directive:"not a directive"`
+	}
+
+	product := NewResolver[DirectiveProduct]("directiveProduct").
+		WithResolver(func(p ResolveParams) (*DirectiveProduct, error) {
+			return &DirectiveProduct{}, nil
+		}).
+		BuildQuery()
+
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{product},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	sdl := PrintSchema(&schema)
+	for _, want := range []string{
+		"  price: Float @auth(requires: ADMIN) @cacheControl(maxAge: 60)",
+		`  sku: String @deprecated(reason: "Use code")`,
+		"  code: String\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+	if strings.Count(sdl, "@deprecated") != 1 {
+		t.Errorf("Expected @deprecated to be printed once:\n%s", sdl)
+	}
+
+	// The @deprecated directive is also visible through introspection
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ __type(name: "DirectiveProduct") { fields(includeDeprecated: true) { name isDeprecated deprecationReason } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Introspection errors: %v", result.Errors)
+	}
+	fields := result.Data.(map[string]interface{})["__type"].(map[string]interface{})["fields"].([]interface{})
+	for _, f := range fields {
+		field := f.(map[string]interface{})
+		if field["name"] == "sku" && (field["isDeprecated"] != true || field["deprecationReason"] != "Use code") {
+			t.Errorf("Expected sku to be deprecated with reason 'Use code', got %v", field)
+		}
+	}
+}
+
+func TestSDL_FieldDirectivesOfSameNamedTypes(t *testing.T) {
+	buildSDL := func(field QueryField) string {
+		schema, err := NewSchemaBuilder(SchemaBuilderParams{QueryFields: []QueryField{field}}).Build()
+		if err != nil {
+			t.Fatalf("Failed to build schema: %v", err)
+		}
+		return PrintSchema(&schema)
+	}
+
+	var first, second string
+	{
+		type DirectiveTwin struct {
+			Price float64 `json:"price" directive:"@auth(requires: ADMIN)"`
+		}
+		first = buildSDL(NewResolver[DirectiveTwin]("directiveTwin").
+			WithResolver(func(p ResolveParams) (*DirectiveTwin, error) {
+				return &DirectiveTwin{}, nil
+			}).
+			BuildQuery())
+	}
+	{
+		type DirectiveTwin struct {
+			Price float64 `json:"price" directive:"@cacheControl(maxAge: 60)"`
+			Stock int     `json:"stock"`
+		}
+		second = buildSDL(NewResolver[DirectiveTwin]("directiveTwin").
+			WithResolver(func(p ResolveParams) (*DirectiveTwin, error) {
+				return &DirectiveTwin{}, nil
+			}).
+			BuildQuery())
+	}
+
+	if !strings.Contains(first, "price: Float @auth(requires: ADMIN)") || strings.Contains(first, "@cacheControl") {
+		t.Errorf("Expected the first schema to print its own directive:\n%s", first)
+	}
+	if !strings.Contains(second, "price: Float @cacheControl(maxAge: 60)") || strings.Contains(second, "@auth") {
+		t.Errorf("Expected the second schema to print its own directive:\n%s", second)
+	}
+}
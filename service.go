@@ -0,0 +1,326 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ServiceTags can be implemented by a service passed to NewService to control how its
+// methods are exposed. It maps method names to tags of the form "kind[,name=fieldName]",
+// where kind is "query", "mutation" or "subscription". Use "-" to skip a method.
+// Methods without a tag are exposed as queries named after the method.
+//
+// Example:
+//
+//	func (s *UserService) GraphQLTags() map[string]string {
+//	    return map[string]string{
+//	        "CreateUser":  "mutation",
+//	        "UserCreated": "subscription,name=onUserCreated",
+//	        "Close":       "-",
+//	    }
+//	}
+type ServiceTags interface {
+	GraphQLTags() map[string]string
+}
+
+// Service exposes the methods of a Go type as GraphQL fields.
+// Create it with NewService and add its fields to a schema with Register.
+type Service[T any] struct {
+	queries       []QueryField
+	mutations     []MutationField
+	subscriptions []SubscriptionField
+}
+
+// serviceField is a field generated from a service method
+type serviceField struct {
+	name  string
+	field *graphql.Field
+}
+
+func (f *serviceField) Serve() *graphql.Field {
+	return f.field
+}
+
+func (f *serviceField) Name() string {
+	return f.name
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// NewService builds GraphQL fields from the exported methods of service.
+// Each method becomes a field named after the method (first letter lower-cased).
+// Supported method signatures:
+//
+//	func (s *Svc) Method(ctx context.Context) (R, error)
+//	func (s *Svc) Method(ctx context.Context, args A) (R, error)
+//
+// A is a struct (or pointer to struct) whose fields become the field arguments,
+// using the same tags as WithArgsFromStruct. For subscriptions R must be a channel;
+// every value received from it is sent to the client. Exported methods with other
+// signatures are ignored. Tag methods by implementing ServiceTags.
+//
+// Example:
+//
+//	type UserService struct{ repo *UserRepo }
+//
+//	func (s *UserService) User(ctx context.Context, args struct{ ID int `json:"id"` }) (*User, error) {
+//	    return s.repo.Get(ctx, args.ID)
+//	}
+//
+//	func (s *UserService) CreateUser(ctx context.Context, args CreateUserArgs) (*User, error) {
+//	    return s.repo.Create(ctx, args)
+//	}
+//
+//	svc, err := graph.NewService(&UserService{repo: repo})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	params := graph.SchemaBuilderParams{}
+//	svc.Register(&params)
+//	schema, err := graph.NewSchemaBuilder(params).Build()
+func NewService[T any](service T) (*Service[T], error) {
+	serviceValue := reflect.ValueOf(service)
+	if !serviceValue.IsValid() {
+		return nil, fmt.Errorf("service must not be nil")
+	}
+
+	var tags map[string]string
+	if tagged, ok := any(service).(ServiceTags); ok {
+		tags = tagged.GraphQLTags()
+	}
+
+	serviceType := serviceValue.Type()
+	s := &Service[T]{}
+	for i := 0; i < serviceType.NumMethod(); i++ {
+		method := serviceType.Method(i)
+		if method.Name == "GraphQLTags" {
+			continue
+		}
+
+		kind, fieldName := parseServiceTag(tags[method.Name])
+		if kind == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = toCamelCase(method.Name)
+		}
+
+		// Skip methods that don't look like resolvers
+		methodValue := serviceValue.Method(i)
+		argType, resultType, ok := serviceMethodSignature(methodValue.Type())
+		if !ok {
+			if _, tagged := tags[method.Name]; tagged {
+				return nil, fmt.Errorf("method %s has an unsupported signature for a GraphQL field", method.Name)
+			}
+			continue
+		}
+
+		switch kind {
+		case "query", "":
+			field, err := buildServiceField(method.Name, methodValue, argType, resultType)
+			if err != nil {
+				return nil, err
+			}
+			s.queries = append(s.queries, &serviceField{name: fieldName, field: field})
+		case "mutation":
+			field, err := buildServiceField(method.Name, methodValue, argType, resultType)
+			if err != nil {
+				return nil, err
+			}
+			s.mutations = append(s.mutations, &serviceField{name: fieldName, field: field})
+		case "subscription":
+			field, err := buildServiceSubscription(method.Name, methodValue, argType, resultType)
+			if err != nil {
+				return nil, err
+			}
+			s.subscriptions = append(s.subscriptions, &serviceField{name: fieldName, field: field})
+		default:
+			return nil, fmt.Errorf("method %s has unknown tag kind '%s'", method.Name, kind)
+		}
+	}
+
+	return s, nil
+}
+
+// QueryFields returns the query fields generated from the service.
+func (s *Service[T]) QueryFields() []QueryField {
+	return s.queries
+}
+
+// MutationFields returns the mutation fields generated from the service.
+func (s *Service[T]) MutationFields() []MutationField {
+	return s.mutations
+}
+
+// SubscriptionFields returns the subscription fields generated from the service.
+func (s *Service[T]) SubscriptionFields() []SubscriptionField {
+	return s.subscriptions
+}
+
+// Register appends the service fields to the schema builder params.
+func (s *Service[T]) Register(params *SchemaBuilderParams) {
+	params.QueryFields = append(params.QueryFields, s.queries...)
+	params.MutationFields = append(params.MutationFields, s.mutations...)
+	params.SubscriptionFields = append(params.SubscriptionFields, s.subscriptions...)
+}
+
+// parseServiceTag splits a service tag into its kind and optional field name
+func parseServiceTag(tag string) (kind string, name string) {
+	parts := strings.Split(tag, ",")
+	kind = strings.TrimSpace(parts[0])
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "name=") {
+			name = strings.TrimPrefix(part, "name=")
+		}
+	}
+	return kind, name
+}
+
+// serviceMethodSignature checks that a method has a supported resolver signature
+// and returns its argument type (nil when the method takes only a context) and result type
+func serviceMethodSignature(methodType reflect.Type) (argType reflect.Type, resultType reflect.Type, ok bool) {
+	if methodType.NumIn() < 1 || methodType.NumIn() > 2 || methodType.In(0) != contextType {
+		return nil, nil, false
+	}
+	if methodType.NumOut() != 2 || methodType.Out(1) != errorType {
+		return nil, nil, false
+	}
+
+	if methodType.NumIn() == 2 {
+		argType = methodType.In(1)
+		structType := argType
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return nil, nil, false
+		}
+	}
+
+	return argType, methodType.Out(0), true
+}
+
+// callServiceMethod decodes the GraphQL arguments and calls the service method
+func callServiceMethod(method reflect.Value, argType reflect.Type, p graphql.ResolveParams) (reflect.Value, error) {
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	in := []reflect.Value{reflect.ValueOf(ctx)}
+	if argType != nil {
+		structType := argType
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+
+		args := reflect.New(structType)
+		if err := mapArgsToStruct(p.Args, args.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to map arguments: %w", err)
+		}
+
+		if argType.Kind() == reflect.Ptr {
+			in = append(in, args)
+		} else {
+			in = append(in, args.Elem())
+		}
+	}
+
+	out := method.Call(in)
+	if errValue := out[1]; !errValue.IsNil() {
+		return reflect.Value{}, errValue.Interface().(error)
+	}
+	return out[0], nil
+}
+
+// buildServiceField builds a query or mutation field for a service method
+func buildServiceField(methodName string, method reflect.Value, argType reflect.Type, resultType reflect.Type) (*graphql.Field, error) {
+	gen := NewFieldGenerator[any]()
+	outputType := gen.getBaseGraphQLType(resultType, nil)
+	if outputType == nil {
+		return nil, fmt.Errorf("method %s returns unsupported type %s", methodName, resultType)
+	}
+
+	field := &graphql.Field{
+		Type: outputType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			result, err := callServiceMethod(method, argType, p)
+			if err != nil {
+				return nil, err
+			}
+			if (result.Kind() == reflect.Ptr || result.Kind() == reflect.Slice || result.Kind() == reflect.Map) && result.IsNil() {
+				return nil, nil
+			}
+			return result.Interface(), nil
+		},
+	}
+	if argType != nil {
+		field.Args = generateArgsFromType(argType)
+	}
+	return field, nil
+}
+
+// buildServiceSubscription builds a subscription field for a service method returning a channel
+func buildServiceSubscription(methodName string, method reflect.Value, argType reflect.Type, resultType reflect.Type) (*graphql.Field, error) {
+	if resultType.Kind() != reflect.Chan || resultType.ChanDir() == reflect.SendDir {
+		return nil, fmt.Errorf("subscription method %s must return a receive channel, got %s", methodName, resultType)
+	}
+
+	gen := NewFieldGenerator[any]()
+	outputType := gen.getBaseGraphQLType(resultType.Elem(), nil)
+	if outputType == nil {
+		return nil, fmt.Errorf("method %s returns unsupported event type %s", methodName, resultType.Elem())
+	}
+
+	field := &graphql.Field{
+		Type: outputType,
+		Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+			ctx := p.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			events, err := callServiceMethod(method, argType, p)
+			if err != nil {
+				return nil, err
+			}
+
+			// graphql-go requires a chan interface{}, so forward typed events
+			output := make(chan interface{})
+			go func() {
+				defer close(output)
+				cases := []reflect.SelectCase{
+					{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+					{Dir: reflect.SelectRecv, Chan: events},
+				}
+				for {
+					chosen, event, ok := reflect.Select(cases)
+					if chosen == 0 || !ok {
+						return
+					}
+					select {
+					case output <- event.Interface():
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return output, nil
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source, nil
+		},
+	}
+	if argType != nil {
+		field.Args = generateArgsFromType(argType)
+	}
+	return field, nil
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type serviceTestTodo struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type serviceTestTodoService struct {
+	todos []serviceTestTodo
+}
+
+func (s *serviceTestTodoService) Todo(ctx context.Context, args struct {
+	ID int `json:"id" graphql:"id,required"`
+}) (*serviceTestTodo, error) {
+	for _, todo := range s.todos {
+		if todo.ID == args.ID {
+			return &todo, nil
+		}
+	}
+	return nil, fmt.Errorf("todo %d not found", args.ID)
+}
+
+func (s *serviceTestTodoService) AddTodo(ctx context.Context, args struct {
+	Title string `json:"title" graphql:"title,required"`
+}) (*serviceTestTodo, error) {
+	todo := serviceTestTodo{ID: len(s.todos) + 1, Title: args.Title}
+	s.todos = append(s.todos, todo)
+	return &todo, nil
+}
+
+// Reset has no context parameter, so it is not exposed
+func (s *serviceTestTodoService) Reset() {
+	s.todos = nil
+}
+
+func (s *serviceTestTodoService) GraphQLTags() map[string]string {
+	return map[string]string{
+		"AddTodo": "mutation,name=createTodo",
+	}
+}
+
+func TestNewService(t *testing.T) {
+	svc, err := NewService(&serviceTestTodoService{})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if len(svc.QueryFields()) != 1 || svc.QueryFields()[0].Name() != "todo" {
+		t.Fatalf("Expected a single 'todo' query field, got %d fields", len(svc.QueryFields()))
+	}
+	if len(svc.MutationFields()) != 1 || svc.MutationFields()[0].Name() != "createTodo" {
+		t.Fatalf("Expected a single 'createTodo' mutation field, got %d fields", len(svc.MutationFields()))
+	}
+
+	params := SchemaBuilderParams{}
+	svc.Register(&params)
+	schema, err := NewSchemaBuilder(params).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { createTodo(title: "write tests") { id title } }`,
+		Context:       context.Background(),
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Mutation errors: %v", result.Errors)
+	}
+	created := result.Data.(map[string]interface{})["createTodo"].(map[string]interface{})
+	if created["id"] != 1 || created["title"] != "write tests" {
+		t.Errorf("Unexpected mutation result: %v", created)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ todo(id: 1) { title } }`,
+		Context:       context.Background(),
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query errors: %v", result.Errors)
+	}
+	todo := result.Data.(map[string]interface{})["todo"].(map[string]interface{})
+	if todo["title"] != "write tests" {
+		t.Errorf("Expected title 'write tests', got %v", todo["title"])
+	}
+
+	// Errors returned by the method surface as GraphQL errors
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ todo(id: 42) { title } }`,
+		Context:       context.Background(),
+	})
+	if len(result.Errors) == 0 {
+		t.Error("Expected error for missing todo")
+	}
+}
+
+type serviceTestBadTags struct{}
+
+func (s *serviceTestBadTags) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+func (s *serviceTestBadTags) GraphQLTags() map[string]string {
+	return map[string]string{"Ping": "subscription"}
+}
+
+func TestNewService_SubscriptionRequiresChannel(t *testing.T) {
+	if _, err := NewService(&serviceTestBadTags{}); err == nil {
+		t.Error("Expected error for subscription method not returning a channel")
+	}
+}
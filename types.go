@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/graphql-go/graphql"
 )
@@ -65,36 +67,87 @@ type GraphContext struct {
 
 	// EnableSubscriptions: Enable WebSocket support for GraphQL subscriptions
 	// Default: false (subscriptions disabled)
-	// Requires PubSub to be configured
+	// Requires PubSub to be configured: while PubSub is nil or its health check
+	// (PubSubHealthChecker) fails, subscription requests get 503 Service Unavailable
 	EnableSubscriptions bool
 
+	// SubscriptionBufferSize: Default output buffer size for every subscription (default: 10)
+	// Subscriptions configured with WithSubscriptionBuffer keep their own size
+	SubscriptionBufferSize int
+
+	// SubscriptionOverflowPolicy: Default policy applied when a subscription buffer is full
+	// Default: OverflowBlock (wait for the client to catch up)
+	SubscriptionOverflowPolicy OverflowPolicy
+
+	// SubscriptionMetrics: Default metrics for subscriptions without their own WithMetrics
+	// Events discarded by the overflow policy are reported via EventDropped
+	SubscriptionMetrics SubscriptionMetrics
+
+	// CoalesceSubscriptions: Share one execution between identical subscriptions (same
+	// query and variables) opened on one WebSocket connection
+	CoalesceSubscriptions bool
+
+	// MaxSubscriptionsPerConnection: Maximum number of active subscriptions of one
+	// WebSocket connection (default: 0, no limit)
+	MaxSubscriptionsPerConnection int
+
 	// WebSocketPath: Path for WebSocket endpoint (default: same as HTTP endpoint)
 	// If not set, WebSocket connections will be handled on the same path as HTTP
 	WebSocketPath string
 
+	// SDLPath: Path that serves the schema in SDL format (e.g. "/graphql/schema.graphql")
+	// Useful for client code generation tooling. Disabled when empty.
+	// The endpoint follows the introspection policy: it is blocked whenever
+	// the configured validation rules block introspection queries.
+	SDLPath string
+
+	// JSONSchemaPath: Path that serves the argument and input type constraints as a JSON
+	// Schema document (e.g. "/graphql/schema.json"), see ExportJSONSchema
+	// Follows the same introspection policy as SDLPath. Disabled when empty.
+	JSONSchemaPath string
+
 	// WebSocketCheckOrigin: Custom function to check WebSocket upgrade origin
 	// If not provided, all origins are allowed (only use in development!)
+	// Required when tokens are read from cookies (ExtractCookieToken), since browsers
+	// send them with upgrade requests started by any site
 	WebSocketCheckOrigin func(r *http.Request) bool
 
 	// Pretty: Pretty-print JSON responses
 	Pretty bool
 
+	// OmitNullFields: Remove null fields from the data of responses, for clients that
+	// can't handle explicit nulls. Nulls in lists and a null "data" are kept.
+	OmitNullFields bool
+
 	// GraphiQL: Enable GraphiQL interface (deprecated, use Playground instead)
 	GraphiQL bool
 
 	// Playground: Enable GraphQL Playground interface
 	Playground bool
 
-	// DEBUG mode skips validation and sanitization for easier development
+	// PlaygroundSubscriptionQuery: Query shown in the default Playground tab when
+	// EnableSubscriptions is true. Defaults to an example for the first subscription field.
+	// The Playground's subscription endpoint is set to the WebSocket URL automatically.
+	PlaygroundSubscriptionQuery string
+
+	// DEBUG mode skips validation and sanitization for easier development, and fails
+	// the schema build for misconfigured fields (see SchemaBuilderParams.Debug)
 	// Default: false (validation enabled)
 	DEBUG bool
 
+	// IncludeStackTraceInDebug: In DEBUG mode, add the stack trace of the failing
+	// resolver call ("stacktrace") and the messages of the errors it wraps
+	// ("errorChain") to the extensions of resolver errors. Ignored outside DEBUG mode
+	IncludeStackTraceInDebug bool
+
 	// RootObjectFn: Custom function to set up root object for each request
 	// Called before token extraction and user details fetching
 	RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
 
 	// TokenExtractorFn: Custom token extraction from request
 	// If not provided, default Bearer token extraction will be used
+	// Built-in extractors: ExtractBearerToken, ExtractCookieToken and ExtractQueryToken;
+	// ChainExtractors tries several in order and uses the first token found
 	TokenExtractorFn func(*http.Request) string
 
 	// UserDetailsFn: Custom user details fetching based on token
@@ -122,9 +175,16 @@ type GraphContext struct {
 	// EnableValidation: Enable query validation (depth, complexity, introspection checks)
 	// Default: false (validation disabled)
 	// When enabled: Max depth=10, Max aliases=4, Max complexity=200, Introspection blocked
+	// The limits can be raised with MaxDepth, MaxAliases and MaxComplexity
 	// DEPRECATED: Use ValidationRules for more control
 	EnableValidation bool
 
+	// MaxDepth, MaxAliases, MaxComplexity: Limits of the default security rules used
+	// with EnableValidation (defaults: 10, 4 and 200). Ignored when ValidationRules is set
+	MaxDepth      int
+	MaxAliases    int
+	MaxComplexity int
+
 	// ValidationRules: Custom validation rules (takes precedence over EnableValidation)
 	// Set to nil or empty slice to disable validation
 	// Example:
@@ -137,14 +197,100 @@ type GraphContext struct {
 	//   }
 	ValidationRules []ValidationRule
 
+	// ComplexityEstimator: Custom query cost function used by NewMaxComplexityRule
+	// and the rate limit rules instead of the built-in estimate (optional)
+	// Receives the parsed query and the request variables
+	ComplexityEstimator ComplexityEstimator
+
+	// EchoCostHeader: Report the estimated query cost in the X-GraphQL-Cost response header
+	// The cost is computed with ComplexityEstimator when set, otherwise with the built-in estimate
+	// Batched requests report the total cost of their operations
+	EchoCostHeader bool
+
 	// ValidationOptions: Configure validation behavior (optional)
 	// Default: StopOnFirstError=false, SkipInDebug=true
 	ValidationOptions *ValidationOptions
 
+	// MaxUploadSize: Maximum size in bytes of a multipart (file upload) request body
+	// Larger requests are rejected with 413 Request Entity Too Large
+	// Default: 0 (no limit)
+	MaxUploadSize int64
+
+	// CORS: Cross-origin settings for browser clients (optional)
+	// When set, NewHTTP answers OPTIONS preflight requests with 204 and adds the
+	// Access-Control-Allow-* headers to responses for allowed origins
+	CORS *CORSConfig
+
+	// EnableCompression: Compress responses with gzip or deflate when the client accepts it
+	// Default: false (responses are not compressed)
+	EnableCompression bool
+
+	// CompressionThreshold: Minimum response size in bytes that is compressed (default: 1024)
+	CompressionThreshold int
+
+	// RequestTimeout: Maximum duration of a request (optional)
+	// The context seen by resolvers is cancelled after the timeout, and requests that
+	// have not completed by then are answered with 408 Request Timeout
+	// Default: 0 (no limit)
+	RequestTimeout time.Duration
+
+	// MaxBatchSize: Maximum number of operations in a batched request, whose JSON body is
+	// an array of operations. Larger batches are rejected with 400 Bad Request
+	// Default: 0 (no limit)
+	MaxBatchSize int
+
+	// Logger: Structured logger used by the handler (default: slog.Default())
+	Logger *slog.Logger
+
+	// AccessLog: Log one entry per HTTP request with method, path, operation name,
+	// user id, status, response size and duration
+	// The user id is taken from the UserDetailsFn details when they implement
+	// HasIDInterface or are a map with an "id" key
+	AccessLog bool
+
+	// StringSanitization: Trim, normalize or HTML-escape the string arguments of every
+	// resolver before it runs (optional). Fields configured with WithStringSanitization
+	// use their own settings
+	StringSanitization *StringSanitization
+
 	// EnableSanitization: Enable response sanitization (removes field suggestions from errors)
 	// Default: false (sanitization disabled)
 	// Prevents information disclosure by removing "Did you mean X?" suggestions
 	EnableSanitization bool
+
+	// ErrorFormatter: Formats each GraphQL error before it is written (optional)
+	// It runs for validation errors and for the errors of executed operations, with
+	// the error returned by the resolver (or the *ValidationError) as err. The returned
+	// map is written as the error; "locations" and "path" are added when it has none.
+	// Returning nil drops the error from the response. Used by NewHTTP only.
+	//
+	// Example:
+	//
+	//	ErrorFormatter: func(err error) map[string]interface{} {
+	//	    if errors.Is(err, sql.ErrNoRows) {
+	//	        return map[string]interface{}{
+	//	            "message":    "not found",
+	//	            "extensions": map[string]interface{}{"code": "NOT_FOUND"},
+	//	        }
+	//	    }
+	//	    return map[string]interface{}{"message": "internal error"}
+	//	}
+	ErrorFormatter func(err error) map[string]interface{}
+
+	// BeforeExecute: Called before each operation is executed (optional)
+	// The returned context is used for execution and is visible to resolvers via p.Context.
+	// Returning nil keeps the original context.
+	//
+	// Example:
+	//
+	//	BeforeExecute: func(ctx context.Context, params *graphql.Params) context.Context {
+	//	    return context.WithValue(ctx, startKey{}, time.Now())
+	//	}
+	BeforeExecute func(ctx context.Context, params *graphql.Params) context.Context
+
+	// AfterExecute: Called with the result of each executed operation (optional)
+	// ctx is the execution context, including values added by BeforeExecute
+	AfterExecute func(ctx context.Context, result *graphql.Result)
 }
 
 type ResolveParams graphql.ResolveParams
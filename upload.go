@@ -0,0 +1,229 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/handler"
+)
+
+// defaultMultipartMemory is the amount of multipart data kept in memory while
+// parsing an upload request; larger files are buffered to temporary files.
+const defaultMultipartMemory = 32 << 20
+
+// Upload represents a file uploaded through a GraphQL multipart request
+// (https://github.com/jaydenseric/graphql-multipart-request-spec).
+//
+// Declare upload arguments with the UploadScalar type and read them in resolvers:
+//
+//	NewResolver[File]("uploadFile").
+//	    WithArgs(graphql.FieldConfigArgument{
+//	        "file": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graph.UploadScalar)},
+//	    }).
+//	    WithResolver(func(p graph.ResolveParams) (*File, error) {
+//	        upload := p.Args["file"].(*graph.Upload)
+//	        data, err := io.ReadAll(upload.File)
+//	        ...
+//	    }).BuildMutation()
+//
+// Struct fields of type *Upload use UploadScalar, so upload arguments can also be
+// declared with WithArgsFromStruct:
+//
+//	type UploadAvatarArgs struct {
+//	    File *graph.Upload `graphql:"file,required"`
+//	}
+type Upload struct {
+	// File is the content of the uploaded file
+	File io.Reader
+
+	// Filename is the name of the file as sent by the client
+	Filename string
+
+	// ContentType is the MIME type of the file as sent by the client
+	ContentType string
+
+	// Size is the size of the file in bytes
+	Size int64
+}
+
+// UploadScalar is the GraphQL scalar type for file uploads.
+// Values are only accepted through variables of a multipart request; uploads
+// cannot be written inline in the query document or returned from a field.
+var UploadScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Upload",
+	Description: "The `Upload` scalar type represents a file upload sent as part of a multipart request",
+	Serialize: func(value interface{}) interface{} {
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case *Upload:
+			return v
+		case Upload:
+			return &v
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return nil
+	},
+})
+
+// uploadTooLarge reports whether parsing a multipart request failed because the
+// body exceeded GraphContext.MaxUploadSize
+func uploadTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// uploadTooLargeMessage is the error message for requests above GraphContext.MaxUploadSize
+func uploadTooLargeMessage(limit int64) string {
+	return fmt.Sprintf("upload exceeds the maximum size of %d bytes", limit)
+}
+
+// isMultipartRequest reports whether the request uses multipart/form-data encoding
+func isMultipartRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// parseMultipartRequest parses a GraphQL multipart request into request options.
+// The "operations" field holds the JSON operation, and the "map" field maps each
+// file field to the variable paths it should be injected into, e.g.:
+//
+//	operations: {"query": "mutation($files: [Upload!]!) {...}", "variables": {"files": [null, null]}}
+//	map:        {"0": ["variables.files.0"], "1": ["variables.files.1"]}
+//
+// The returned uploads hold open files, which the caller closes with closeUploads
+// once the operation has executed.
+func parseMultipartRequest(r *http.Request, maxMemory int64) (*handler.RequestOptions, []*Upload, error) {
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse multipart request: %w", err)
+	}
+
+	operations := r.FormValue("operations")
+	if operations == "" {
+		return nil, nil, fmt.Errorf("multipart request is missing the 'operations' field")
+	}
+
+	var opts handler.RequestOptions
+	if err := json.Unmarshal([]byte(operations), &opts); err != nil {
+		return nil, nil, fmt.Errorf("invalid 'operations' field: %w", err)
+	}
+	if opts.Variables == nil {
+		opts.Variables = make(map[string]interface{})
+	}
+
+	var fileMap map[string][]string
+	if mapField := r.FormValue("map"); mapField != "" {
+		if err := json.Unmarshal([]byte(mapField), &fileMap); err != nil {
+			return nil, nil, fmt.Errorf("invalid 'map' field: %w", err)
+		}
+	}
+
+	var uploads []*Upload
+	for key, paths := range fileMap {
+		files := r.MultipartForm.File[key]
+		if len(files) == 0 {
+			closeUploads(uploads)
+			return nil, nil, fmt.Errorf("file '%s' referenced in 'map' was not uploaded", key)
+		}
+
+		upload, err := newUpload(files[0])
+		if err != nil {
+			closeUploads(uploads)
+			return nil, nil, err
+		}
+		uploads = append(uploads, upload)
+
+		for _, path := range paths {
+			if err := injectUpload(opts.Variables, path, upload); err != nil {
+				closeUploads(uploads)
+				return nil, nil, err
+			}
+		}
+	}
+
+	return &opts, uploads, nil
+}
+
+// closeUploads closes the files opened for uploads
+func closeUploads(uploads []*Upload) {
+	for _, upload := range uploads {
+		if closer, ok := upload.File.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+}
+
+// newUpload opens a multipart file header as an Upload
+func newUpload(header *multipart.FileHeader) (*Upload, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file '%s': %w", header.Filename, err)
+	}
+
+	return &Upload{
+		File:        file,
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}, nil
+}
+
+// injectUpload sets the upload at a dotted object path such as "variables.file"
+// or "variables.input.files.1" inside the operation variables.
+// List indices must already exist in the operations JSON (as null placeholders).
+func injectUpload(variables map[string]interface{}, path string, upload *Upload) error {
+	segments := strings.Split(path, ".")
+	if len(segments) < 2 || segments[0] != "variables" {
+		return fmt.Errorf("invalid upload path '%s': must start with 'variables.'", path)
+	}
+	segments = segments[1:]
+
+	var current interface{} = variables
+	for i, segment := range segments {
+		last := i == len(segments)-1
+
+		switch container := current.(type) {
+		case map[string]interface{}:
+			if last {
+				container[segment] = upload
+				return nil
+			}
+			next, ok := container[segment]
+			if !ok || next == nil {
+				return fmt.Errorf("invalid upload path '%s': '%s' not found in variables", path, segment)
+			}
+			current = next
+
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(container) {
+				return fmt.Errorf("invalid upload path '%s': index '%s' out of range", path, segment)
+			}
+			if last {
+				container[index] = upload
+				return nil
+			}
+			current = container[index]
+
+		default:
+			return fmt.Errorf("invalid upload path '%s': '%s' is not an object or list", path, segment)
+		}
+	}
+
+	return nil
+}
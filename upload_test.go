@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestInjectUpload(t *testing.T) {
+	upload := &Upload{Filename: "a.txt"}
+
+	tests := []struct {
+		name      string
+		variables map[string]interface{}
+		path      string
+		check     func(vars map[string]interface{}) bool
+		wantError bool
+	}{
+		{
+			name:      "top-level variable",
+			variables: map[string]interface{}{"file": nil},
+			path:      "variables.file",
+			check: func(vars map[string]interface{}) bool {
+				return vars["file"] == upload
+			},
+		},
+		{
+			name:      "list index",
+			variables: map[string]interface{}{"files": []interface{}{nil, nil}},
+			path:      "variables.files.1",
+			check: func(vars map[string]interface{}) bool {
+				files := vars["files"].([]interface{})
+				return files[0] == nil && files[1] == upload
+			},
+		},
+		{
+			name: "nested input object list",
+			variables: map[string]interface{}{
+				"input": map[string]interface{}{"attachments": []interface{}{nil}},
+			},
+			path: "variables.input.attachments.0",
+			check: func(vars map[string]interface{}) bool {
+				input := vars["input"].(map[string]interface{})
+				return input["attachments"].([]interface{})[0] == upload
+			},
+		},
+		{
+			name:      "missing variables prefix",
+			variables: map[string]interface{}{"file": nil},
+			path:      "file",
+			wantError: true,
+		},
+		{
+			name:      "index out of range",
+			variables: map[string]interface{}{"files": []interface{}{nil}},
+			path:      "variables.files.3",
+			wantError: true,
+		},
+		{
+			name:      "missing intermediate object",
+			variables: map[string]interface{}{},
+			path:      "variables.input.file",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := injectUpload(tt.variables, tt.path, upload)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("injectUpload() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && !tt.check(tt.variables) {
+				t.Errorf("injectUpload() did not set upload at %s: %v", tt.path, tt.variables)
+			}
+		})
+	}
+}
+
+func TestNewHTTP_MultipartUploadList(t *testing.T) {
+	var received []string
+
+	uploadFiles := NewResolver[string]("uploadFiles").
+		WithArgs(graphql.FieldConfigArgument{
+			"files": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(UploadScalar))),
+			},
+		}).
+		WithResolver(func(p ResolveParams) (*string, error) {
+			for _, f := range p.Args["files"].([]interface{}) {
+				upload := f.(*Upload)
+				data, err := io.ReadAll(upload.File)
+				if err != nil {
+					return nil, err
+				}
+				received = append(received, upload.Filename+":"+string(data))
+			}
+			result := strings.Join(received, ",")
+			return &result, nil
+		}).
+		BuildMutation()
+
+	handler := NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields:    []QueryField{getDefaultHelloQuery()},
+			MutationFields: []MutationField{uploadFiles},
+		},
+		EnableValidation: true,
+	})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("operations", `{"query":"mutation($files: [Upload!]!) { uploadFiles(files: $files) }","variables":{"files":[null,null]}}`)
+	_ = writer.WriteField("map", `{"0":["variables.files.0"],"1":["variables.files.1"]}`)
+	for i, content := range []string{"first", "second"} {
+		part, _ := writer.CreateFormFile(string(rune('0'+i)), content+".txt")
+		_, _ = part.Write([]byte(content))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["errors"] != nil {
+		t.Fatalf("Unexpected errors: %v", response["errors"])
+	}
+
+	data := response["data"].(map[string]interface{})
+	if data["uploadFiles"] != "first.txt:first,second.txt:second" {
+		t.Errorf("Expected both files in order, got %v", data["uploadFiles"])
+	}
+}
+
+func TestParseMultipartRequest_CloseUploads(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("operations", `{"query":"mutation($file: Upload!) { upload(file: $file) }","variables":{"file":null}}`)
+	_ = writer.WriteField("map", `{"0":["variables.file"]}`)
+	part, _ := writer.CreateFormFile("0", "large.txt")
+	_, _ = part.Write(bytes.Repeat([]byte("x"), 1024))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Without memory for file contents, the upload is opened from a temporary file
+	_, uploads, err := parseMultipartRequest(req, 0)
+	if err != nil {
+		t.Fatalf("parseMultipartRequest() error = %v", err)
+	}
+	defer func() { _ = req.MultipartForm.RemoveAll() }()
+	if len(uploads) != 1 {
+		t.Fatalf("Expected 1 upload, got %d", len(uploads))
+	}
+
+	closeUploads(uploads)
+
+	if _, err := uploads[0].File.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected reading a closed upload to fail")
+	}
+}
+
+func TestNewHTTP_MultipartInvalidMap(t *testing.T) {
+	handler := NewHTTP(&GraphContext{DEBUG: true})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("operations", `{"query":"{ hello }"}`)
+	_ = writer.WriteField("map", `{"0":["variables.file"]}`)
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for missing file, got %d", w.Code)
+	}
+}
+
+func TestNewHTTP_MultipartUploadStructArgs(t *testing.T) {
+	type UploadAvatarArgs struct {
+		File *Upload `graphql:"file,required"`
+	}
+
+	uploadAvatar := NewArgsResolver[string, UploadAvatarArgs]("uploadAvatar").
+		WithResolver(func(ctx context.Context, p ResolveParams, args UploadAvatarArgs) (*string, error) {
+			data, err := io.ReadAll(args.File.File)
+			if err != nil {
+				return nil, err
+			}
+			result := args.File.Filename + ":" + args.File.ContentType + ":" + string(data)
+			return &result, nil
+		}).
+		BuildMutation()
+
+	newHandler := func(maxUploadSize int64) http.HandlerFunc {
+		return NewHTTP(&GraphContext{
+			SchemaParams: &SchemaBuilderParams{
+				QueryFields:    []QueryField{getDefaultHelloQuery()},
+				MutationFields: []MutationField{uploadAvatar},
+			},
+			MaxUploadSize: maxUploadSize,
+		})
+	}
+	newRequest := func(content string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("operations", `{"query":"mutation($file: Upload!) { uploadAvatar(file: $file) }","variables":{"file":null}}`)
+		_ = writer.WriteField("map", `{"0":["variables.file"]}`)
+		part, _ := writer.CreateFormFile("0", "avatar.png")
+		_, _ = part.Write([]byte(content))
+		_ = writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	newHandler(1<<20).ServeHTTP(w, newRequest("png"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Errors) > 0 || response.Data["uploadAvatar"] != "avatar.png:application/octet-stream:png" {
+		t.Errorf("Unexpected response %s", w.Body.String())
+	}
+
+	// Requests above MaxUploadSize are rejected
+	w = httptest.NewRecorder()
+	newHandler(512).ServeHTTP(w, newRequest(strings.Repeat("x", 1024)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status 413, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "upload exceeds the maximum size of 512 bytes") {
+		t.Errorf("Expected a clear size error, got %s", w.Body.String())
+	}
+}
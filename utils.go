@@ -1,10 +1,13 @@
 package graph
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
 // QueryField represents a GraphQL query field with its configuration.
@@ -202,6 +205,16 @@ func GetArg(p ResolveParams, key string, target interface{}) error {
 		}
 	}
 
+	// IDs may arrive as strings or integers; coerce between the two
+	if targetValue := reflect.ValueOf(target); targetValue.Kind() == reflect.Ptr && !targetValue.IsNil() {
+		if ok, err := coerceIDValue(targetValue.Elem(), value); ok {
+			if err != nil {
+				return fmt.Errorf("failed to convert argument '%s': %w", key, err)
+			}
+			return nil
+		}
+	}
+
 	// For complex types, use JSON marshaling/unmarshaling
 	jsonBytes, err := json.Marshal(value)
 	if err != nil {
@@ -215,6 +228,127 @@ func GetArg(p ResolveParams, key string, target interface{}) error {
 	return nil
 }
 
+// GetArgWithPresence works like GetArg but also reports which fields of an input
+// object argument the client actually sent. graphql-go drops null values while
+// coercing arguments, so a field explicitly set to null is indistinguishable from an
+// omitted one in p.Args; the presence map tells them apart, which is what patch
+// mutations need ("set bio to null" vs "leave bio unchanged").
+//
+// Presence of fields passed through variables requires the raw request variables,
+// which NewHTTP attaches automatically (see WithRawVariables).
+//
+// Returns an error if the argument was not sent at all or type conversion fails.
+// If the argument itself is explicitly null, target is left untouched and the
+// presence map is empty.
+//
+// Example:
+//
+//	var input UpdateUserInput // Bio *string `json:"bio"`
+//	present, err := graph.GetArgWithPresence(p, "input", &input)
+//	if err != nil {
+//	    return nil, err
+//	}
+//	if present["bio"] {
+//	    user.Bio = input.Bio // nil when the client sent bio: null
+//	}
+func GetArgWithPresence(p ResolveParams, key string, target interface{}) (map[string]bool, error) {
+	present, sent := argumentPresence(p, key)
+
+	if _, exists := p.Args[key]; !exists {
+		if !sent {
+			return nil, fmt.Errorf("argument '%s' not found", key)
+		}
+		// Explicit null for the whole argument
+		return present, nil
+	}
+
+	if err := GetArg(p, key, target); err != nil {
+		return nil, err
+	}
+	return present, nil
+}
+
+type rawVariablesKey struct{}
+
+// WithRawVariables returns a context carrying the request variables exactly as the
+// client sent them, including null values. NewHTTP does this for every request;
+// call it yourself when executing operations with graphql.Do.
+//
+// Example:
+//
+//	result := graphql.Do(graphql.Params{
+//	    Schema:         schema,
+//	    RequestString:  query,
+//	    VariableValues: variables,
+//	    Context:        graph.WithRawVariables(ctx, variables),
+//	})
+func WithRawVariables(ctx context.Context, variables map[string]interface{}) context.Context {
+	return context.WithValue(ctx, rawVariablesKey{}, variables)
+}
+
+// rawVariable looks up a variable as sent by the client. It falls back to the
+// coerced variable values when no raw variables are attached to the context.
+func rawVariable(p ResolveParams, name string) (interface{}, bool) {
+	if p.Context != nil {
+		if raw, ok := p.Context.Value(rawVariablesKey{}).(map[string]interface{}); ok {
+			value, exists := raw[name]
+			return value, exists
+		}
+	}
+	value, exists := p.Info.VariableValues[name]
+	return value, exists
+}
+
+// argumentPresence reports whether the argument key was sent and which of its
+// input object fields were present in the request
+func argumentPresence(p ResolveParams, key string) (map[string]bool, bool) {
+	for _, fieldAST := range p.Info.FieldASTs {
+		for _, arg := range fieldAST.Arguments {
+			if arg.Name == nil || arg.Name.Value != key {
+				continue
+			}
+
+			present := make(map[string]bool)
+			switch value := arg.Value.(type) {
+			case *ast.ObjectValue:
+				for _, field := range value.Fields {
+					// Fields bound to variables are present only if the variable was sent
+					if variable, ok := field.Value.(*ast.Variable); ok {
+						if _, exists := rawVariable(p, variable.Name.Value); !exists {
+							continue
+						}
+					}
+					present[field.Name.Value] = true
+				}
+				return present, true
+			case *ast.Variable:
+				raw, exists := rawVariable(p, value.Name.Value)
+				if !exists {
+					return nil, false
+				}
+				if fields, ok := raw.(map[string]interface{}); ok {
+					for name := range fields {
+						present[name] = true
+					}
+				}
+				return present, true
+			default:
+				return present, true
+			}
+		}
+	}
+
+	// Not sent by the client; a default value may still apply
+	if fields, ok := p.Args[key].(map[string]interface{}); ok {
+		present := make(map[string]bool, len(fields))
+		for name := range fields {
+			present[name] = true
+		}
+		return present, true
+	}
+	return nil, false
+}
+
 // GetArgString safely extracts a string argument from p.Args.
 // Returns an error if the argument doesn't exist or is not a string.
 //
@@ -252,6 +386,8 @@ func GetArgInt(p ResolveParams, key string) (int, error) {
 	switch v := value.(type) {
 	case int:
 		return v, nil
+	case int64:
+		return int(v), nil
 	case float64:
 		return int(v), nil
 	default:
@@ -278,3 +414,100 @@ func GetArgBool(p ResolveParams, key string) (bool, error) {
 
 	return b, nil
 }
+
+// GetArgFloat safely extracts a float argument from p.Args.
+// Handles int arguments as well, since Float accepts integer literals.
+// Returns an error if the argument doesn't exist or is not a number.
+//
+// Example:
+//
+//	price, err := graph.GetArgFloat(p, "price")
+func GetArgFloat(p ResolveParams, key string) (float64, error) {
+	value, exists := p.Args[key]
+	if !exists {
+		return 0, fmt.Errorf("argument '%s' not found", key)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("argument '%s' is not a number", key)
+	}
+}
+
+// GetArgStringSlice safely extracts a list of strings from p.Args.
+// graphql-go passes list arguments as []interface{}; each element must be a string.
+// Returns an error if the argument doesn't exist, is not a list, or has an element
+// that is not a string.
+//
+// Example:
+//
+//	tags, err := graph.GetArgStringSlice(p, "tags")
+func GetArgStringSlice(p ResolveParams, key string) ([]string, error) {
+	value, exists := p.Args[key]
+	if !exists {
+		return nil, fmt.Errorf("argument '%s' not found", key)
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		strs := make([]string, len(v))
+		for i, elem := range v {
+			str, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("argument '%s' element %d is not a string", key, i)
+			}
+			strs[i] = str
+		}
+		return strs, nil
+	default:
+		return nil, fmt.Errorf("argument '%s' is not a list", key)
+	}
+}
+
+// GetArgIntSlice safely extracts a list of ints from p.Args.
+// graphql-go passes list arguments as []interface{}; each element must be a number.
+// Returns an error if the argument doesn't exist, is not a list, or has an element
+// that is not a number.
+//
+// Example:
+//
+//	ids, err := graph.GetArgIntSlice(p, "ids")
+func GetArgIntSlice(p ResolveParams, key string) ([]int, error) {
+	value, exists := p.Args[key]
+	if !exists {
+		return nil, fmt.Errorf("argument '%s' not found", key)
+	}
+
+	switch v := value.(type) {
+	case []int:
+		return v, nil
+	case []interface{}:
+		ints := make([]int, len(v))
+		for i, elem := range v {
+			// Handle both int and float64 (JSON numbers are parsed as float64)
+			switch n := elem.(type) {
+			case int:
+				ints[i] = n
+			case int64:
+				ints[i] = int(n)
+			case float64:
+				ints[i] = int(n)
+			default:
+				return nil, fmt.Errorf("argument '%s' element %d is not a number", key, i)
+			}
+		}
+		return ints, nil
+	default:
+		return nil, fmt.Errorf("argument '%s' is not a list", key)
+	}
+}
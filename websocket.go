@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -21,6 +22,11 @@ type WebSocketManager struct {
 	authFn        func(r *http.Request) (interface{}, error)
 	pubsub        PubSub
 	rootObjectFn  func(ctx context.Context, r *http.Request) map[string]interface{}
+	subDefaults   SubscriptionDefaults
+	coalesce      bool
+	maxSubscriptions int
+	keepAlive     time.Duration
+	initTimeout   time.Duration
 }
 
 // Connection represents a single WebSocket connection.
@@ -30,6 +36,7 @@ type Connection struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
 	subscriptions map[string]context.CancelFunc // subscription ID -> cancel function
+	coalesced     map[string]*coalescedSubscription // subscription key -> shared subscription
 	mu            sync.RWMutex
 	userDetails   interface{}
 	rootValue     map[string]interface{}
@@ -37,6 +44,37 @@ type Connection struct {
 	messageChan   chan *WSMessage
 	acknowledged  bool
 	pingTicker    *time.Ticker
+	protocol      string // negotiated subprotocol, empty when the client proposed none
+	request       *http.Request // upgrade request, passed to AuthFn on connection_init
+}
+
+// connectionInit is the connection_init payload carried by the AuthFn request context
+type connectionInit struct {
+	payload map[string]interface{}
+	// headerFromPayload: the Authorization header was copied from the payload
+	headerFromPayload bool
+}
+
+// connectionInitKey stores the *connectionInit of an AuthFn request
+type connectionInitKey struct{}
+
+// ConnectionInitPayload returns the payload of the connection_init message from the
+// context of the request passed to WebSocketParams.AuthFn, or nil.
+//
+// Example:
+//
+//	AuthFn: func(r *http.Request) (interface{}, error) {
+//	    token, _ := graph.ConnectionInitPayload(r.Context())["apiKey"].(string)
+//	    return validateAPIKey(token)
+//	},
+func ConnectionInitPayload(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	if init, ok := ctx.Value(connectionInitKey{}).(*connectionInit); ok {
+		return init.payload
+	}
+	return nil
 }
 
 // WSMessage represents a GraphQL WebSocket Protocol message.
@@ -47,6 +85,18 @@ type WSMessage struct {
 	Payload map[string]interface{} `json:"payload,omitempty"`
 }
 
+// WebSocket subprotocols. The client proposes them in the Sec-WebSocket-Protocol
+// header, and the handler answers with the first one it supports.
+const (
+	// SubprotocolGraphQLTransportWS is the protocol of the graphql-ws library:
+	// connection_init, subscribe, next, complete, ping and pong
+	SubprotocolGraphQLTransportWS = "graphql-transport-ws"
+
+	// SubprotocolGraphQLWS is the legacy protocol of subscriptions-transport-ws:
+	// connection_init, start, data, stop, ka and connection_terminate
+	SubprotocolGraphQLWS = "graphql-ws"
+)
+
 // GraphQL WebSocket Protocol message types
 // Supports both graphql-ws (new) and subscriptions-transport-ws (legacy) protocols
 const (
@@ -58,6 +108,7 @@ const (
 	// Client -> Server (subscriptions-transport-ws - legacy)
 	MessageTypeStart = "start" // Legacy equivalent of "subscribe"
 	MessageTypeStop  = "stop"  // Legacy equivalent of "complete"
+	MessageTypeConnectionTerminate = "connection_terminate" // Legacy connection close
 
 	// Server -> Client (graphql-ws)
 	MessageTypeConnectionAck = "connection_ack"
@@ -85,7 +136,14 @@ type WebSocketParams struct {
 	CheckOrigin func(r *http.Request) bool
 
 	// AuthFn: Authentication function to extract user details from request
-	// Called during connection_init phase
+	// Called during connection_init phase with the upgrade request, whose context
+	// carries the connection_init payload (see ConnectionInitPayload). An
+	// "authorization" payload value is copied to the Authorization header when the
+	// upgrade request has none.
+	// It is called for every connection_init, also when the client sent no token, so
+	// tokens can come from the upgrade request (cookies, query parameters). Earlier
+	// versions skipped AuthFn without a payload token: to keep accepting anonymous
+	// connections, return nil details and no error when the request has no token
 	AuthFn func(r *http.Request) (interface{}, error)
 
 	// RootObjectFn: Custom function to set up root object for each connection
@@ -93,11 +151,40 @@ type WebSocketParams struct {
 	RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
 
 	// PingInterval: Interval for sending ping messages (default: 30 seconds)
-	// Set to 0 to disable automatic pinging
+	//
+	// Deprecated: Use KeepAliveInterval
 	PingInterval time.Duration
 
 	// ConnectionTimeout: Timeout for connection_init message (default: 10 seconds)
+	//
+	// Deprecated: Use ConnectionInitTimeout
 	ConnectionTimeout time.Duration
+
+	// KeepAliveInterval: Interval for sending keep-alive messages ("ping" and the legacy
+	// "ka") and WebSocket ping frames once the connection is initialized.
+	// A connection that sends nothing, not even a pong frame, for two intervals is
+	// closed and its subscriptions are stopped
+	// Default: PingInterval, or 30 seconds. A negative value disables keep-alive
+	KeepAliveInterval time.Duration
+
+	// ConnectionInitTimeout: Time a client has to send connection_init after the
+	// upgrade; connections that don't are closed with code 4408
+	// Default: ConnectionTimeout, or 10 seconds. A negative value disables the timeout
+	ConnectionInitTimeout time.Duration
+
+	// SubscriptionDefaults: Buffer size, overflow policy and metrics applied to
+	// subscriptions that don't configure their own
+	SubscriptionDefaults SubscriptionDefaults
+
+	// CoalesceSubscriptions: Share one execution between identical subscriptions (same
+	// query and variables) of a connection, fanning its events out to each of them
+	// Default: false (every subscription is executed separately)
+	CoalesceSubscriptions bool
+
+	// MaxSubscriptionsPerConnection: Maximum number of active subscriptions of a
+	// connection. Further subscribe messages are answered with an error message
+	// Default: 0 (no limit)
+	MaxSubscriptionsPerConnection int
 }
 
 // NewWebSocketHandler creates an HTTP handler for WebSocket connections.
@@ -127,6 +214,12 @@ func NewWebSocketHandler(params WebSocketParams) http.HandlerFunc {
 	if params.ConnectionTimeout == 0 {
 		params.ConnectionTimeout = 10 * time.Second
 	}
+	if params.KeepAliveInterval == 0 {
+		params.KeepAliveInterval = params.PingInterval
+	}
+	if params.ConnectionInitTimeout == 0 {
+		params.ConnectionInitTimeout = params.ConnectionTimeout
+	}
 	if params.CheckOrigin == nil {
 		// Allow all origins (development only!)
 		params.CheckOrigin = func(r *http.Request) bool { return true }
@@ -135,7 +228,7 @@ func NewWebSocketHandler(params WebSocketParams) http.HandlerFunc {
 	mgr := &WebSocketManager{
 		upgrader: websocket.Upgrader{
 			CheckOrigin:     params.CheckOrigin,
-			Subprotocols:    []string{"graphql-transport-ws", "graphql-ws"},
+			Subprotocols:    []string{SubprotocolGraphQLTransportWS, SubprotocolGraphQLWS},
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
 		},
@@ -143,6 +236,11 @@ func NewWebSocketHandler(params WebSocketParams) http.HandlerFunc {
 		authFn:       params.AuthFn,
 		pubsub:       params.PubSub,
 		rootObjectFn: params.RootObjectFn,
+		subDefaults:  params.SubscriptionDefaults,
+		coalesce:     params.CoalesceSubscriptions,
+		maxSubscriptions: params.MaxSubscriptionsPerConnection,
+		keepAlive:     params.KeepAliveInterval,
+		initTimeout:   params.ConnectionInitTimeout,
 	}
 
 	return mgr.HandleWebSocket
@@ -171,9 +269,12 @@ func (m *WebSocketManager) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		ctx:           ctx,
 		cancel:        cancel,
 		subscriptions: make(map[string]context.CancelFunc),
+		coalesced:     make(map[string]*coalescedSubscription),
 		manager:       m,
 		messageChan:   make(chan *WSMessage, 100),
 		rootValue:     make(map[string]interface{}),
+		protocol:      ws.Subprotocol(),
+		request:       r,
 	}
 
 	// Set up root value if RootObjectFn is provided
@@ -197,19 +298,63 @@ func (c *Connection) start() {
 	// Start write pump (handles outgoing messages)
 	go c.writePump()
 
+	// Close connections that never send connection_init
+	if c.manager.initTimeout > 0 {
+		go c.closeUninitialized(c.manager.initTimeout)
+	}
+
 	// Start read pump (handles incoming messages) - this blocks until connection closes
 	c.readPump()
 }
 
+// closeUninitialized closes the connection with code 4408 when it is not initialized
+// within timeout, as the graphql-ws protocol specifies
+func (c *Connection) closeUninitialized(timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		c.mu.RLock()
+		acknowledged := c.acknowledged
+		c.mu.RUnlock()
+		if acknowledged {
+			return
+		}
+		closeMessage := websocket.FormatCloseMessage(4408, "Connection initialisation timeout")
+		_ = c.ws.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+		c.cancel()
+		_ = c.ws.Close()
+	case <-c.ctx.Done():
+	}
+}
+
+// extendReadDeadline gives the client two keep-alive intervals to send its next
+// message or pong frame, once keep-alive has started
+func (c *Connection) extendReadDeadline() {
+	c.mu.RLock()
+	started := c.pingTicker != nil
+	c.mu.RUnlock()
+	if started {
+		_ = c.ws.SetReadDeadline(time.Now().Add(2 * c.manager.keepAlive))
+	}
+}
+
 // readPump reads messages from the WebSocket connection.
 func (c *Connection) readPump() {
 	defer c.cancel()
 
+	c.ws.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		return nil
+	})
+
 	for {
 		var msg WSMessage
 		if err := c.ws.ReadJSON(&msg); err != nil {
 			return
 		}
+		c.extendReadDeadline()
 
 		// Handle message
 		c.handleMessage(&msg)
@@ -232,8 +377,20 @@ func (c *Connection) writePump() {
 	}
 }
 
+// sendsModern reports whether server messages use the graphql-transport-ws types
+func (c *Connection) sendsModern() bool {
+	return c.protocol != SubprotocolGraphQLWS
+}
+
+// sendsLegacy reports whether server messages use the subscriptions-transport-ws
+// types. Clients that negotiated no subprotocol get the messages of both protocols.
+func (c *Connection) sendsLegacy() bool {
+	return c.protocol != SubprotocolGraphQLTransportWS
+}
+
 // handleMessage processes incoming WebSocket messages.
-// Supports both graphql-ws and subscriptions-transport-ws (legacy) protocols.
+// Supports both graphql-ws and subscriptions-transport-ws (legacy) protocols; the
+// messages sent back use the types of the negotiated subprotocol.
 func (c *Connection) handleMessage(msg *WSMessage) {
 	switch msg.Type {
 	case MessageTypeConnectionInit:
@@ -255,11 +412,35 @@ func (c *Connection) handleMessage(msg *WSMessage) {
 	case MessageTypePing:
 		c.sendMessage(&WSMessage{Type: MessageTypePong})
 
+	case MessageTypePong:
+		// Answer to a keep-alive ping; the read deadline is already extended
+
+	case MessageTypeConnectionTerminate:
+		c.cancel()
+
 	default:
 		c.sendError(msg.ID, fmt.Sprintf("Unknown message type: %s", msg.Type))
 	}
 }
 
+// authRequest returns the request passed to authFn: the upgrade request with the
+// connection_init payload in its context, and the payload's authorization token as its
+// Authorization header when it has none
+func (c *Connection) authRequest(payload map[string]interface{}, authToken string) *http.Request {
+	init := &connectionInit{payload: payload}
+	var req *http.Request
+	if c.request != nil {
+		req = c.request.Clone(context.WithValue(c.ctx, connectionInitKey{}, init))
+	} else {
+		req = (&http.Request{Header: http.Header{}}).WithContext(context.WithValue(c.ctx, connectionInitKey{}, init))
+	}
+	if authToken != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", authToken)
+		init.headerFromPayload = true
+	}
+	return req
+}
+
 // handleConnectionInit processes connection initialization.
 func (c *Connection) handleConnectionInit(msg *WSMessage) {
 	if c.acknowledged {
@@ -278,17 +459,15 @@ func (c *Connection) handleConnectionInit(msg *WSMessage) {
 	}
 
 	// Authenticate if authFn is provided
-	if c.manager.authFn != nil && authToken != "" {
-		// Create a fake request with the auth token for authFn
-		fakeReq := &http.Request{
-			Header: http.Header{
-				"Authorization": []string{authToken},
-			},
-		}
-
-		userDetails, err := c.manager.authFn(fakeReq)
+	if c.manager.authFn != nil {
+		userDetails, err := c.manager.authFn(c.authRequest(msg.Payload, authToken))
 		if err != nil {
-			c.sendError("", fmt.Sprintf("Authentication failed: %s", err.Error()))
+			message := fmt.Sprintf("Authentication failed: %s", err.Error())
+			if c.protocol == SubprotocolGraphQLWS {
+				c.sendMessage(&WSMessage{Type: MessageTypeConnectionError, Payload: map[string]interface{}{"message": message}})
+			} else {
+				c.sendError("", message)
+			}
 			c.cancel()
 			return
 		}
@@ -297,22 +476,41 @@ func (c *Connection) handleConnectionInit(msg *WSMessage) {
 	}
 
 	// Mark as acknowledged
+	c.mu.Lock()
 	c.acknowledged = true
+	c.mu.Unlock()
 
 	// Send connection_ack
 	c.sendMessage(&WSMessage{Type: MessageTypeConnectionAck})
 
+	if c.manager.keepAlive <= 0 {
+		return
+	}
+
 	// Start keep-alive ticker (supports both protocols)
-	c.pingTicker = time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(c.manager.keepAlive)
+	c.mu.Lock()
+	c.pingTicker = ticker
+	c.mu.Unlock()
+	c.extendReadDeadline()
 	go func() {
 		for {
 			select {
-			case <-c.pingTicker.C:
-				// Send both ping (graphql-ws) and ka (legacy) for compatibility
-				c.sendMessage(&WSMessage{Type: MessageTypePing})
-				c.sendMessage(&WSMessage{Type: MessageTypeConnectionKeepAlive})
+			case <-ticker.C:
+				// Send ping (graphql-ws) and/or ka (legacy) depending on the protocol
+				if c.sendsModern() {
+					c.sendMessage(&WSMessage{Type: MessageTypePing})
+				}
+				if c.sendsLegacy() {
+					c.sendMessage(&WSMessage{Type: MessageTypeConnectionKeepAlive})
+				}
+				// Ping frames are answered by the client's WebSocket stack, so a
+				// missing pong means the client is gone
+				if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.manager.keepAlive)); err != nil {
+					c.cancel()
+				}
 			case <-c.ctx.Done():
-				c.pingTicker.Stop()
+				ticker.Stop()
 				return
 			}
 		}
@@ -340,6 +538,22 @@ func (c *Connection) handleSubscribe(msg *WSMessage) {
 
 	variables, _ := msg.Payload["variables"].(map[string]interface{})
 
+	// Enforce the per-connection subscription limit
+	if max := c.manager.maxSubscriptions; max > 0 {
+		c.mu.RLock()
+		active := len(c.subscriptions)
+		c.mu.RUnlock()
+		if active >= max {
+			c.sendError(msg.ID, fmt.Sprintf("Too many subscriptions: maximum %d per connection", max))
+			return
+		}
+	}
+
+	if c.manager.coalesce {
+		c.subscribeCoalesced(msg.ID, query, variables)
+		return
+	}
+
 	// Create subscription context (can be canceled independently)
 	subCtx, cancel := context.WithCancel(c.ctx)
 
@@ -349,11 +563,25 @@ func (c *Connection) handleSubscribe(msg *WSMessage) {
 	c.mu.Unlock()
 
 	// Execute subscription
-	go c.executeSubscription(subCtx, msg.ID, query, variables)
+	subscriptionIDs := []string{msg.ID}
+	go c.executeSubscription(subCtx, func(done bool) []string {
+		if done && subCtx.Err() == nil {
+			// Ended by the server; a completed subscription was already removed
+			c.mu.Lock()
+			delete(c.subscriptions, msg.ID)
+			c.mu.Unlock()
+		}
+		return subscriptionIDs
+	}, query, variables)
 }
 
 // executeSubscription runs the GraphQL subscription and sends events to the client.
-func (c *Connection) executeSubscription(ctx context.Context, subscriptionID, query string, variables map[string]interface{}) {
+// subscriptionIDs returns the subscriptions receiving the events; done is true once
+// the subscription has ended.
+func (c *Connection) executeSubscription(ctx context.Context, subscriptionIDs func(done bool) []string, query string, variables map[string]interface{}) {
+	// Make server-wide subscription defaults available to subscription resolvers
+	ctx = WithSubscriptionDefaults(ctx, c.manager.subDefaults)
+
 	// Execute GraphQL subscription
 	params := graphql.Params{
 		Schema:         *c.manager.schema,
@@ -371,31 +599,115 @@ func (c *Connection) executeSubscription(ctx context.Context, subscriptionID, qu
 		case result, ok := <-resultChannel:
 			if !ok {
 				// Channel closed - subscription complete
-				c.sendComplete(subscriptionID)
+				for _, subscriptionID := range subscriptionIDs(true) {
+					c.sendComplete(subscriptionID)
+				}
 				return
 			}
 
-			// Check for errors
-			if len(result.Errors) > 0 {
-				for _, err := range result.Errors {
-					c.sendError(subscriptionID, err.Error())
+			for _, subscriptionID := range subscriptionIDs(false) {
+				// Check for errors
+				if len(result.Errors) > 0 {
+					for _, err := range result.Errors {
+						c.sendError(subscriptionID, err.Error())
+					}
+					continue
 				}
-				continue
-			}
 
-			// Send event data to client
-			if result.Data != nil {
-				c.sendNext(subscriptionID, result.Data)
+				// Send event data to client
+				if result.Data != nil {
+					c.sendNext(subscriptionID, result.Data)
+				}
 			}
 
 		case <-ctx.Done():
 			// Subscription canceled
-			c.sendComplete(subscriptionID)
+			for _, subscriptionID := range subscriptionIDs(true) {
+				c.sendComplete(subscriptionID)
+			}
 			return
 		}
 	}
 }
 
+// coalescedSubscription is an execution shared by identical subscriptions of a connection
+type coalescedSubscription struct {
+	ids    []string
+	cancel context.CancelFunc
+}
+
+// subscribeCoalesced starts a subscription, joining the execution of an identical
+// subscription of the connection when there is one. The execution is canceled when
+// its last subscription completes.
+func (c *Connection) subscribeCoalesced(subscriptionID, query string, variables map[string]interface{}) {
+	key := subscriptionKey(query, variables)
+
+	c.mu.Lock()
+	shared, exists := c.coalesced[key]
+	if !exists {
+		shared = &coalescedSubscription{}
+		c.coalesced[key] = shared
+	}
+	shared.ids = append(shared.ids, subscriptionID)
+	c.subscriptions[subscriptionID] = c.leaveCoalesced(key, shared, subscriptionID)
+	if exists {
+		c.mu.Unlock()
+		return
+	}
+	subCtx, cancel := context.WithCancel(c.ctx)
+	shared.cancel = cancel
+	c.mu.Unlock()
+
+	go c.executeSubscription(subCtx, func(done bool) []string {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if done && c.coalesced[key] == shared {
+			// Later identical subscriptions start a new execution
+			delete(c.coalesced, key)
+		}
+		if done && subCtx.Err() == nil {
+			// Ended by the server: the subscriptions no longer count as active
+			for _, id := range shared.ids {
+				delete(c.subscriptions, id)
+			}
+		}
+		return append([]string(nil), shared.ids...)
+	}, query, variables)
+}
+
+// leaveCoalesced returns the cancel function of a coalesced subscription, which is
+// called without c.mu held. It completes the subscription and cancels the shared
+// execution when no other subscription uses it.
+func (c *Connection) leaveCoalesced(key string, shared *coalescedSubscription, subscriptionID string) context.CancelFunc {
+	return func() {
+		c.mu.Lock()
+		for i, id := range shared.ids {
+			if id == subscriptionID {
+				shared.ids = append(shared.ids[:i], shared.ids[i+1:]...)
+				break
+			}
+		}
+		last := len(shared.ids) == 0
+		if last && c.coalesced[key] == shared {
+			delete(c.coalesced, key)
+		}
+		c.mu.Unlock()
+
+		// Sending can block on a full message channel, so it happens outside the lock
+		c.sendComplete(subscriptionID)
+		if last {
+			shared.cancel()
+		}
+	}
+}
+
+// subscriptionKey identifies identical subscriptions by their query and variables
+func subscriptionKey(query string, variables map[string]interface{}) string {
+	// Map keys are marshaled in sorted order, so equal variables give equal keys
+	encoded, _ := json.Marshal(variables)
+	return query + "\x00" + string(encoded)
+}
+
 // handleComplete stops an active subscription.
 func (c *Connection) handleComplete(msg *WSMessage) {
 	if msg.ID == "" {
@@ -403,11 +715,14 @@ func (c *Connection) handleComplete(msg *WSMessage) {
 	}
 
 	c.mu.Lock()
-	if cancel, exists := c.subscriptions[msg.ID]; exists {
+	cancel, exists := c.subscriptions[msg.ID]
+	delete(c.subscriptions, msg.ID)
+	c.mu.Unlock()
+
+	// Cancel functions of coalesced subscriptions take the lock
+	if exists {
 		cancel()
-		delete(c.subscriptions, msg.ID)
 	}
-	c.mu.Unlock()
 }
 
 // sendMessage sends a message to the client.
@@ -418,26 +733,30 @@ func (c *Connection) sendMessage(msg *WSMessage) {
 	}
 }
 
-// sendNext sends a subscription event to the client.
-// Sends both "next" (graphql-ws) and "data" (legacy) for compatibility.
+// sendNext sends a subscription event to the client: "next" for graphql-ws, "data"
+// for the legacy protocol, and both when no subprotocol was negotiated.
 func (c *Connection) sendNext(subscriptionID string, data interface{}) {
 	payload := map[string]interface{}{
 		"data": data,
 	}
 
 	// Send using new protocol (graphql-ws)
-	c.sendMessage(&WSMessage{
-		ID:      subscriptionID,
-		Type:    MessageTypeNext,
-		Payload: payload,
-	})
+	if c.sendsModern() {
+		c.sendMessage(&WSMessage{
+			ID:      subscriptionID,
+			Type:    MessageTypeNext,
+			Payload: payload,
+		})
+	}
 
-	// Also send using legacy protocol (subscriptions-transport-ws) for older clients
-	c.sendMessage(&WSMessage{
-		ID:      subscriptionID,
-		Type:    MessageTypeData,
-		Payload: payload,
-	})
+	// Send using legacy protocol (subscriptions-transport-ws) for older clients
+	if c.sendsLegacy() {
+		c.sendMessage(&WSMessage{
+			ID:      subscriptionID,
+			Type:    MessageTypeData,
+			Payload: payload,
+		})
+	}
 }
 
 // sendError sends an error message to the client.
@@ -467,19 +786,23 @@ func (c *Connection) sendComplete(subscriptionID string) {
 func (c *Connection) cleanup() {
 	// Cancel all subscriptions
 	c.mu.Lock()
-	for _, cancel := range c.subscriptions {
-		cancel()
-	}
+	subscriptions := c.subscriptions
 	c.subscriptions = make(map[string]context.CancelFunc)
 	c.mu.Unlock()
+	for _, cancel := range subscriptions {
+		cancel()
+	}
 
 	// Stop ping ticker
-	if c.pingTicker != nil {
-		c.pingTicker.Stop()
+	c.mu.RLock()
+	ticker := c.pingTicker
+	c.mu.RUnlock()
+	if ticker != nil {
+		ticker.Stop()
 	}
 
-	// Close message channel
-	close(c.messageChan)
+	// The message channel stays open: subscription goroutines may still be sending,
+	// and they return once the connection context is canceled
 
 	// Close WebSocket connection
 	c.ws.Close()
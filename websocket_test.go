@@ -0,0 +1,451 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type WebSocketTestMessage struct {
+	Text string `json:"text"`
+}
+
+// dialWebSocketTest opens an initialized graphql-ws connection to a subscription handler
+func dialWebSocketTest(t *testing.T, params WebSocketParams) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(NewWebSocketHandler(params))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ws.Close() })
+
+	if err := ws.WriteJSON(WSMessage{Type: MessageTypeConnectionInit}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if msg := readWebSocketTestMessage(t, ws, MessageTypeConnectionAck); msg == nil {
+		t.Fatal("Expected connection_ack")
+	}
+	return ws
+}
+
+// readWebSocketTestMessage reads messages until one of the given type arrives
+func readWebSocketTestMessage(t *testing.T, ws *websocket.Conn, messageType string) *WSMessage {
+	t.Helper()
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg WSMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			t.Fatalf("Timed out waiting for %s: %v", messageType, err)
+		}
+		if msg.Type == messageType {
+			return &msg
+		}
+	}
+}
+
+// waitForSubscribers waits until the topic has want subscribers
+func waitForSubscribers(t *testing.T, pubsub *InMemoryPubSub, topic string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for pubsub.SubscriberCount(topic) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d subscribers, got %d", want, pubsub.SubscriberCount(topic))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocket_CoalesceSubscriptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		coalesce        bool
+		wantSubscribers int
+	}{
+		{name: "coalesced", coalesce: true, wantSubscribers: 1},
+		{name: "separate", coalesce: false, wantSubscribers: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pubsub := NewInMemoryPubSub()
+			defer pubsub.Close()
+
+			sub := NewSubscription[WebSocketTestMessage]("webSocketTestMessages").
+				WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "messages" }).
+				BuildSubscription()
+			schema, err := NewSchemaBuilder(SchemaBuilderParams{
+				QueryFields:        []QueryField{getDefaultHelloQuery()},
+				SubscriptionFields: []SubscriptionField{sub},
+			}).Build()
+			if err != nil {
+				t.Fatalf("Failed to build schema: %v", err)
+			}
+
+			ws := dialWebSocketTest(t, WebSocketParams{
+				Schema:                &schema,
+				PubSub:                pubsub,
+				CoalesceSubscriptions: tt.coalesce,
+			})
+
+			query := map[string]interface{}{"query": "subscription { webSocketTestMessages { text } }"}
+			for _, id := range []string{"1", "2"} {
+				if err := ws.WriteJSON(WSMessage{ID: id, Type: MessageTypeSubscribe, Payload: query}); err != nil {
+					t.Fatalf("WriteJSON() error = %v", err)
+				}
+			}
+			waitForSubscribers(t, pubsub, "messages", tt.wantSubscribers)
+			time.Sleep(20 * time.Millisecond)
+			if got := pubsub.SubscriberCount("messages"); got != tt.wantSubscribers {
+				t.Fatalf("Expected %d upstream subscribers, got %d", tt.wantSubscribers, got)
+			}
+
+			if err := pubsub.Publish(context.Background(), "messages", WebSocketTestMessage{Text: "hello"}); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			received := make(map[string]bool)
+			for len(received) < 2 {
+				msg := readWebSocketTestMessage(t, ws, MessageTypeNext)
+				data := msg.Payload["data"].(map[string]interface{})["webSocketTestMessages"].(map[string]interface{})
+				if data["text"] != "hello" {
+					t.Errorf("Unexpected event %v", data)
+				}
+				received[msg.ID] = true
+			}
+			if !received["1"] || !received["2"] {
+				t.Errorf("Expected both subscriptions to receive the event, got %v", received)
+			}
+
+			if !tt.coalesce {
+				return
+			}
+
+			// The shared subscription lives until its last subscriber completes
+			_ = ws.WriteJSON(WSMessage{ID: "1", Type: MessageTypeComplete})
+			if msg := readWebSocketTestMessage(t, ws, MessageTypeComplete); msg.ID != "1" {
+				t.Errorf("Expected subscription 1 to complete, got %s", msg.ID)
+			}
+			time.Sleep(20 * time.Millisecond)
+			if got := pubsub.SubscriberCount("messages"); got != 1 {
+				t.Errorf("Expected the upstream subscription to remain, got %d subscribers", got)
+			}
+			_ = ws.WriteJSON(WSMessage{ID: "2", Type: MessageTypeComplete})
+			waitForSubscribers(t, pubsub, "messages", 0)
+		})
+	}
+}
+
+func TestWebSocket_MaxSubscriptionsPerConnection(t *testing.T) {
+	pubsub := NewInMemoryPubSub()
+	defer pubsub.Close()
+
+	sub := NewSubscription[WebSocketTestMessage]("webSocketLimitedMessages").
+		WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "limited" }).
+		BuildSubscription()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{sub},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	ws := dialWebSocketTest(t, WebSocketParams{
+		Schema:                        &schema,
+		PubSub:                        pubsub,
+		MaxSubscriptionsPerConnection: 2,
+	})
+	subscribe := func(id string) {
+		t.Helper()
+		query := map[string]interface{}{"query": "subscription { webSocketLimitedMessages { text } }"}
+		if err := ws.WriteJSON(WSMessage{ID: id, Type: MessageTypeSubscribe, Payload: query}); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+	}
+
+	subscribe("1")
+	subscribe("2")
+	waitForSubscribers(t, pubsub, "limited", 2)
+
+	subscribe("3")
+	msg := readWebSocketTestMessage(t, ws, MessageTypeError)
+	if msg.ID != "3" {
+		t.Fatalf("Expected an error for subscription 3, got %s", msg.ID)
+	}
+	errs := msg.Payload["errors"].([]interface{})
+	if message := errs[0].(map[string]interface{})["message"].(string); !strings.Contains(message, "maximum 2 per connection") {
+		t.Errorf("Unexpected error message %q", message)
+	}
+	if got := pubsub.SubscriberCount("limited"); got != 2 {
+		t.Errorf("Expected the rejected subscription not to start, got %d subscribers", got)
+	}
+
+	// Completing a subscription frees its slot
+	_ = ws.WriteJSON(WSMessage{ID: "1", Type: MessageTypeComplete})
+	if msg := readWebSocketTestMessage(t, ws, MessageTypeComplete); msg.ID != "1" {
+		t.Fatalf("Expected subscription 1 to complete, got %s", msg.ID)
+	}
+	subscribe("4")
+	waitForSubscribers(t, pubsub, "limited", 2)
+}
+
+func TestWebSocket_ConnectionInitTimeout(t *testing.T) {
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields: []QueryField{getDefaultHelloQuery()},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	server := httptest.NewServer(NewWebSocketHandler(WebSocketParams{
+		Schema:                &schema,
+		ConnectionInitTimeout: 50 * time.Millisecond,
+	}))
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer ws.Close()
+
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = ws.ReadMessage()
+	if !websocket.IsCloseError(err, 4408) {
+		t.Fatalf("Expected close code 4408, got %v", err)
+	}
+}
+
+func TestWebSocket_KeepAlive(t *testing.T) {
+	pubsub := NewInMemoryPubSub()
+	defer pubsub.Close()
+
+	sub := NewSubscription[WebSocketTestMessage]("webSocketKeepAliveMessages").
+		WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "keepalive" }).
+		BuildSubscription()
+	schema, err := NewSchemaBuilder(SchemaBuilderParams{
+		QueryFields:        []QueryField{getDefaultHelloQuery()},
+		SubscriptionFields: []SubscriptionField{sub},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+
+	ws := dialWebSocketTest(t, WebSocketParams{
+		Schema:            &schema,
+		PubSub:            pubsub,
+		KeepAliveInterval: 50 * time.Millisecond,
+	})
+	readWebSocketTestMessage(t, ws, MessageTypePing)
+
+	query := map[string]interface{}{"query": "subscription { webSocketKeepAliveMessages { text } }"}
+	if err := ws.WriteJSON(WSMessage{ID: "1", Type: MessageTypeSubscribe, Payload: query}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	waitForSubscribers(t, pubsub, "keepalive", 1)
+
+	// A client that stops reading never answers the ping frames; the server closes
+	// the connection and stops its subscriptions
+	waitForSubscribers(t, pubsub, "keepalive", 0)
+}
+
+func TestWebSocket_Subprotocols(t *testing.T) {
+	tests := []struct {
+		name        string
+		subprotocol string
+		subscribe   string
+		stop        string
+		wantEvent   string
+		wantPing    string
+	}{
+		{
+			name:        "graphql-transport-ws",
+			subprotocol: SubprotocolGraphQLTransportWS,
+			subscribe:   MessageTypeSubscribe,
+			stop:        MessageTypeComplete,
+			wantEvent:   MessageTypeNext,
+			wantPing:    MessageTypePing,
+		},
+		{
+			name:        "graphql-ws",
+			subprotocol: SubprotocolGraphQLWS,
+			subscribe:   MessageTypeStart,
+			stop:        MessageTypeStop,
+			wantEvent:   MessageTypeData,
+			wantPing:    MessageTypeConnectionKeepAlive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pubsub := NewInMemoryPubSub()
+			defer pubsub.Close()
+
+			sub := NewSubscription[WebSocketTestMessage]("webSocketProtocolMessages").
+				WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "protocol" }).
+				BuildSubscription()
+			schema, err := NewSchemaBuilder(SchemaBuilderParams{
+				QueryFields:        []QueryField{getDefaultHelloQuery()},
+				SubscriptionFields: []SubscriptionField{sub},
+			}).Build()
+			if err != nil {
+				t.Fatalf("Failed to build schema: %v", err)
+			}
+			server := httptest.NewServer(NewWebSocketHandler(WebSocketParams{
+				Schema:            &schema,
+				PubSub:            pubsub,
+				KeepAliveInterval: 50 * time.Millisecond,
+			}))
+			defer server.Close()
+
+			dialer := websocket.Dialer{Subprotocols: []string{tt.subprotocol}}
+			ws, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer ws.Close()
+			if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != tt.subprotocol {
+				t.Fatalf("Expected subprotocol %s, got %q", tt.subprotocol, got)
+			}
+
+			// read returns the next message, skipping keep-alive messages
+			read := func() WSMessage {
+				t.Helper()
+				_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+				for {
+					var msg WSMessage
+					if err := ws.ReadJSON(&msg); err != nil {
+						t.Fatalf("ReadJSON() error = %v", err)
+					}
+					if msg.Type != MessageTypePing && msg.Type != MessageTypeConnectionKeepAlive {
+						return msg
+					}
+					if msg.Type != tt.wantPing {
+						t.Errorf("Unexpected keep-alive message %s", msg.Type)
+					}
+				}
+			}
+
+			_ = ws.WriteJSON(WSMessage{Type: MessageTypeConnectionInit})
+			if msg := read(); msg.Type != MessageTypeConnectionAck {
+				t.Fatalf("Expected connection_ack, got %s", msg.Type)
+			}
+			readWebSocketTestMessage(t, ws, tt.wantPing)
+
+			query := map[string]interface{}{"query": "subscription { webSocketProtocolMessages { text } }"}
+			_ = ws.WriteJSON(WSMessage{ID: "1", Type: tt.subscribe, Payload: query})
+			waitForSubscribers(t, pubsub, "protocol", 1)
+			if err := pubsub.Publish(context.Background(), "protocol", WebSocketTestMessage{Text: "hello"}); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if msg := read(); msg.Type != tt.wantEvent || msg.ID != "1" {
+				t.Fatalf("Expected %s for subscription 1, got %s %s", tt.wantEvent, msg.Type, msg.ID)
+			}
+
+			_ = ws.WriteJSON(WSMessage{ID: "1", Type: tt.stop})
+			if msg := read(); msg.Type != MessageTypeComplete {
+				t.Fatalf("Expected complete, got %s", msg.Type)
+			}
+			waitForSubscribers(t, pubsub, "protocol", 0)
+		})
+	}
+}
+
+func TestNewHTTP_WebSocketTokenSources(t *testing.T) {
+	pubsub := NewInMemoryPubSub()
+	defer pubsub.Close()
+
+	sub := NewSubscription[WebSocketTestMessage]("webSocketAuthMessages").
+		WithSubscriptionTopic(pubsub, func(p ResolveParams) string { return "auth" }).
+		BuildSubscription()
+
+	tokens := make(chan string, 1)
+	server := httptest.NewServer(NewHTTP(&GraphContext{
+		SchemaParams: &SchemaBuilderParams{
+			QueryFields:        []QueryField{getDefaultHelloQuery()},
+			SubscriptionFields: []SubscriptionField{sub},
+		},
+		EnableSubscriptions: true,
+		PubSub:              pubsub,
+		TokenExtractorFn:    ChainExtractors(ExtractBearerToken, ExtractCookieToken("session")),
+		UserDetailsFn: func(ctx context.Context, token string) (context.Context, interface{}, error) {
+			tokens <- token
+			return ctx, map[string]interface{}{"token": token}, nil
+		},
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		header    string
+		cookie    string
+		query     string
+		payload   map[string]interface{}
+		wantToken string
+	}{
+		{name: "header", header: "Bearer header-token", wantToken: "header-token"},
+		{name: "cookie", cookie: "cookie-token", wantToken: "cookie-token"},
+		{name: "query", query: "?token=query-token", wantToken: "query-token"},
+		{name: "init payload", payload: map[string]interface{}{"authorization": "payload-token"}, wantToken: "payload-token"},
+		{name: "init payload with bearer", payload: map[string]interface{}{"Authorization": "Bearer payload-token"}, wantToken: "payload-token"},
+		{
+			name:      "header before query and payload",
+			header:    "Bearer header-token",
+			query:     "?token=query-token",
+			payload:   map[string]interface{}{"authorization": "payload-token"},
+			wantToken: "header-token",
+		},
+		{
+			name:      "cookie before query",
+			cookie:    "cookie-token",
+			query:     "?token=query-token",
+			wantToken: "cookie-token",
+		},
+		{
+			name:      "query before payload",
+			query:     "?token=query-token",
+			payload:   map[string]interface{}{"authorization": "payload-token"},
+			wantToken: "query-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				header.Set("Cookie", "session="+tt.cookie)
+			}
+			dialer := websocket.Dialer{Subprotocols: []string{SubprotocolGraphQLTransportWS}}
+			ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+tt.query, header)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer ws.Close()
+
+			if err := ws.WriteJSON(WSMessage{Type: MessageTypeConnectionInit, Payload: tt.payload}); err != nil {
+				t.Fatalf("WriteJSON() error = %v", err)
+			}
+			readWebSocketTestMessage(t, ws, MessageTypeConnectionAck)
+
+			select {
+			case token := <-tokens:
+				if token != tt.wantToken {
+					t.Errorf("Expected token %q, got %q", tt.wantToken, token)
+				}
+			default:
+				t.Fatal("Expected the connection to be authenticated")
+			}
+		})
+	}
+}